@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"fmt"
 	"go/types"
 	"reflect"
 	"strings"
@@ -51,192 +52,72 @@ func (n *{{.MainStructName}}) GetDisplayFieldFromColumnTag(columnTag string) (pr
 	return printer.DisplayField{}, fmt.Errorf("column tag '%s' not found. Valid tags are %v", columnTag, Get{{.MainStructName}}ColumnTags())
 }
 
-{{- range .IntAttrs }}
+{{- range .SliceAccessors }}
 
-func (n *{{.StructName}}) Get{{.AttributeName}}() printer.DisplayField {
-	return printer.DisplayField{
-		DisplayName: "{{.DisplayName}}",
-		ColumnTag: "{{.ColumnTagValue}}",
-		Value: func() string {
-			str := strconv.Itoa(n.{{.AttributeName}})
-			{{- if .RedTexts }}
-				{{- range .RedTexts }}
-				if str == "{{.}}" {
-					return printer.RedText(str)
-				}
-				{{- end}}
-			{{- end}}
-			{{- if .GreenTexts }}
-				{{- range .GreenTexts }}
-				if str == "{{.}}" {
-					return printer.GreenText(str)
-				}
-				{{- end}}
-			{{- end}}
-			{{- if .YellowTexts }}
-				{{- range .YellowTexts }}
-				if str == "{{.}}" {
-					return printer.YellowText(str)
-				}
-				{{- end}}
-			{{- end}}
-			return str
-		},
+// {{.Slice}}At returns the element i of {{.Slice}}, nil if out of range.
+func (n *{{.StructName}}) {{.Slice}}At(i int) *{{.ElemType}} {
+	if n == nil || i < 0 || i >= len(n.{{.Slice}}) {
+		return nil
 	}
+	return {{if not .PointerElems}}&{{end}}n.{{.Slice}}[i]
 }
-{{- end}}
-
-{{- range .BoolAttrs }}
 
-func (n *{{.StructName}}) Get{{.AttributeName}}() printer.DisplayField {
-	return printer.DisplayField{
-		DisplayName: "{{.DisplayName}}",
-		ColumnTag: "{{.ColumnTagValue}}",
-		Value: func() string {
-			str := strconv.FormatBool(n.{{.AttributeName}})
-			{{- if .RedTexts }}
-				{{- range .RedTexts }}
-				if str == "{{.}}" {
-					return printer.RedText(str)
-				}
-				{{- end}}
-			{{- end}}
-			{{- if .GreenTexts }}
-				{{- range .GreenTexts }}
-				if str == "{{.}}" {
-					return printer.GreenText(str)
-				}
-				{{- end}}
-			{{- end}}
-			{{- if .YellowTexts }}
-				{{- range .YellowTexts }}
-				if str == "{{.}}" {
-					return printer.YellowText(str)
-				}
-				{{- end}}
-			{{- end}}
-			return str
-		},
-	}
+// {{.Slice}}First returns the first element of {{.Slice}}, nil if empty.
+func (n *{{.StructName}}) {{.Slice}}First() *{{.ElemType}} {
+	return n.{{.Slice}}At(0)
 }
-{{- end}}
 
-{{- range .StrArrayAttrs }}
-
-func (n *{{.StructName}}) Get{{.AttributeName}}() printer.DisplayField {
-
-	// Sort the array to make the output deterministic
-	sort.Strings(n.{{.AttributeName}})
-	return printer.DisplayField{
-		DisplayName: "{{.DisplayName}}",
-		ColumnTag: "{{.ColumnTagValue}}",
-		Value: func() string {
-			str := strings.Join(n.{{.AttributeName}}, "\n")
-			{{- if .RedTexts }}
-				{{- range .RedTexts }}
-				if slices.Contains(n.{{.AttributeName}}, "{{.}}") {
-					return printer.RedText(str)
-				}
-				{{- end}}
-			{{- end}}
-			{{- if .GreenTexts }}
-				{{- range .GreenTexts }}
-				if slices.Contains(n.{{.AttributeName}}, "{{.}}") {
-					return printer.GreenText(str)
-				}
-				{{- end}}
-			{{- end}}
-			{{- if .YellowTexts }}
-				{{- range .YellowTexts }}
-				if slices.Contains(n.{{.AttributeName}}, "{{.}}") {
-					return printer.YellowText(str)
-				}
-				{{- end}}
-			{{- end}}
-			return str
-		},
+// {{.Slice}}Last returns the last element of {{.Slice}}, nil if empty.
+func (n *{{.StructName}}) {{.Slice}}Last() *{{.ElemType}} {
+	if n == nil {
+		return nil
 	}
+	return n.{{.Slice}}At(len(n.{{.Slice}}) - 1)
 }
 {{- end}}
 
-{{- range .StrAttrs }}
+{{- range .DisplayAttrs }}
+{{- $attr := . }}
 
 func (n *{{.StructName}}) Get{{.AttributeName}}() printer.DisplayField {
 	return printer.DisplayField{
 		DisplayName: "{{.DisplayName}}",
 		ColumnTag: "{{.ColumnTagValue}}",
 		Value: func() string {
-			str := n.{{.AttributeName}}
-			{{- if .RedTexts }}
-				{{- range .RedTexts }}
-				if str == "{{.}}" {
-					return printer.RedText(str)
-				}
-				{{- end}}
+			if n == nil {
+				return printer.UnsetValue
+			}
+			{{.ValueCode}}
+			{{- range .RedTexts }}
+			if {{if $attr.List}}slices.Contains(values, "{{.}}"){{else}}str == "{{.}}"{{end}} {
+				return printer.RedText(str)
+			}
 			{{- end}}
-			{{- if .GreenTexts }}
-				{{- range .GreenTexts }}
-				if str == "{{.}}" {
-					return printer.GreenText(str)
-				}
-				{{- end}}
+			{{- range .GreenTexts }}
+			if {{if $attr.List}}slices.Contains(values, "{{.}}"){{else}}str == "{{.}}"{{end}} {
+				return printer.GreenText(str)
+			}
 			{{- end}}
-			{{- if .YellowTexts }}
-				{{- range .YellowTexts }}
-				if str == "{{.}}" {
-					return printer.YellowText(str)
-				}
-				{{- end}}
+			{{- range .YellowTexts }}
+			if {{if $attr.List}}slices.Contains(values, "{{.}}"){{else}}str == "{{.}}"{{end}} {
+				return printer.YellowText(str)
+			}
 			{{- end}}
 			return str
 		},
 	}
 }
 {{- end}}
-
-{{- range .TimeDurationAttrs }}
-
-func (n *{{.StructName}}) Get{{.AttributeName}}() printer.DisplayField {
-	return printer.DisplayField{
-		DisplayName: "{{.DisplayName}}",
-		ColumnTag: "{{.ColumnTagValue}}",
-		Value: func() string {
-			str := duration.HumanDuration(n.{{.AttributeName}})
-			return str
-		},
-	}
-}
-{{- end}}
-
-{{- range .TimeTimeAttrs }}
-
-func (n *{{.StructName}}) Get{{.AttributeName}}() printer.DisplayField {
-	return printer.DisplayField{
-		DisplayName: "{{.DisplayName}}",
-		ColumnTag: "{{.ColumnTagValue}}",
-		Value: func() string {
-			str := n.{{.AttributeName}}.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.{{.AttributeName}})
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
-			return str
-		},
-	}
-}
-{{- end}}
 `
 
 type body struct {
-	PkgName           string
-	MainStructName    string
-	Imports           []string
-	IntAttrs          []attributeParams
-	BoolAttrs         []attributeParams
-	StrAttrs          []attributeParams
-	StrArrayAttrs     []attributeParams
-	TimeDurationAttrs []attributeParams
-	TimeTimeAttrs     []attributeParams
-	ColumnAttrs       []attributeParams
+	PkgName        string
+	MainStructName string
+	Imports        []string
+	DisplayAttrs   []attributeParams
+	ColumnAttrs    []attributeParams
+	SliceAccessors []attributeParams
+	DetailSections []detailSection
 }
 
 type attributeParams struct {
@@ -251,6 +132,16 @@ type attributeParams struct {
 	GreenTexts             []string
 	YellowTexts            []string
 	DerivedAttributeGetter string
+	// ValueCode and List render the value of the attribute, see valueCode.
+	ValueCode string
+	List      bool
+	// Slice is set for the aggregates of a slice of structs: the count of the elements, or the
+	// values of the element field ElemField joined. ElemType is the element struct, PointerElems
+	// set when the elements are pointers.
+	Slice        string
+	ElemField    string
+	ElemType     string
+	PointerElems bool
 }
 
 func (g *Generator) getBody() (body, error) {
@@ -259,13 +150,10 @@ func (g *Generator) getBody() (body, error) {
 		"github.com/msanath/gondolf/pkg/duration",
 		"strings",
 	}
-	strAttrs := []attributeParams{}
 	columnAttrs := []attributeParams{}
-	intAttrs := []attributeParams{}
-	boolAttrs := []attributeParams{}
-	strArrayAttrs := []attributeParams{}
-	timeDurationAttrs := []attributeParams{}
-	timeTimeAttrs := []attributeParams{}
+	displayAttrs := []attributeParams{}
+	sliceAccessors := []attributeParams{}
+	columns := map[string]attributeParams{}
 
 	s := g.structType
 	attrs := getAttrs(s, g.structName, []string{})
@@ -278,42 +166,40 @@ func (g *Generator) getBody() (body, error) {
 		}
 		alreadyAdded[attr.StructName+attr.AttributeName] = true
 
-		if attr.ColumnTagValue != "" {
+		// The attributes of the elements of slices are not reachable from the main struct, and
+		// have no columns.
+		if attr.ColumnTagValue != "" && attr.DerivedAttributeGetter != "" {
+			if other, ok := columns[attr.ColumnTagValue]; ok {
+				return body{}, fmt.Errorf("field %s.%s: column tag %s is already used by field %s.%s",
+					attr.StructName, attr.AttributeName, attr.ColumnTagValue, other.StructName, other.AttributeName)
+			}
+			columns[attr.ColumnTagValue] = attr
 			columnAttrs = append(columnAttrs, attr)
 		}
 
+		if attr.Slice != "" && attr.ElemField == "" {
+			sliceAccessors = append(sliceAccessors, attr)
+		}
+
 		if attr.DisplayName != "" {
-			switch {
-			case isTimeDuration(attr.Type):
-				timeDurationAttrs = append(timeDurationAttrs, attr)
-			case isTimeTime(attr.Type):
-				timeTimeAttrs = append(timeTimeAttrs, attr)
-			case isNumber(attr.Type):
-				intAttrs = append(intAttrs, attr)
-			case isBool(attr.Type):
-				boolAttrs = append(boolAttrs, attr)
-			case isStringArray(attr.Type):
-				strArrayAttrs = append(strArrayAttrs, attr)
-			case isString(attr.Type):
-				strAttrs = append(strAttrs, attr)
-			default:
-				panic("unsupported type" + attr.Type.String())
+			code, list, err := attr.valueCode()
+			if err != nil {
+				return body{}, fmt.Errorf("field %s.%s: %w", attr.StructName, attr.AttributeName, err)
 			}
+			attr.ValueCode = code
+			attr.List = list
+			displayAttrs = append(displayAttrs, attr)
 		}
-
 	}
 
 	return body{
-		PkgName:           g.pkgName,
-		MainStructName:    g.structName,
-		Imports:           imports,
-		StrAttrs:          strAttrs,
-		StrArrayAttrs:     strArrayAttrs,
-		BoolAttrs:         boolAttrs,
-		ColumnAttrs:       columnAttrs,
-		IntAttrs:          intAttrs,
-		TimeDurationAttrs: timeDurationAttrs,
-		TimeTimeAttrs:     timeTimeAttrs,
+		PkgName:        g.pkgName,
+		MainStructName: g.structName,
+		Imports:        imports,
+		DisplayAttrs:   displayAttrs,
+		ColumnAttrs:    columnAttrs,
+		SliceAccessors: sliceAccessors,
+		DetailSections: getDetailSections(s, "", "", nil),
 	}, nil
 }
 
@@ -327,7 +213,7 @@ func getAttrs(s *types.Struct, structName string, parents []string) []attributeP
 		}
 
 		// If the field is a nested struct (or pointer/slice of one), process it recursively.
-		if nested, ok := tryExtractNestedAttrs(f, parents); ok {
+		if nested, ok := tryExtractNestedAttrs(f, tags, structName, parents); ok {
 			attrs = append(attrs, nested...)
 			continue
 		}
@@ -360,7 +246,7 @@ func getAttrs(s *types.Struct, structName string, parents []string) []attributeP
 
 // tryExtractNestedAttrs checks whether f’s type is a struct (or pointer/slice thereof)
 // and, if so, calls getAttrs recursively.
-func tryExtractNestedAttrs(f *types.Var, parents []string) ([]attributeParams, bool) {
+func tryExtractNestedAttrs(f *types.Var, tags reflect.StructTag, structName string, parents []string) ([]attributeParams, bool) {
 	typ := f.Type()
 	// Skip time.Time even though it is a struct.
 	if typ.String() == "time.Time" {
@@ -379,21 +265,77 @@ func tryExtractNestedAttrs(f *types.Var, parents []string) ([]attributeParams, b
 		}
 
 	case *types.Slice:
-		switch elem := t.Elem().Underlying().(type) {
-		case *types.Struct:
-			structName := lastPart(t.Elem().String())
-			// Note: for a slice of struct, we append the struct name.
-			return getAttrs(elem, structName, append(parents, structName)), true
-		case *types.Pointer:
-			if st, ok := elem.Elem().Underlying().(*types.Struct); ok {
-				structName := lastPart(elem.Elem().String())
-				return getAttrs(st, structName, append(parents, f.Name())), true
-			}
+		if elem, isPointer, ok := nestedStruct(t.Elem()); ok {
+			return getSliceAttrs(f, tags, elem, isPointer, structName, parents), true
 		}
 	}
 	return nil, false
 }
 
+// getSliceAttrs returns the aggregates of the slice of structs f of the struct: the count of the
+// elements, displayed and tagged as f, and the values of each display field of the elements
+// joined, tagged with the column tag of the element field. The attributes of the elements follow,
+// without derived getters as no element is reachable from the main struct.
+func getSliceAttrs(f *types.Var, tags reflect.StructTag, elem *types.Struct, isPointer bool, structName string, parents []string) []attributeParams {
+	elemType := f.Type().Underlying().(*types.Slice).Elem()
+	if isPointer {
+		elemType = elemType.(*types.Pointer).Elem()
+	}
+	elemName := lastPart(elemType.String())
+	elemAttrs := getAttrs(elem, elemName, nil)
+	for i := range elemAttrs {
+		elemAttrs[i].DerivedAttributeGetter = ""
+	}
+	if !f.Exported() {
+		return elemAttrs
+	}
+
+	displayName := tags.Get("displayName")
+	if displayName == "" {
+		displayName = "# " + splitCamelCase(f.Name())
+	}
+	count := attributeParams{
+		StructName:             structName,
+		AttributeName:          f.Name() + "Count",
+		JSONTag:                strings.Split(tags.Get("json"), ",")[0],
+		Type:                   types.Typ[types.Int],
+		ColumnTagValue:         tags.Get("columnTag"),
+		ColumnTagName:          "Column" + toCamelCase(tags.Get("columnTag")),
+		DisplayName:            displayName,
+		DerivedAttributeGetter: makeGetter(f.Name()+"Count", parents),
+		Slice:                  f.Name(),
+		ElemType:               elemName,
+		PointerElems:           isPointer,
+	}
+	attrs := []attributeParams{count}
+
+	for i := 0; i < elem.NumFields(); i++ {
+		if !isDisplayField(elem, i) {
+			continue
+		}
+		ef := elem.Field(i)
+		elemTags := reflect.StructTag(elem.Tag(i))
+		attrs = append(attrs, attributeParams{
+			StructName:             structName,
+			AttributeName:          f.Name() + ef.Name(),
+			JSONTag:                strings.Split(elemTags.Get("json"), ",")[0],
+			Type:                   ef.Type(),
+			ColumnTagValue:         elemTags.Get("columnTag"),
+			ColumnTagName:          "Column" + toCamelCase(elemTags.Get("columnTag")),
+			DisplayName:            elemTags.Get("displayName"),
+			RedTexts:               getTagValues(elemTags, "redTexts"),
+			GreenTexts:             getTagValues(elemTags, "greenTexts"),
+			YellowTexts:            getTagValues(elemTags, "yellowTexts"),
+			DerivedAttributeGetter: makeGetter(f.Name()+ef.Name(), parents),
+			Slice:                  f.Name(),
+			ElemField:              ef.Name(),
+			ElemType:               elemName,
+			PointerElems:           isPointer,
+		})
+	}
+	return append(attrs, elemAttrs...)
+}
+
 // lastPart returns the substring after the last dot.
 // For example, "*middle-earth/gondolf/cligen.DisruptionSummary" becomes "DisruptionSummary".
 func lastPart(s string) string {
@@ -439,27 +381,3 @@ func toCamelCase(input string) string {
 	}
 	return output
 }
-
-func isNumber(f types.Type) bool {
-	return strings.Contains(f.String(), "int") || strings.Contains(f.String(), "float")
-}
-
-func isBool(f types.Type) bool {
-	return strings.Contains(f.String(), "bool")
-}
-
-func isStringArray(f types.Type) bool {
-	return strings.Contains(f.String(), "[]string")
-}
-
-func isString(f types.Type) bool {
-	return strings.Contains(f.String(), "string")
-}
-
-func isTimeDuration(f types.Type) bool {
-	return strings.Contains(f.String(), "time.Duration")
-}
-
-func isTimeTime(f types.Type) bool {
-	return strings.Contains(f.String(), "time.Time")
-}
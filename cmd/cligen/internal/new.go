@@ -1,16 +1,18 @@
 package internal
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"go/types"
-	"os"
-	"os/exec"
 	"reflect"
 	"strings"
 	"text/template"
 
 	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+
+	"github.com/msanath/gondolf/cmd/internal/genfile"
 )
 
 type Generator struct {
@@ -18,9 +20,10 @@ type Generator struct {
 	structName string
 	pkgName    string
 	outputFile string
+	files      *genfile.Writer
 }
 
-func NewGenerator(structName string, pkgName string, outputFile string) (*Generator, error) {
+func NewGenerator(structName string, pkgName string, outputFile string, files *genfile.Writer) (*Generator, error) {
 	pkg, err := getCurrentPackage()
 	if err != nil {
 		return nil, err
@@ -44,6 +47,7 @@ func NewGenerator(structName string, pkgName string, outputFile string) (*Genera
 		structName: structName,
 		outputFile: outputFile,
 		structType: s,
+		files:      files,
 	}, nil
 }
 
@@ -73,55 +77,24 @@ func isValidStructForDisplayGen(s *types.Struct) error {
 }
 
 func (g *Generator) Generate() error {
-	f, err := os.Create(g.outputFile)
-	if err != nil {
-		fmt.Println("Error creating output file:", err)
-		os.Exit(1)
-	}
-	defer f.Close()
-
 	// Write the struct body
 	bodyData, err := g.getBody()
 	if err != nil {
 		return err
 	}
-	bt := template.Must(template.New("body").Parse(bodyTemplate))
-	err = bt.Execute(f, bodyData)
+	var buf bytes.Buffer
+	bt := template.Must(template.New("body").Funcs(template.FuncMap{"lowerFirst": lowerFirst}).Parse(bodyTemplate + renderTemplate))
+	err = bt.Execute(&buf, bodyData)
 	if err != nil {
 		return err
 	}
 
-	return g.tidyGeneratedFile()
-}
-
-func (g *Generator) tidyGeneratedFile() error {
-	// Run "goimports"
-	importsCmd := exec.Command("goimports", "-w", g.outputFile)
-	importsCmd.Stdout = os.Stdout
-	importsCmd.Stderr = os.Stderr
-	if err := importsCmd.Run(); err != nil {
-		fmt.Println("Error running 'goimports':", err)
-		return err
-	}
-
-	// Run "go mod tidy"
-	tidyCmd := exec.Command("go", "mod", "tidy")
-	tidyCmd.Stdout = os.Stdout
-	tidyCmd.Stderr = os.Stderr
-	if err := tidyCmd.Run(); err != nil {
-		fmt.Println("Error running 'go mod tidy':", err)
-		return err
-	}
-
-	// Run "go fmt"
-	fmtCmd := exec.Command("go", "fmt")
-	fmtCmd.Stdout = os.Stdout
-	fmtCmd.Stderr = os.Stderr
-	if err := fmtCmd.Run(); err != nil {
-		fmt.Println("Error running 'go fmt':", err)
-		return err
+	// Resolve and format the imports in memory, as goimports does.
+	content, err := imports.Process(g.outputFile, buf.Bytes(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", g.outputFile, err)
 	}
-	return nil
+	return g.files.WriteFile(g.outputFile, content)
 }
 
 func getCurrentPackage() (*packages.Package, error) {
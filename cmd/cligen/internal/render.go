@@ -0,0 +1,234 @@
+package internal
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// renderTemplate is the template for generating the table and detail views of the main struct.
+const renderTemplate = `
+var {{lowerFirst .MainStructName}}ColumnDisplayNames = map[string]string{
+	{{- range .ColumnAttrs }}
+	{{.ColumnTagName}}: {{printf "%q" .DisplayName}},
+	{{- end}}
+}
+
+// Render{{.MainStructName}}Table prints the items as a table with a column per column tag, headed
+// by the display names of the fields. Empty columnTags print every column.
+func Render{{.MainStructName}}Table(items []{{.MainStructName}}, columnTags []string, opts ...printer.TablePrinterOption) error {
+	if len(columnTags) == 0 {
+		columnTags = Get{{.MainStructName}}ColumnTags()
+	}
+	if err := Validate{{.MainStructName}}ColumnTags(columnTags); err != nil {
+		return err
+	}
+	headers := make([]string, len(columnTags))
+	for i, tag := range columnTags {
+		headers[i] = {{lowerFirst .MainStructName}}ColumnDisplayNames[tag]
+	}
+	rows := make([][]string, len(items))
+	for i := range items {
+		for _, tag := range columnTags {
+			field, err := items[i].GetDisplayFieldFromColumnTag(tag)
+			if err != nil {
+				return err
+			}
+			rows[i] = append(rows[i], field.Value())
+		}
+	}
+	printer.NewPlainTextPrinter().PrintTable(headers, rows, opts...)
+	return nil
+}
+
+// Render{{.MainStructName}}Detail prints the display fields of the item, with a section per nested
+// struct and a table per slice of structs.
+func Render{{.MainStructName}}Detail(item *{{.MainStructName}}) {
+	p := printer.NewPlainTextPrinter()
+{{- range .DetailSections }}
+{{- if .Slice }}
+	if {{if .Guard}}{{.Guard}} && {{end}}len(item{{.Path}}) > 0 {
+		p.PrintEmptyLine()
+		p.PrintHeader({{printf "%q" .Title}})
+		rows := [][]string{}
+{{- if .PointerElems }}
+		for _, elem := range item{{.Path}} {
+			if elem == nil {
+				continue
+			}
+{{- else }}
+		for i := range item{{.Path}} {
+			elem := &item{{.Path}}[i]
+{{- end }}
+			rows = append(rows, []string{
+{{- range .Getters }}
+				elem.{{.}}().Value(),
+{{- end }}
+			})
+		}
+		p.PrintTable([]string{ {{- range $i, $h := .Headers}}{{if $i}}, {{end}}{{printf "%q" $h}}{{end -}} }, rows, printer.WithAlignLeft())
+	}
+{{- else }}
+{{- if .Guard }}
+	if {{.Guard}} {
+{{- end }}
+{{- if .Title }}
+		p.PrintEmptyLine()
+		p.PrintHeader({{printf "%q" .Title}})
+{{- end }}
+{{- $section := . }}
+{{- range .Getters }}
+{{- if $section.Title }}
+		p.PrintDisplayFieldWithIndent(item{{$section.Path}}.{{.}}())
+{{- else }}
+		p.PrintDisplayField(item.{{.}}())
+{{- end }}
+{{- end }}
+{{- if .Guard }}
+	}
+{{- end }}
+{{- end }}
+{{- end }}
+}
+`
+
+// detailSection is a section of the detail view of the main struct: the display fields of the
+// main struct or of a nested struct, or the table of a slice of structs.
+type detailSection struct {
+	// Title heads the section, empty for the main struct.
+	Title string
+	// Path selects the nested struct or slice from the item, e.g. .Incidents.IncidentHistory.
+	Path string
+	// Guard is the condition of the pointers along Path being set, empty if there are none.
+	Guard string
+	// Getters are the getters of the display fields, of the elements for slices, and Headers
+	// their display names.
+	Getters []string
+	Headers []string
+	// Slice is set for slices of structs, printed as tables. PointerElems is set when the
+	// elements are pointers.
+	Slice        bool
+	PointerElems bool
+}
+
+// getDetailSections returns the section of the display fields of the struct, followed by the
+// sections of its nested structs and slices of structs, in field order.
+func getDetailSections(s *types.Struct, title, path string, guards []string) []detailSection {
+	section := detailSection{Title: title, Path: path, Guard: strings.Join(guards, " && ")}
+	section.Getters, section.Headers = displayFields(s)
+	var sections []detailSection
+	if len(section.Getters) > 0 {
+		sections = append(sections, section)
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if reflect.StructTag(s.Tag(i)).Get("doNotGen") == "true" || !f.Exported() {
+			continue
+		}
+		fieldPath := path + "." + f.Name()
+		if elem, isPointer, ok := sliceOfStructs(f.Type()); ok {
+			getters, headers := displayFields(elem)
+			if len(getters) == 0 {
+				continue
+			}
+			sections = append(sections, detailSection{
+				Title:        splitCamelCase(f.Name()),
+				Path:         fieldPath,
+				Guard:        section.Guard,
+				Getters:      getters,
+				Headers:      headers,
+				Slice:        true,
+				PointerElems: isPointer,
+			})
+		} else if st, isPointer, ok := nestedStruct(f.Type()); ok {
+			fieldGuards := guards
+			if isPointer {
+				fieldGuards = append(append([]string{}, guards...), "item"+fieldPath+" != nil")
+			}
+			sections = append(sections, getDetailSections(st, splitCamelCase(f.Name()), fieldPath, fieldGuards)...)
+		}
+	}
+	return sections
+}
+
+// nestedStruct returns the struct of a struct or pointer to struct type, other than time.Time.
+func nestedStruct(typ types.Type) (*types.Struct, bool, bool) {
+	isPointer := false
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+		isPointer = true
+	}
+	if typ.String() == "time.Time" {
+		return nil, false, false
+	}
+	st, ok := typ.Underlying().(*types.Struct)
+	return st, isPointer, ok
+}
+
+// sliceOfStructs returns the element struct of a slice of structs or of pointers to structs.
+func sliceOfStructs(typ types.Type) (*types.Struct, bool, bool) {
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return nil, false, false
+	}
+	return nestedStruct(slice.Elem())
+}
+
+// displayFields returns the getters and display names of the display fields of the struct,
+// without those of its nested structs.
+func displayFields(s *types.Struct) (getters, names []string) {
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		tags := reflect.StructTag(s.Tag(i))
+		if _, _, ok := sliceOfStructs(f.Type()); ok && f.Exported() && tags.Get("doNotGen") != "true" && tags.Get("displayName") != "" {
+			// Slices of structs display the count of their elements.
+			getters = append(getters, "Get"+f.Name()+"Count")
+			names = append(names, tags.Get("displayName"))
+			continue
+		}
+		if !isDisplayField(s, i) {
+			continue
+		}
+		getters = append(getters, "Get"+f.Name())
+		names = append(names, tags.Get("displayName"))
+	}
+	return getters, names
+}
+
+// isDisplayField returns true if the field i of the struct is displayed as a value, rather than
+// as a section or a table.
+func isDisplayField(s *types.Struct, i int) bool {
+	f := s.Field(i)
+	tags := reflect.StructTag(s.Tag(i))
+	if !f.Exported() || tags.Get("doNotGen") == "true" || tags.Get("displayName") == "" {
+		return false
+	}
+	if _, _, ok := sliceOfStructs(f.Type()); ok {
+		return false
+	}
+	_, _, ok := nestedStruct(f.Type())
+	return !ok
+}
+
+// splitCamelCase splits a field name into words, e.g. IncidentHistory becomes Incident History
+// and IPAddress becomes IP Address.
+func splitCamelCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
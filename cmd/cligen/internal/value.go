@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// valueCode returns the statements setting str to the displayed value of the attribute, see
+// valueCode, and of the aggregates of slices.
+func (a attributeParams) valueCode() (string, bool, error) {
+	switch {
+	case a.Slice == "":
+		return valueCode("n."+a.AttributeName, a.Type)
+	case a.ElemField == "":
+		return fmt.Sprintf("str := strconv.Itoa(len(n.%s))", a.Slice), false, nil
+	}
+	// The values of the elements needing statements are computed by function literals, which the
+	// nil checks of the value code return from.
+	elemCode, _, err := valueCode("e."+a.ElemField, a.Type)
+	if err != nil {
+		return "", false, err
+	}
+	loop := fmt.Sprintf("for i := range n.%s {\ne := &n.%s[i]", a.Slice, a.Slice)
+	if a.PointerElems {
+		loop = fmt.Sprintf("for _, e := range n.%s {\nif e == nil {\nvalues = append(values, printer.UnsetValue)\ncontinue\n}", a.Slice)
+	}
+	value := fmt.Sprintf("func() string {\n%s\nreturn str\n}()", elemCode)
+	if expr, ok := strings.CutPrefix(elemCode, "str := "); ok && !strings.Contains(expr, "\n") {
+		value = expr
+	}
+	return fmt.Sprintf(`values := make([]string, 0, len(n.%s))
+%s
+values = append(values, %s)
+}
+str := strings.Join(values, "\n")`, a.Slice, loop, value), true, nil
+}
+
+// valueCode returns the statements setting str to the displayed value of expr, of type typ, an
+// addressable expression such as n.Name. list is set for slices and maps, whose statements also
+// set values to the displayed values of the elements, which the color tags are matched against.
+// Nil pointers and interfaces display as printer.UnsetValue.
+func valueCode(expr string, typ types.Type) (code string, list bool, err error) {
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer:
+		elemCode, list, err := valueCode("*"+expr, t.Elem())
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("if %s == nil {\nreturn printer.UnsetValue\n}\n%s", expr, elemCode), list, nil
+	case *types.Interface:
+		value, err := scalarExpr(expr, typ)
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("if %s == nil {\nreturn printer.UnsetValue\n}\nstr := %s", expr, value), false, nil
+	}
+
+	if value, err := scalarExpr(expr, typ); err == nil {
+		return "str := " + value, false, nil
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Slice:
+		return listCode(expr, t.Elem())
+	case *types.Array:
+		return listCode(expr, t.Elem())
+	case *types.Map:
+		key, err := scalarExpr("k", t.Key())
+		if err != nil {
+			return "", false, err
+		}
+		value, err := scalarExpr("v", t.Elem())
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf(`values := make([]string, 0, len(%s))
+for k, v := range %s {
+values = append(values, %s+"="+%s)
+}
+// Sort the entries to make the output deterministic
+sort.Strings(values)
+str := strings.Join(values, "\n")`, expr, expr, key, value), true, nil
+	}
+	return "", false, fmt.Errorf("unsupported type %s", typ)
+}
+
+// listCode returns the statements displaying a slice or an array of scalars, a value per line.
+func listCode(expr string, elem types.Type) (string, bool, error) {
+	value, err := scalarExpr("e", elem)
+	if err != nil {
+		return "", false, err
+	}
+	code := fmt.Sprintf(`values := make([]string, 0, len(%s))
+for _, e := range %s {
+values = append(values, %s)
+}`, expr, expr, value)
+	if basic, ok := elem.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+		code += "\n// Sort the strings to make the output deterministic\nsort.Strings(values)"
+	}
+	return code + "\nstr := strings.Join(values, \"\\n\")", true, nil
+}
+
+// scalarExpr returns the expression of the displayed string of expr, an addressable expression of
+// a type displayed as a single value: durations, times, fmt.Stringer and encoding.TextMarshaler
+// types, and types of basic underlying types such as named string and int enums.
+func scalarExpr(expr string, typ types.Type) (string, error) {
+	switch typ.String() {
+	case "time.Duration":
+		return fmt.Sprintf("duration.HumanDuration(%s)", expr), nil
+	case "time.Time":
+		return fmt.Sprintf(`%s.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(%s)) + " ago)"`, operand(expr), expr), nil
+	}
+	// Methods with pointer receivers are callable on addressable expressions.
+	if hasMethod(typ, "String", "func() string") {
+		return operand(expr) + ".String()", nil
+	}
+	if hasMethod(typ, "MarshalText", "func() ([]byte, error)") {
+		if types.NewMethodSet(typ).Lookup(nil, "MarshalText") == nil {
+			expr = addressOf(expr)
+		}
+		return fmt.Sprintf("printer.TextValue(%s)", expr), nil
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsString != 0:
+			return convert(expr, typ, "string"), nil
+		case t.Info()&types.IsBoolean != 0:
+			return fmt.Sprintf("strconv.FormatBool(%s)", convert(expr, typ, "bool")), nil
+		case t.Kind() == types.Int && typ == t:
+			return fmt.Sprintf("strconv.Itoa(%s)", expr), nil
+		case t.Info()&types.IsUnsigned != 0:
+			return fmt.Sprintf("strconv.FormatUint(%s, 10)", convert(expr, typ, "uint64")), nil
+		case t.Info()&types.IsInteger != 0:
+			return fmt.Sprintf("strconv.FormatInt(%s, 10)", convert(expr, typ, "int64")), nil
+		case t.Info()&types.IsFloat != 0:
+			return fmt.Sprintf("strconv.FormatFloat(%s, 'f', -1, 64)", convert(expr, typ, "float64")), nil
+		}
+	case *types.Slice:
+		if basic, ok := t.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return fmt.Sprintf("string(%s)", expr), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported type %s", typ)
+}
+
+// hasMethod returns true if the method of the signature is callable on addressable values of typ.
+func hasMethod(typ types.Type, name, signature string) bool {
+	sel := types.NewMethodSet(types.NewPointer(typ)).Lookup(nil, name)
+	if _, isInterface := typ.Underlying().(*types.Interface); isInterface {
+		sel = types.NewMethodSet(typ).Lookup(nil, name)
+	}
+	if sel == nil {
+		return false
+	}
+	return types.TypeString(sel.Type(), nil) == signature
+}
+
+// convert returns expr converted to the basic type, unless already of it.
+func convert(expr string, typ types.Type, basic string) string {
+	if typ.String() == basic {
+		return expr
+	}
+	return fmt.Sprintf("%s(%s)", basic, expr)
+}
+
+// operand returns expr as the operand of a selector, parenthesized if dereferenced.
+func operand(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+// addressOf returns the address of expr.
+func addressOf(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return expr[1:]
+	}
+	return "&" + expr
+}
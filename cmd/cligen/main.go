@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/msanath/gondolf/cmd/cligen/internal"
+	"github.com/msanath/gondolf/cmd/internal/genfile"
 	"github.com/spf13/cobra"
 )
 
@@ -14,11 +15,15 @@ type cliGenOptions struct {
 	StructName string
 	PkgName    string
 	OutputFile string
+
+	// Check compares the generated file with the one on disk instead of writing it, and fails
+	// with a diff when it is stale.
+	Check bool
+	// Stdout prints the generated file instead of writing it.
+	Stdout bool
 }
 
 func main() {
-	fmt.Println(os.Args)
-
 	o := cliGenOptions{}
 
 	cmd := cobra.Command{
@@ -38,6 +43,9 @@ func main() {
 	cmd.Flags().StringVar(&o.OutputFile, "output-file", "", "Name of the output file")
 	cmd.MarkFlagRequired("output-file")
 
+	cmd.Flags().BoolVar(&o.Check, "check", false, "Compare the generated file with the one on disk, print a diff and fail when it is stale")
+	cmd.Flags().BoolVar(&o.Stdout, "stdout", false, "Print the generated file instead of writing it")
+
 	err := cmd.Execute()
 	if err != nil {
 		panic(err)
@@ -45,8 +53,15 @@ func main() {
 }
 
 func (o cliGenOptions) Run(ctx context.Context) error {
+	mode, err := genfile.NewMode(o.Check, o.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	files := genfile.NewWriter(mode, os.Stdout)
+
 	// Get a writer to write to the output file.
-	generator, err := internal.NewGenerator(o.StructName, o.PkgName, o.OutputFile)
+	generator, err := internal.NewGenerator(o.StructName, o.PkgName, o.OutputFile, files)
 	if err != nil {
 		fmt.Println("Unable to initialize generator:", err)
 		os.Exit(1)
@@ -56,5 +71,9 @@ func (o cliGenOptions) Run(ctx context.Context) error {
 		fmt.Println("Error generating statements:", err)
 		os.Exit(1)
 	}
+	if err := files.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	return nil
 }
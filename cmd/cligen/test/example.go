@@ -1,6 +1,9 @@
 package test
 
-import "time"
+import (
+	"encoding/hex"
+	"time"
+)
 
 //go:generate ../../../bin/cligen --struct-name DisplayServiceNode --pkg-name test --output-file example_gen.go
 type DisplayServiceNode struct {
@@ -12,6 +15,8 @@ type DisplayServiceNode struct {
 	Incidents        IncidentSummary    `json:"incidents,omitempty"`
 	NetworkInfo      NetworkInfo        `json:"network_info,omitempty"`
 	ContainerSummary ContainerSummary   `json:"container_summary,omitempty"`
+	Runtime          Runtime            `json:"runtime,omitempty"`
+	Rollout          *Rollout           `json:"rollout,omitempty"`
 }
 
 type ServiceMetadata struct {
@@ -44,7 +49,7 @@ type IncidentSummary struct {
 	OpenIncidents     int        `json:"open_incidents" displayName:"# Open Incidents" columnTag:"open_incidents"`
 	ResolvedIncidents int        `json:"resolved_incidents" displayName:"# Resolved Incidents" columnTag:"resolved_incidents"`
 	ActiveIncidentID  string     `json:"active_incident_id,omitempty" displayName:"Active Incident ID" columnTag:"active_incident_id"`
-	IncidentHistory   []Incident `json:"incident_history,omitempty"`
+	IncidentHistory   []Incident `json:"incident_history,omitempty" displayName:"# Incidents" columnTag:"incident_count"`
 }
 
 type Incident struct {
@@ -53,7 +58,7 @@ type Incident struct {
 	ReportedAt  time.Time `json:"reported_at" displayName:"Reported At"`
 	ResolvedAt  time.Time `json:"resolved_at,omitempty" displayName:"Resolved At"`
 	Description string    `json:"description" displayName:"Description"`
-	Priority    string    `json:"priority" displayName:"Priority" redTexts:"High" yellowTexts:"Medium" greenTexts:"Low"`
+	Priority    string    `json:"priority" displayName:"Priority" redTexts:"High" yellowTexts:"Medium" greenTexts:"Low" columnTag:"incident_priorities"`
 }
 
 type NetworkInfo struct {
@@ -67,7 +72,7 @@ type DeploymentStats struct {
 	DeploymentID string    `json:"deployment_id" displayName:"Deployment ID"`
 	StartTime    time.Time `json:"start_time" displayName:"Start Time"`
 	EndTime      time.Time `json:"end_time" displayName:"End Time"`
-	Status       string    `json:"status" displayName:"Status" greenTexts:"Success" redTexts:"Failed"`
+	Status       string    `json:"status" displayName:"Status" greenTexts:"Success" redTexts:"Failed" columnTag:"deployment_statuses"`
 	Version      string    `json:"version" displayName:"Version"`
 }
 
@@ -85,3 +90,50 @@ type Container struct {
 	MemUsage     string `json:"mem_usage" displayName:"Memory Usage"`
 	RestartCount int    `json:"restart_count" displayName:"Restart Count"`
 }
+
+// ServiceTier is a named string enum.
+type ServiceTier string
+
+// RestartPolicy is a named int enum displayed through its String method.
+type RestartPolicy int
+
+const (
+	RestartAlways RestartPolicy = iota
+	RestartNever
+)
+
+func (p RestartPolicy) String() string {
+	if p == RestartNever {
+		return "Never"
+	}
+	return "Always"
+}
+
+// Checksum is displayed through its MarshalText method.
+type Checksum [4]byte
+
+func (c Checksum) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(c[:])), nil
+}
+
+type Runtime struct {
+	Tier          ServiceTier       `json:"tier" displayName:"Tier" greenTexts:"gold" columnTag:"tier"`
+	Replicas      *int              `json:"replicas,omitempty" displayName:"Replicas" columnTag:"replicas"`
+	Owner         *string           `json:"owner,omitempty" displayName:"Owner" columnTag:"owner"`
+	Timeout       *time.Duration    `json:"timeout,omitempty" displayName:"Timeout" columnTag:"timeout"`
+	Ports         []int32           `json:"ports" displayName:"Ports" columnTag:"ports"`
+	Labels        map[string]string `json:"labels" displayName:"Labels" redTexts:"env=prod" columnTag:"labels"`
+	RestartPolicy RestartPolicy     `json:"restart_policy" displayName:"Restart Policy" redTexts:"Never" columnTag:"restart_policy"`
+	Checksum      Checksum          `json:"checksum" displayName:"Checksum" columnTag:"checksum"`
+	Load          float64           `json:"load" displayName:"Load" columnTag:"load"`
+}
+
+type Rollout struct {
+	Strategy string         `json:"strategy" displayName:"Strategy" columnTag:"rollout_strategy"`
+	Steps    []*RolloutStep `json:"steps,omitempty" displayName:"# Steps" columnTag:"rollout_step_count"`
+}
+
+type RolloutStep struct {
+	Name   string `json:"step_name" displayName:"Step"`
+	Weight *int   `json:"weight,omitempty" displayName:"Weight" columnTag:"rollout_weights"`
+}
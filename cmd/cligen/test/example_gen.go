@@ -5,7 +5,9 @@ package test
 import (
 	"fmt"
 	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/msanath/gondolf/pkg/duration"
@@ -13,37 +15,53 @@ import (
 )
 
 const (
-	ColumnName              = "name"
-	ColumnServiceName       = "service_name"
-	ColumnServiceRegion     = "service_region"
-	ColumnServiceId         = "service_id"
-	ColumnEnvironment       = "environment"
-	ColumnVersion           = "version"
-	ColumnIsActive          = "is_active"
-	ColumnCreatedAt         = "created_at"
-	ColumnLastUpdatedAt     = "last_updated_at"
-	ColumnCpu               = "cpu"
-	ColumnMemory            = "memory"
-	ColumnStorage           = "storage"
-	ColumnNetworkBandwidth  = "network_bandwidth"
-	ColumnState             = "state"
-	ColumnUptime            = "uptime"
-	ColumnLastRestart       = "last_restart"
-	ColumnHealthCheck       = "health_check"
-	ColumnTotalIncidents    = "total_incidents"
-	ColumnOpenIncidents     = "open_incidents"
-	ColumnResolvedIncidents = "resolved_incidents"
-	ColumnActiveIncidentId  = "active_incident_id"
-	ColumnIpAddress         = "ip_address"
-	ColumnSubnet            = "subnet"
-	ColumnGateway           = "gateway"
-	ColumnVpn               = "vpn"
-	ColumnRunningContainers = "running_containers"
-	ColumnStoppedContainers = "stopped_containers"
+	ColumnDeploymentStatuses = "deployment_statuses"
+	ColumnName               = "name"
+	ColumnServiceName        = "service_name"
+	ColumnServiceRegion      = "service_region"
+	ColumnServiceId          = "service_id"
+	ColumnEnvironment        = "environment"
+	ColumnVersion            = "version"
+	ColumnIsActive           = "is_active"
+	ColumnCreatedAt          = "created_at"
+	ColumnLastUpdatedAt      = "last_updated_at"
+	ColumnCpu                = "cpu"
+	ColumnMemory             = "memory"
+	ColumnStorage            = "storage"
+	ColumnNetworkBandwidth   = "network_bandwidth"
+	ColumnState              = "state"
+	ColumnUptime             = "uptime"
+	ColumnLastRestart        = "last_restart"
+	ColumnHealthCheck        = "health_check"
+	ColumnTotalIncidents     = "total_incidents"
+	ColumnOpenIncidents      = "open_incidents"
+	ColumnResolvedIncidents  = "resolved_incidents"
+	ColumnActiveIncidentId   = "active_incident_id"
+	ColumnIncidentCount      = "incident_count"
+	ColumnIncidentPriorities = "incident_priorities"
+	ColumnIpAddress          = "ip_address"
+	ColumnSubnet             = "subnet"
+	ColumnGateway            = "gateway"
+	ColumnVpn                = "vpn"
+	ColumnRunningContainers  = "running_containers"
+	ColumnStoppedContainers  = "stopped_containers"
+	ColumnTier               = "tier"
+	ColumnReplicas           = "replicas"
+	ColumnOwner              = "owner"
+	ColumnTimeout            = "timeout"
+	ColumnPorts              = "ports"
+	ColumnLabels             = "labels"
+	ColumnRestartPolicy      = "restart_policy"
+	ColumnChecksum           = "checksum"
+	ColumnLoad               = "load"
+	ColumnRolloutStrategy    = "rollout_strategy"
+	ColumnRolloutStepCount   = "rollout_step_count"
+	ColumnRolloutWeights     = "rollout_weights"
 )
 
 func GetDisplayServiceNodeColumnTags() []string {
 	return []string{
+		ColumnDeploymentStatuses,
 		ColumnName,
 		ColumnServiceName,
 		ColumnServiceRegion,
@@ -65,12 +83,26 @@ func GetDisplayServiceNodeColumnTags() []string {
 		ColumnOpenIncidents,
 		ColumnResolvedIncidents,
 		ColumnActiveIncidentId,
+		ColumnIncidentCount,
+		ColumnIncidentPriorities,
 		ColumnIpAddress,
 		ColumnSubnet,
 		ColumnGateway,
 		ColumnVpn,
 		ColumnRunningContainers,
 		ColumnStoppedContainers,
+		ColumnTier,
+		ColumnReplicas,
+		ColumnOwner,
+		ColumnTimeout,
+		ColumnPorts,
+		ColumnLabels,
+		ColumnRestartPolicy,
+		ColumnChecksum,
+		ColumnLoad,
+		ColumnRolloutStrategy,
+		ColumnRolloutStepCount,
+		ColumnRolloutWeights,
 	}
 }
 
@@ -86,6 +118,8 @@ func ValidateDisplayServiceNodeColumnTags(tags []string) error {
 
 func (n *DisplayServiceNode) GetDisplayFieldFromColumnTag(columnTag string) (printer.DisplayField, error) {
 	switch columnTag {
+	case ColumnDeploymentStatuses:
+		return n.GetDeploymentsStatus(), nil
 	case ColumnName:
 		return n.GetName(), nil
 	case ColumnServiceName:
@@ -128,6 +162,10 @@ func (n *DisplayServiceNode) GetDisplayFieldFromColumnTag(columnTag string) (pri
 		return n.Incidents.GetResolvedIncidents(), nil
 	case ColumnActiveIncidentId:
 		return n.Incidents.GetActiveIncidentID(), nil
+	case ColumnIncidentCount:
+		return n.Incidents.GetIncidentHistoryCount(), nil
+	case ColumnIncidentPriorities:
+		return n.Incidents.GetIncidentHistoryPriority(), nil
 	case ColumnIpAddress:
 		return n.NetworkInfo.GetIPAddress(), nil
 	case ColumnSubnet:
@@ -140,116 +178,285 @@ func (n *DisplayServiceNode) GetDisplayFieldFromColumnTag(columnTag string) (pri
 		return n.ContainerSummary.GetRunningContainers(), nil
 	case ColumnStoppedContainers:
 		return n.ContainerSummary.GetStoppedContainers(), nil
+	case ColumnTier:
+		return n.Runtime.GetTier(), nil
+	case ColumnReplicas:
+		return n.Runtime.GetReplicas(), nil
+	case ColumnOwner:
+		return n.Runtime.GetOwner(), nil
+	case ColumnTimeout:
+		return n.Runtime.GetTimeout(), nil
+	case ColumnPorts:
+		return n.Runtime.GetPorts(), nil
+	case ColumnLabels:
+		return n.Runtime.GetLabels(), nil
+	case ColumnRestartPolicy:
+		return n.Runtime.GetRestartPolicy(), nil
+	case ColumnChecksum:
+		return n.Runtime.GetChecksum(), nil
+	case ColumnLoad:
+		return n.Runtime.GetLoad(), nil
+	case ColumnRolloutStrategy:
+		return n.Rollout.GetStrategy(), nil
+	case ColumnRolloutStepCount:
+		return n.Rollout.GetStepsCount(), nil
+	case ColumnRolloutWeights:
+		return n.Rollout.GetStepsWeight(), nil
 	}
 	return printer.DisplayField{}, fmt.Errorf("column tag '%s' not found. Valid tags are %v", columnTag, GetDisplayServiceNodeColumnTags())
 }
 
-func (n *IncidentSummary) GetTotalIncidents() printer.DisplayField {
+// DeploymentsAt returns the element i of Deployments, nil if out of range.
+func (n *DisplayServiceNode) DeploymentsAt(i int) *DeploymentStats {
+	if n == nil || i < 0 || i >= len(n.Deployments) {
+		return nil
+	}
+	return n.Deployments[i]
+}
+
+// DeploymentsFirst returns the first element of Deployments, nil if empty.
+func (n *DisplayServiceNode) DeploymentsFirst() *DeploymentStats {
+	return n.DeploymentsAt(0)
+}
+
+// DeploymentsLast returns the last element of Deployments, nil if empty.
+func (n *DisplayServiceNode) DeploymentsLast() *DeploymentStats {
+	if n == nil {
+		return nil
+	}
+	return n.DeploymentsAt(len(n.Deployments) - 1)
+}
+
+// IncidentHistoryAt returns the element i of IncidentHistory, nil if out of range.
+func (n *IncidentSummary) IncidentHistoryAt(i int) *Incident {
+	if n == nil || i < 0 || i >= len(n.IncidentHistory) {
+		return nil
+	}
+	return &n.IncidentHistory[i]
+}
+
+// IncidentHistoryFirst returns the first element of IncidentHistory, nil if empty.
+func (n *IncidentSummary) IncidentHistoryFirst() *Incident {
+	return n.IncidentHistoryAt(0)
+}
+
+// IncidentHistoryLast returns the last element of IncidentHistory, nil if empty.
+func (n *IncidentSummary) IncidentHistoryLast() *Incident {
+	if n == nil {
+		return nil
+	}
+	return n.IncidentHistoryAt(len(n.IncidentHistory) - 1)
+}
+
+// ContainerDetailsAt returns the element i of ContainerDetails, nil if out of range.
+func (n *ContainerSummary) ContainerDetailsAt(i int) *Container {
+	if n == nil || i < 0 || i >= len(n.ContainerDetails) {
+		return nil
+	}
+	return &n.ContainerDetails[i]
+}
+
+// ContainerDetailsFirst returns the first element of ContainerDetails, nil if empty.
+func (n *ContainerSummary) ContainerDetailsFirst() *Container {
+	return n.ContainerDetailsAt(0)
+}
+
+// ContainerDetailsLast returns the last element of ContainerDetails, nil if empty.
+func (n *ContainerSummary) ContainerDetailsLast() *Container {
+	if n == nil {
+		return nil
+	}
+	return n.ContainerDetailsAt(len(n.ContainerDetails) - 1)
+}
+
+// StepsAt returns the element i of Steps, nil if out of range.
+func (n *Rollout) StepsAt(i int) *RolloutStep {
+	if n == nil || i < 0 || i >= len(n.Steps) {
+		return nil
+	}
+	return n.Steps[i]
+}
+
+// StepsFirst returns the first element of Steps, nil if empty.
+func (n *Rollout) StepsFirst() *RolloutStep {
+	return n.StepsAt(0)
+}
+
+// StepsLast returns the last element of Steps, nil if empty.
+func (n *Rollout) StepsLast() *RolloutStep {
+	if n == nil {
+		return nil
+	}
+	return n.StepsAt(len(n.Steps) - 1)
+}
+
+func (n *DisplayServiceNode) GetDeploymentsCount() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "# Total Incidents",
-		ColumnTag:   "total_incidents",
+		DisplayName: "# Deployments",
+		ColumnTag:   "",
 		Value: func() string {
-			str := strconv.Itoa(n.TotalIncidents)
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(len(n.Deployments))
 			return str
 		},
 	}
 }
 
-func (n *IncidentSummary) GetOpenIncidents() printer.DisplayField {
+func (n *DisplayServiceNode) GetDeploymentsDeploymentID() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "# Open Incidents",
-		ColumnTag:   "open_incidents",
+		DisplayName: "Deployment ID",
+		ColumnTag:   "",
 		Value: func() string {
-			str := strconv.Itoa(n.OpenIncidents)
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Deployments))
+			for _, e := range n.Deployments {
+				if e == nil {
+					values = append(values, printer.UnsetValue)
+					continue
+				}
+				values = append(values, e.DeploymentID)
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *IncidentSummary) GetResolvedIncidents() printer.DisplayField {
+func (n *DisplayServiceNode) GetDeploymentsStartTime() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "# Resolved Incidents",
-		ColumnTag:   "resolved_incidents",
+		DisplayName: "Start Time",
+		ColumnTag:   "",
 		Value: func() string {
-			str := strconv.Itoa(n.ResolvedIncidents)
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Deployments))
+			for _, e := range n.Deployments {
+				if e == nil {
+					values = append(values, printer.UnsetValue)
+					continue
+				}
+				values = append(values, e.StartTime.String()+" ("+duration.HumanDuration(time.Now().UTC().Sub(e.StartTime))+" ago)")
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *ContainerSummary) GetRunningContainers() printer.DisplayField {
+func (n *DisplayServiceNode) GetDeploymentsEndTime() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "# Running Containers",
-		ColumnTag:   "running_containers",
+		DisplayName: "End Time",
+		ColumnTag:   "",
 		Value: func() string {
-			str := strconv.Itoa(n.RunningContainers)
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Deployments))
+			for _, e := range n.Deployments {
+				if e == nil {
+					values = append(values, printer.UnsetValue)
+					continue
+				}
+				values = append(values, e.EndTime.String()+" ("+duration.HumanDuration(time.Now().UTC().Sub(e.EndTime))+" ago)")
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *ContainerSummary) GetStoppedContainers() printer.DisplayField {
+func (n *DisplayServiceNode) GetDeploymentsStatus() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "# Stopped Containers",
-		ColumnTag:   "stopped_containers",
+		DisplayName: "Status",
+		ColumnTag:   "deployment_statuses",
 		Value: func() string {
-			str := strconv.Itoa(n.StoppedContainers)
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Deployments))
+			for _, e := range n.Deployments {
+				if e == nil {
+					values = append(values, printer.UnsetValue)
+					continue
+				}
+				values = append(values, e.Status)
+			}
+			str := strings.Join(values, "\n")
+			if slices.Contains(values, "Failed") {
+				return printer.RedText(str)
+			}
+			if slices.Contains(values, "Success") {
+				return printer.GreenText(str)
+			}
 			return str
 		},
 	}
 }
 
-func (n *Container) GetRestartCount() printer.DisplayField {
+func (n *DisplayServiceNode) GetDeploymentsVersion() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Restart Count",
+		DisplayName: "Version",
 		ColumnTag:   "",
 		Value: func() string {
-			str := strconv.Itoa(n.RestartCount)
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Deployments))
+			for _, e := range n.Deployments {
+				if e == nil {
+					values = append(values, printer.UnsetValue)
+					continue
+				}
+				values = append(values, e.Version)
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *ServiceMetadata) GetIsActive() printer.DisplayField {
+func (n *DeploymentStats) GetDeploymentID() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Active",
-		ColumnTag:   "is_active",
+		DisplayName: "Deployment ID",
+		ColumnTag:   "",
 		Value: func() string {
-			str := strconv.FormatBool(n.IsActive)
-			if str == "false" {
-				return printer.RedText(str)
-			}
-			if str == "true" {
-				return printer.GreenText(str)
+			if n == nil {
+				return printer.UnsetValue
 			}
+			str := n.DeploymentID
 			return str
 		},
 	}
 }
 
-func (n *NetworkInfo) GetVPN() printer.DisplayField {
+func (n *DeploymentStats) GetStartTime() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "VPN Enabled",
-		ColumnTag:   "vpn",
+		DisplayName: "Start Time",
+		ColumnTag:   "",
 		Value: func() string {
-			str := strconv.FormatBool(n.VPN)
-			if str == "false" {
-				return printer.RedText(str)
-			}
-			if str == "true" {
-				return printer.GreenText(str)
+			if n == nil {
+				return printer.UnsetValue
 			}
+			str := n.StartTime.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(n.StartTime)) + " ago)"
 			return str
 		},
 	}
 }
 
-func (n *DeploymentStats) GetDeploymentID() printer.DisplayField {
+func (n *DeploymentStats) GetEndTime() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Deployment ID",
+		DisplayName: "End Time",
 		ColumnTag:   "",
 		Value: func() string {
-			str := n.DeploymentID
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.EndTime.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(n.EndTime)) + " ago)"
 			return str
 		},
 	}
@@ -258,8 +465,11 @@ func (n *DeploymentStats) GetDeploymentID() printer.DisplayField {
 func (n *DeploymentStats) GetStatus() printer.DisplayField {
 	return printer.DisplayField{
 		DisplayName: "Status",
-		ColumnTag:   "",
+		ColumnTag:   "deployment_statuses",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Status
 			if str == "Failed" {
 				return printer.RedText(str)
@@ -277,6 +487,9 @@ func (n *DeploymentStats) GetVersion() printer.DisplayField {
 		DisplayName: "Version",
 		ColumnTag:   "",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Version
 			return str
 		},
@@ -288,6 +501,9 @@ func (n *DisplayServiceNode) GetName() printer.DisplayField {
 		DisplayName: "Name",
 		ColumnTag:   "name",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Name
 			return str
 		},
@@ -299,6 +515,9 @@ func (n *ServiceMetadata) GetName() printer.DisplayField {
 		DisplayName: "Service Name",
 		ColumnTag:   "service_name",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Name
 			return str
 		},
@@ -310,6 +529,9 @@ func (n *ServiceMetadata) GetRegion() printer.DisplayField {
 		DisplayName: "Region",
 		ColumnTag:   "service_region",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Region
 			return str
 		},
@@ -321,6 +543,9 @@ func (n *ServiceMetadata) GetServiceID() printer.DisplayField {
 		DisplayName: "Service ID",
 		ColumnTag:   "service_id",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.ServiceID
 			return str
 		},
@@ -332,6 +557,9 @@ func (n *ServiceMetadata) GetEnvironment() printer.DisplayField {
 		DisplayName: "Environment",
 		ColumnTag:   "environment",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Environment
 			return str
 		},
@@ -343,17 +571,71 @@ func (n *ServiceMetadata) GetVersion() printer.DisplayField {
 		DisplayName: "Version",
 		ColumnTag:   "version",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Version
 			return str
 		},
 	}
 }
 
+func (n *ServiceMetadata) GetIsActive() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Active",
+		ColumnTag:   "is_active",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.FormatBool(n.IsActive)
+			if str == "false" {
+				return printer.RedText(str)
+			}
+			if str == "true" {
+				return printer.GreenText(str)
+			}
+			return str
+		},
+	}
+}
+
+func (n *ServiceMetadata) GetCreatedAt() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Created At",
+		ColumnTag:   "created_at",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.CreatedAt.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(n.CreatedAt)) + " ago)"
+			return str
+		},
+	}
+}
+
+func (n *ServiceMetadata) GetLastUpdatedAt() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Last Updated At",
+		ColumnTag:   "last_updated_at",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.LastUpdatedAt.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(n.LastUpdatedAt)) + " ago)"
+			return str
+		},
+	}
+}
+
 func (n *Resources) GetCPU() printer.DisplayField {
 	return printer.DisplayField{
 		DisplayName: "CPU",
 		ColumnTag:   "cpu",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.CPU
 			return str
 		},
@@ -365,6 +647,9 @@ func (n *Resources) GetMemory() printer.DisplayField {
 		DisplayName: "Memory",
 		ColumnTag:   "memory",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Memory
 			return str
 		},
@@ -376,6 +661,9 @@ func (n *Resources) GetStorage() printer.DisplayField {
 		DisplayName: "Storage",
 		ColumnTag:   "storage",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Storage
 			return str
 		},
@@ -387,6 +675,9 @@ func (n *Resources) GetNetworkBandwidth() printer.DisplayField {
 		DisplayName: "Network Bandwidth",
 		ColumnTag:   "network_bandwidth",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.NetworkBandwidth
 			return str
 		},
@@ -398,6 +689,9 @@ func (n *ServiceStatus) GetState() printer.DisplayField {
 		DisplayName: "State",
 		ColumnTag:   "state",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.State
 			if str == "Stopped" {
 				return printer.RedText(str)
@@ -418,17 +712,37 @@ func (n *ServiceStatus) GetUptime() printer.DisplayField {
 		DisplayName: "Uptime",
 		ColumnTag:   "uptime",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Uptime
 			return str
 		},
 	}
 }
 
+func (n *ServiceStatus) GetLastRestart() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Last Restart",
+		ColumnTag:   "last_restart",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.LastRestart.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(n.LastRestart)) + " ago)"
+			return str
+		},
+	}
+}
+
 func (n *ServiceStatus) GetHealthCheck() printer.DisplayField {
 	return printer.DisplayField{
 		DisplayName: "Health Check",
 		ColumnTag:   "health_check",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.HealthCheck
 			if str == "Unhealthy" {
 				return printer.RedText(str)
@@ -441,119 +755,538 @@ func (n *ServiceStatus) GetHealthCheck() printer.DisplayField {
 	}
 }
 
-func (n *IncidentSummary) GetActiveIncidentID() printer.DisplayField {
+func (n *IncidentSummary) GetTotalIncidents() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Active Incident ID",
-		ColumnTag:   "active_incident_id",
+		DisplayName: "# Total Incidents",
+		ColumnTag:   "total_incidents",
 		Value: func() string {
-			str := n.ActiveIncidentID
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(n.TotalIncidents)
 			return str
 		},
 	}
 }
 
-func (n *Incident) GetIncidentID() printer.DisplayField {
+func (n *IncidentSummary) GetOpenIncidents() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Incident ID",
-		ColumnTag:   "",
+		DisplayName: "# Open Incidents",
+		ColumnTag:   "open_incidents",
 		Value: func() string {
-			str := n.IncidentID
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(n.OpenIncidents)
 			return str
 		},
 	}
 }
 
-func (n *Incident) GetStatus() printer.DisplayField {
+func (n *IncidentSummary) GetResolvedIncidents() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Status",
-		ColumnTag:   "",
+		DisplayName: "# Resolved Incidents",
+		ColumnTag:   "resolved_incidents",
 		Value: func() string {
-			str := n.Status
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(n.ResolvedIncidents)
 			return str
 		},
 	}
 }
 
-func (n *Incident) GetDescription() printer.DisplayField {
+func (n *IncidentSummary) GetActiveIncidentID() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Description",
-		ColumnTag:   "",
+		DisplayName: "Active Incident ID",
+		ColumnTag:   "active_incident_id",
 		Value: func() string {
-			str := n.Description
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.ActiveIncidentID
 			return str
 		},
 	}
 }
 
-func (n *Incident) GetPriority() printer.DisplayField {
+func (n *IncidentSummary) GetIncidentHistoryCount() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Priority",
-		ColumnTag:   "",
+		DisplayName: "# Incidents",
+		ColumnTag:   "incident_count",
 		Value: func() string {
-			str := n.Priority
-			if str == "High" {
-				return printer.RedText(str)
-			}
-			if str == "Low" {
-				return printer.GreenText(str)
-			}
-			if str == "Medium" {
-				return printer.YellowText(str)
+			if n == nil {
+				return printer.UnsetValue
 			}
+			str := strconv.Itoa(len(n.IncidentHistory))
 			return str
 		},
 	}
 }
 
-func (n *NetworkInfo) GetIPAddress() printer.DisplayField {
+func (n *IncidentSummary) GetIncidentHistoryIncidentID() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "IP Address",
-		ColumnTag:   "ip_address",
+		DisplayName: "Incident ID",
+		ColumnTag:   "",
 		Value: func() string {
-			str := n.IPAddress
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.IncidentHistory))
+			for i := range n.IncidentHistory {
+				e := &n.IncidentHistory[i]
+				values = append(values, e.IncidentID)
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *NetworkInfo) GetSubnet() printer.DisplayField {
+func (n *IncidentSummary) GetIncidentHistoryStatus() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Subnet",
-		ColumnTag:   "subnet",
+		DisplayName: "Status",
+		ColumnTag:   "",
 		Value: func() string {
-			str := n.Subnet
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.IncidentHistory))
+			for i := range n.IncidentHistory {
+				e := &n.IncidentHistory[i]
+				values = append(values, e.Status)
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *NetworkInfo) GetGateway() printer.DisplayField {
+func (n *IncidentSummary) GetIncidentHistoryReportedAt() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Gateway",
-		ColumnTag:   "gateway",
+		DisplayName: "Reported At",
+		ColumnTag:   "",
 		Value: func() string {
-			str := n.Gateway
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.IncidentHistory))
+			for i := range n.IncidentHistory {
+				e := &n.IncidentHistory[i]
+				values = append(values, e.ReportedAt.String()+" ("+duration.HumanDuration(time.Now().UTC().Sub(e.ReportedAt))+" ago)")
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *Container) GetName() printer.DisplayField {
+func (n *IncidentSummary) GetIncidentHistoryResolvedAt() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Container Name",
+		DisplayName: "Resolved At",
 		ColumnTag:   "",
 		Value: func() string {
-			str := n.Name
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.IncidentHistory))
+			for i := range n.IncidentHistory {
+				e := &n.IncidentHistory[i]
+				values = append(values, e.ResolvedAt.String()+" ("+duration.HumanDuration(time.Now().UTC().Sub(e.ResolvedAt))+" ago)")
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *Container) GetImage() printer.DisplayField {
+func (n *IncidentSummary) GetIncidentHistoryDescription() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Description",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.IncidentHistory))
+			for i := range n.IncidentHistory {
+				e := &n.IncidentHistory[i]
+				values = append(values, e.Description)
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *IncidentSummary) GetIncidentHistoryPriority() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Priority",
+		ColumnTag:   "incident_priorities",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.IncidentHistory))
+			for i := range n.IncidentHistory {
+				e := &n.IncidentHistory[i]
+				values = append(values, e.Priority)
+			}
+			str := strings.Join(values, "\n")
+			if slices.Contains(values, "High") {
+				return printer.RedText(str)
+			}
+			if slices.Contains(values, "Low") {
+				return printer.GreenText(str)
+			}
+			if slices.Contains(values, "Medium") {
+				return printer.YellowText(str)
+			}
+			return str
+		},
+	}
+}
+
+func (n *Incident) GetIncidentID() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Incident ID",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.IncidentID
+			return str
+		},
+	}
+}
+
+func (n *Incident) GetStatus() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Status",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Status
+			return str
+		},
+	}
+}
+
+func (n *Incident) GetReportedAt() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Reported At",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.ReportedAt.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(n.ReportedAt)) + " ago)"
+			return str
+		},
+	}
+}
+
+func (n *Incident) GetResolvedAt() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Resolved At",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.ResolvedAt.String() + " (" + duration.HumanDuration(time.Now().UTC().Sub(n.ResolvedAt)) + " ago)"
+			return str
+		},
+	}
+}
+
+func (n *Incident) GetDescription() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Description",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Description
+			return str
+		},
+	}
+}
+
+func (n *Incident) GetPriority() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Priority",
+		ColumnTag:   "incident_priorities",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Priority
+			if str == "High" {
+				return printer.RedText(str)
+			}
+			if str == "Low" {
+				return printer.GreenText(str)
+			}
+			if str == "Medium" {
+				return printer.YellowText(str)
+			}
+			return str
+		},
+	}
+}
+
+func (n *NetworkInfo) GetIPAddress() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "IP Address",
+		ColumnTag:   "ip_address",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.IPAddress
+			return str
+		},
+	}
+}
+
+func (n *NetworkInfo) GetSubnet() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Subnet",
+		ColumnTag:   "subnet",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Subnet
+			return str
+		},
+	}
+}
+
+func (n *NetworkInfo) GetGateway() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Gateway",
+		ColumnTag:   "gateway",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Gateway
+			return str
+		},
+	}
+}
+
+func (n *NetworkInfo) GetVPN() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "VPN Enabled",
+		ColumnTag:   "vpn",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.FormatBool(n.VPN)
+			if str == "false" {
+				return printer.RedText(str)
+			}
+			if str == "true" {
+				return printer.GreenText(str)
+			}
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetRunningContainers() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "# Running Containers",
+		ColumnTag:   "running_containers",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(n.RunningContainers)
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetStoppedContainers() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "# Stopped Containers",
+		ColumnTag:   "stopped_containers",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(n.StoppedContainers)
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetContainerDetailsCount() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "# Container Details",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(len(n.ContainerDetails))
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetContainerDetailsName() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Container Name",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.ContainerDetails))
+			for i := range n.ContainerDetails {
+				e := &n.ContainerDetails[i]
+				values = append(values, e.Name)
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetContainerDetailsImage() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Container Image",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.ContainerDetails))
+			for i := range n.ContainerDetails {
+				e := &n.ContainerDetails[i]
+				values = append(values, e.Image)
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetContainerDetailsState() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "State",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.ContainerDetails))
+			for i := range n.ContainerDetails {
+				e := &n.ContainerDetails[i]
+				values = append(values, e.State)
+			}
+			str := strings.Join(values, "\n")
+			if slices.Contains(values, "Stopped") {
+				return printer.RedText(str)
+			}
+			if slices.Contains(values, "Running") {
+				return printer.GreenText(str)
+			}
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetContainerDetailsCPUUsage() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "CPU Usage",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.ContainerDetails))
+			for i := range n.ContainerDetails {
+				e := &n.ContainerDetails[i]
+				values = append(values, e.CPUUsage)
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetContainerDetailsMemUsage() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Memory Usage",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.ContainerDetails))
+			for i := range n.ContainerDetails {
+				e := &n.ContainerDetails[i]
+				values = append(values, e.MemUsage)
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *ContainerSummary) GetContainerDetailsRestartCount() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Restart Count",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.ContainerDetails))
+			for i := range n.ContainerDetails {
+				e := &n.ContainerDetails[i]
+				values = append(values, strconv.Itoa(e.RestartCount))
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *Container) GetName() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Container Name",
+		ColumnTag:   "",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Name
+			return str
+		},
+	}
+}
+
+func (n *Container) GetImage() printer.DisplayField {
 	return printer.DisplayField{
 		DisplayName: "Container Image",
 		ColumnTag:   "",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.Image
 			return str
 		},
@@ -565,6 +1298,9 @@ func (n *Container) GetState() printer.DisplayField {
 		DisplayName: "State",
 		ColumnTag:   "",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.State
 			if str == "Stopped" {
 				return printer.RedText(str)
@@ -582,6 +1318,9 @@ func (n *Container) GetCPUUsage() printer.DisplayField {
 		DisplayName: "CPU Usage",
 		ColumnTag:   "",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.CPUUsage
 			return str
 		},
@@ -593,106 +1332,490 @@ func (n *Container) GetMemUsage() printer.DisplayField {
 		DisplayName: "Memory Usage",
 		ColumnTag:   "",
 		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
 			str := n.MemUsage
 			return str
 		},
 	}
 }
 
-func (n *DeploymentStats) GetStartTime() printer.DisplayField {
+func (n *Container) GetRestartCount() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Start Time",
+		DisplayName: "Restart Count",
 		ColumnTag:   "",
 		Value: func() string {
-			str := n.StartTime.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.StartTime)
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(n.RestartCount)
 			return str
 		},
 	}
 }
 
-func (n *DeploymentStats) GetEndTime() printer.DisplayField {
+func (n *Runtime) GetTier() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "End Time",
-		ColumnTag:   "",
+		DisplayName: "Tier",
+		ColumnTag:   "tier",
 		Value: func() string {
-			str := n.EndTime.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.EndTime)
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := string(n.Tier)
+			if str == "gold" {
+				return printer.GreenText(str)
+			}
 			return str
 		},
 	}
 }
 
-func (n *ServiceMetadata) GetCreatedAt() printer.DisplayField {
+func (n *Runtime) GetReplicas() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Created At",
-		ColumnTag:   "created_at",
+		DisplayName: "Replicas",
+		ColumnTag:   "replicas",
 		Value: func() string {
-			str := n.CreatedAt.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.CreatedAt)
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
+			if n == nil {
+				return printer.UnsetValue
+			}
+			if n.Replicas == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(*n.Replicas)
 			return str
 		},
 	}
 }
 
-func (n *ServiceMetadata) GetLastUpdatedAt() printer.DisplayField {
+func (n *Runtime) GetOwner() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Last Updated At",
-		ColumnTag:   "last_updated_at",
+		DisplayName: "Owner",
+		ColumnTag:   "owner",
 		Value: func() string {
-			str := n.LastUpdatedAt.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.LastUpdatedAt)
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
+			if n == nil {
+				return printer.UnsetValue
+			}
+			if n.Owner == nil {
+				return printer.UnsetValue
+			}
+			str := *n.Owner
 			return str
 		},
 	}
 }
 
-func (n *ServiceStatus) GetLastRestart() printer.DisplayField {
+func (n *Runtime) GetTimeout() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Last Restart",
-		ColumnTag:   "last_restart",
+		DisplayName: "Timeout",
+		ColumnTag:   "timeout",
 		Value: func() string {
-			str := n.LastRestart.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.LastRestart)
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
+			if n == nil {
+				return printer.UnsetValue
+			}
+			if n.Timeout == nil {
+				return printer.UnsetValue
+			}
+			str := duration.HumanDuration(*n.Timeout)
 			return str
 		},
 	}
 }
 
-func (n *Incident) GetReportedAt() printer.DisplayField {
+func (n *Runtime) GetPorts() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Reported At",
+		DisplayName: "Ports",
+		ColumnTag:   "ports",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Ports))
+			for _, e := range n.Ports {
+				values = append(values, strconv.FormatInt(int64(e), 10))
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *Runtime) GetLabels() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Labels",
+		ColumnTag:   "labels",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Labels))
+			for k, v := range n.Labels {
+				values = append(values, k+"="+v)
+			}
+			// Sort the entries to make the output deterministic
+			sort.Strings(values)
+			str := strings.Join(values, "\n")
+			if slices.Contains(values, "env=prod") {
+				return printer.RedText(str)
+			}
+			return str
+		},
+	}
+}
+
+func (n *Runtime) GetRestartPolicy() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Restart Policy",
+		ColumnTag:   "restart_policy",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.RestartPolicy.String()
+			if str == "Never" {
+				return printer.RedText(str)
+			}
+			return str
+		},
+	}
+}
+
+func (n *Runtime) GetChecksum() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Checksum",
+		ColumnTag:   "checksum",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := printer.TextValue(n.Checksum)
+			return str
+		},
+	}
+}
+
+func (n *Runtime) GetLoad() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Load",
+		ColumnTag:   "load",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.FormatFloat(n.Load, 'f', -1, 64)
+			return str
+		},
+	}
+}
+
+func (n *Rollout) GetStrategy() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Strategy",
+		ColumnTag:   "rollout_strategy",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Strategy
+			return str
+		},
+	}
+}
+
+func (n *Rollout) GetStepsCount() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "# Steps",
+		ColumnTag:   "rollout_step_count",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(len(n.Steps))
+			return str
+		},
+	}
+}
+
+func (n *Rollout) GetStepsName() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Step",
 		ColumnTag:   "",
 		Value: func() string {
-			str := n.ReportedAt.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.ReportedAt)
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Steps))
+			for _, e := range n.Steps {
+				if e == nil {
+					values = append(values, printer.UnsetValue)
+					continue
+				}
+				values = append(values, e.Name)
+			}
+			str := strings.Join(values, "\n")
 			return str
 		},
 	}
 }
 
-func (n *Incident) GetResolvedAt() printer.DisplayField {
+func (n *Rollout) GetStepsWeight() printer.DisplayField {
 	return printer.DisplayField{
-		DisplayName: "Resolved At",
+		DisplayName: "Weight",
+		ColumnTag:   "rollout_weights",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			values := make([]string, 0, len(n.Steps))
+			for _, e := range n.Steps {
+				if e == nil {
+					values = append(values, printer.UnsetValue)
+					continue
+				}
+				values = append(values, func() string {
+					if e.Weight == nil {
+						return printer.UnsetValue
+					}
+					str := strconv.Itoa(*e.Weight)
+					return str
+				}())
+			}
+			str := strings.Join(values, "\n")
+			return str
+		},
+	}
+}
+
+func (n *RolloutStep) GetName() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Step",
 		ColumnTag:   "",
 		Value: func() string {
-			str := n.ResolvedAt.String()
-			curTime := time.Now().UTC()
-			agoTime := curTime.Sub(n.ResolvedAt)
-			str += " (" + duration.HumanDuration(agoTime) + " ago)"
+			if n == nil {
+				return printer.UnsetValue
+			}
+			str := n.Name
+			return str
+		},
+	}
+}
+
+func (n *RolloutStep) GetWeight() printer.DisplayField {
+	return printer.DisplayField{
+		DisplayName: "Weight",
+		ColumnTag:   "rollout_weights",
+		Value: func() string {
+			if n == nil {
+				return printer.UnsetValue
+			}
+			if n.Weight == nil {
+				return printer.UnsetValue
+			}
+			str := strconv.Itoa(*n.Weight)
 			return str
 		},
 	}
 }
+
+var displayServiceNodeColumnDisplayNames = map[string]string{
+	ColumnDeploymentStatuses: "Status",
+	ColumnName:               "Name",
+	ColumnServiceName:        "Service Name",
+	ColumnServiceRegion:      "Region",
+	ColumnServiceId:          "Service ID",
+	ColumnEnvironment:        "Environment",
+	ColumnVersion:            "Version",
+	ColumnIsActive:           "Active",
+	ColumnCreatedAt:          "Created At",
+	ColumnLastUpdatedAt:      "Last Updated At",
+	ColumnCpu:                "CPU",
+	ColumnMemory:             "Memory",
+	ColumnStorage:            "Storage",
+	ColumnNetworkBandwidth:   "Network Bandwidth",
+	ColumnState:              "State",
+	ColumnUptime:             "Uptime",
+	ColumnLastRestart:        "Last Restart",
+	ColumnHealthCheck:        "Health Check",
+	ColumnTotalIncidents:     "# Total Incidents",
+	ColumnOpenIncidents:      "# Open Incidents",
+	ColumnResolvedIncidents:  "# Resolved Incidents",
+	ColumnActiveIncidentId:   "Active Incident ID",
+	ColumnIncidentCount:      "# Incidents",
+	ColumnIncidentPriorities: "Priority",
+	ColumnIpAddress:          "IP Address",
+	ColumnSubnet:             "Subnet",
+	ColumnGateway:            "Gateway",
+	ColumnVpn:                "VPN Enabled",
+	ColumnRunningContainers:  "# Running Containers",
+	ColumnStoppedContainers:  "# Stopped Containers",
+	ColumnTier:               "Tier",
+	ColumnReplicas:           "Replicas",
+	ColumnOwner:              "Owner",
+	ColumnTimeout:            "Timeout",
+	ColumnPorts:              "Ports",
+	ColumnLabels:             "Labels",
+	ColumnRestartPolicy:      "Restart Policy",
+	ColumnChecksum:           "Checksum",
+	ColumnLoad:               "Load",
+	ColumnRolloutStrategy:    "Strategy",
+	ColumnRolloutStepCount:   "# Steps",
+	ColumnRolloutWeights:     "Weight",
+}
+
+// RenderDisplayServiceNodeTable prints the items as a table with a column per column tag, headed
+// by the display names of the fields. Empty columnTags print every column.
+func RenderDisplayServiceNodeTable(items []DisplayServiceNode, columnTags []string, opts ...printer.TablePrinterOption) error {
+	if len(columnTags) == 0 {
+		columnTags = GetDisplayServiceNodeColumnTags()
+	}
+	if err := ValidateDisplayServiceNodeColumnTags(columnTags); err != nil {
+		return err
+	}
+	headers := make([]string, len(columnTags))
+	for i, tag := range columnTags {
+		headers[i] = displayServiceNodeColumnDisplayNames[tag]
+	}
+	rows := make([][]string, len(items))
+	for i := range items {
+		for _, tag := range columnTags {
+			field, err := items[i].GetDisplayFieldFromColumnTag(tag)
+			if err != nil {
+				return err
+			}
+			rows[i] = append(rows[i], field.Value())
+		}
+	}
+	printer.NewPlainTextPrinter().PrintTable(headers, rows, opts...)
+	return nil
+}
+
+// RenderDisplayServiceNodeDetail prints the display fields of the item, with a section per nested
+// struct and a table per slice of structs.
+func RenderDisplayServiceNodeDetail(item *DisplayServiceNode) {
+	p := printer.NewPlainTextPrinter()
+	p.PrintDisplayField(item.GetName())
+	if len(item.Deployments) > 0 {
+		p.PrintEmptyLine()
+		p.PrintHeader("Deployments")
+		rows := [][]string{}
+		for _, elem := range item.Deployments {
+			if elem == nil {
+				continue
+			}
+			rows = append(rows, []string{
+				elem.GetDeploymentID().Value(),
+				elem.GetStartTime().Value(),
+				elem.GetEndTime().Value(),
+				elem.GetStatus().Value(),
+				elem.GetVersion().Value(),
+			})
+		}
+		p.PrintTable([]string{"Deployment ID", "Start Time", "End Time", "Status", "Version"}, rows, printer.WithAlignLeft())
+	}
+	p.PrintEmptyLine()
+	p.PrintHeader("Service Metadata")
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetName())
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetRegion())
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetServiceID())
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetEnvironment())
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetVersion())
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetIsActive())
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetCreatedAt())
+	p.PrintDisplayFieldWithIndent(item.ServiceMetadata.GetLastUpdatedAt())
+	p.PrintEmptyLine()
+	p.PrintHeader("Resources")
+	p.PrintDisplayFieldWithIndent(item.Resources.GetCPU())
+	p.PrintDisplayFieldWithIndent(item.Resources.GetMemory())
+	p.PrintDisplayFieldWithIndent(item.Resources.GetStorage())
+	p.PrintDisplayFieldWithIndent(item.Resources.GetNetworkBandwidth())
+	p.PrintEmptyLine()
+	p.PrintHeader("Status")
+	p.PrintDisplayFieldWithIndent(item.Status.GetState())
+	p.PrintDisplayFieldWithIndent(item.Status.GetUptime())
+	p.PrintDisplayFieldWithIndent(item.Status.GetLastRestart())
+	p.PrintDisplayFieldWithIndent(item.Status.GetHealthCheck())
+	p.PrintEmptyLine()
+	p.PrintHeader("Incidents")
+	p.PrintDisplayFieldWithIndent(item.Incidents.GetTotalIncidents())
+	p.PrintDisplayFieldWithIndent(item.Incidents.GetOpenIncidents())
+	p.PrintDisplayFieldWithIndent(item.Incidents.GetResolvedIncidents())
+	p.PrintDisplayFieldWithIndent(item.Incidents.GetActiveIncidentID())
+	p.PrintDisplayFieldWithIndent(item.Incidents.GetIncidentHistoryCount())
+	if len(item.Incidents.IncidentHistory) > 0 {
+		p.PrintEmptyLine()
+		p.PrintHeader("Incident History")
+		rows := [][]string{}
+		for i := range item.Incidents.IncidentHistory {
+			elem := &item.Incidents.IncidentHistory[i]
+			rows = append(rows, []string{
+				elem.GetIncidentID().Value(),
+				elem.GetStatus().Value(),
+				elem.GetReportedAt().Value(),
+				elem.GetResolvedAt().Value(),
+				elem.GetDescription().Value(),
+				elem.GetPriority().Value(),
+			})
+		}
+		p.PrintTable([]string{"Incident ID", "Status", "Reported At", "Resolved At", "Description", "Priority"}, rows, printer.WithAlignLeft())
+	}
+	p.PrintEmptyLine()
+	p.PrintHeader("Network Info")
+	p.PrintDisplayFieldWithIndent(item.NetworkInfo.GetIPAddress())
+	p.PrintDisplayFieldWithIndent(item.NetworkInfo.GetSubnet())
+	p.PrintDisplayFieldWithIndent(item.NetworkInfo.GetGateway())
+	p.PrintDisplayFieldWithIndent(item.NetworkInfo.GetVPN())
+	p.PrintEmptyLine()
+	p.PrintHeader("Container Summary")
+	p.PrintDisplayFieldWithIndent(item.ContainerSummary.GetRunningContainers())
+	p.PrintDisplayFieldWithIndent(item.ContainerSummary.GetStoppedContainers())
+	if len(item.ContainerSummary.ContainerDetails) > 0 {
+		p.PrintEmptyLine()
+		p.PrintHeader("Container Details")
+		rows := [][]string{}
+		for i := range item.ContainerSummary.ContainerDetails {
+			elem := &item.ContainerSummary.ContainerDetails[i]
+			rows = append(rows, []string{
+				elem.GetName().Value(),
+				elem.GetImage().Value(),
+				elem.GetState().Value(),
+				elem.GetCPUUsage().Value(),
+				elem.GetMemUsage().Value(),
+				elem.GetRestartCount().Value(),
+			})
+		}
+		p.PrintTable([]string{"Container Name", "Container Image", "State", "CPU Usage", "Memory Usage", "Restart Count"}, rows, printer.WithAlignLeft())
+	}
+	p.PrintEmptyLine()
+	p.PrintHeader("Runtime")
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetTier())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetReplicas())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetOwner())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetTimeout())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetPorts())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetLabels())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetRestartPolicy())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetChecksum())
+	p.PrintDisplayFieldWithIndent(item.Runtime.GetLoad())
+	if item.Rollout != nil {
+		p.PrintEmptyLine()
+		p.PrintHeader("Rollout")
+		p.PrintDisplayFieldWithIndent(item.Rollout.GetStrategy())
+		p.PrintDisplayFieldWithIndent(item.Rollout.GetStepsCount())
+	}
+	if item.Rollout != nil && len(item.Rollout.Steps) > 0 {
+		p.PrintEmptyLine()
+		p.PrintHeader("Steps")
+		rows := [][]string{}
+		for _, elem := range item.Rollout.Steps {
+			if elem == nil {
+				continue
+			}
+			rows = append(rows, []string{
+				elem.GetName().Value(),
+				elem.GetWeight().Value(),
+			})
+		}
+		p.PrintTable([]string{"Step", "Weight"}, rows, printer.WithAlignLeft())
+	}
+}
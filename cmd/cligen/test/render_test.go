@@ -0,0 +1,131 @@
+package test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/msanath/gondolf/pkg/printer"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout returns what f prints to the standard output, where the printer writes.
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestRenderTable(t *testing.T) {
+	nodes := []DisplayServiceNode{
+		{Name: "node1", Status: ServiceStatus{State: "Running"}},
+		{Name: "node2", Status: ServiceStatus{State: "Stopped"}},
+	}
+
+	out := captureStdout(t, func() {
+		require.NoError(t, RenderDisplayServiceNodeTable(nodes, []string{ColumnName, ColumnState}))
+	})
+	require.Contains(t, out, "Name")
+	require.Contains(t, out, "State")
+	require.Contains(t, out, "node2")
+	require.Contains(t, out, "Stopped")
+	require.Contains(t, out, "Total: 2")
+
+	err := RenderDisplayServiceNodeTable(nodes, []string{ColumnName, "unknown"})
+	require.ErrorContains(t, err, "column tag 'unknown' not found")
+}
+
+func TestRenderDetail(t *testing.T) {
+	node := &DisplayServiceNode{
+		Name:        "node1",
+		Deployments: []*DeploymentStats{{DeploymentID: "deploy1"}, nil},
+		Incidents: IncidentSummary{
+			TotalIncidents:  1,
+			IncidentHistory: []Incident{{IncidentID: "incident1", Priority: "High"}},
+		},
+	}
+
+	out := captureStdout(t, func() {
+		RenderDisplayServiceNodeDetail(node)
+	})
+	require.Contains(t, out, "node1")
+	require.Contains(t, out, "Service Metadata")
+	require.Contains(t, out, "Deployment ID")
+	require.Contains(t, out, "deploy1")
+	require.Contains(t, out, "Incident History")
+	require.Contains(t, out, "incident1")
+	// Empty slices print no table.
+	require.NotContains(t, out, "Container Details")
+}
+
+func TestDisplayValues(t *testing.T) {
+	replicas := 3
+	runtime := &Runtime{
+		Tier:          "gold",
+		Replicas:      &replicas,
+		Ports:         []int32{80, 443},
+		Labels:        map[string]string{"team": "infra", "env": "prod"},
+		RestartPolicy: RestartNever,
+		Checksum:      Checksum{0xde, 0xad, 0xbe, 0xef},
+		Load:          0.75,
+	}
+
+	require.Equal(t, "gold", runtime.GetTier().Value())
+	require.Equal(t, "3", runtime.GetReplicas().Value())
+	require.Equal(t, printer.UnsetValue, runtime.GetOwner().Value())
+	require.Equal(t, printer.UnsetValue, runtime.GetTimeout().Value())
+	require.Equal(t, "80\n443", runtime.GetPorts().Value())
+	require.Equal(t, "env=prod\nteam=infra", runtime.GetLabels().Value())
+	require.Equal(t, "Never", runtime.GetRestartPolicy().Value())
+	require.Equal(t, "deadbeef", runtime.GetChecksum().Value())
+	require.Equal(t, "0.75", runtime.GetLoad().Value())
+}
+
+func TestSliceAggregates(t *testing.T) {
+	weight := 25
+	node := &DisplayServiceNode{
+		Deployments: []*DeploymentStats{{DeploymentID: "deploy1", Status: "Success"}, nil},
+		Incidents: IncidentSummary{
+			IncidentHistory: []Incident{
+				{IncidentID: "incident1", Priority: "Low"},
+				{IncidentID: "incident2", Priority: "Medium"},
+			},
+		},
+		Rollout: &Rollout{Steps: []*RolloutStep{{Name: "canary", Weight: &weight}, {Name: "full"}}},
+	}
+
+	// Indexed accessors.
+	require.Equal(t, "incident1", node.Incidents.IncidentHistoryFirst().IncidentID)
+	require.Equal(t, "incident2", node.Incidents.IncidentHistoryLast().IncidentID)
+	require.Nil(t, node.Incidents.IncidentHistoryAt(2))
+	require.Nil(t, node.DeploymentsAt(1))
+	require.Equal(t, printer.UnsetValue, node.DeploymentsLast().GetStatus().Value())
+	require.Equal(t, "full", node.Rollout.StepsLast().GetName().Value())
+
+	// Aggregates, by column tag.
+	values := map[string]string{}
+	for _, tag := range []string{ColumnIncidentCount, ColumnIncidentPriorities, ColumnDeploymentStatuses, ColumnRolloutStepCount, ColumnRolloutWeights} {
+		field, err := node.GetDisplayFieldFromColumnTag(tag)
+		require.NoError(t, err)
+		values[tag] = field.Value()
+	}
+	require.Equal(t, "2", values[ColumnIncidentCount])
+	require.Equal(t, printer.GreenText("Low\nMedium"), values[ColumnIncidentPriorities])
+	require.Equal(t, printer.GreenText("Success\n"+printer.UnsetValue), values[ColumnDeploymentStatuses])
+	require.Equal(t, "2", values[ColumnRolloutStepCount])
+	require.Equal(t, "25\n"+printer.UnsetValue, values[ColumnRolloutWeights])
+
+	// Nil pointers along the path display as unset.
+	node.Rollout = nil
+	require.Nil(t, node.Rollout.StepsFirst())
+	require.Equal(t, printer.UnsetValue, node.Rollout.GetStepsCount().Value())
+	require.Equal(t, printer.UnsetValue, node.Rollout.GetStepsWeight().Value())
+}
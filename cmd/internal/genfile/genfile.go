@@ -0,0 +1,96 @@
+// Package genfile writes the files of the code generators, or checks them or prints them instead.
+package genfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ErrStale is returned by Writer.Err in check mode when a file on disk differs from its
+// generated content.
+var ErrStale = errors.New("generated files are stale, run go generate")
+
+// Mode is how a Writer handles the generated files.
+type Mode int
+
+const (
+	// Write writes the files.
+	Write Mode = iota
+	// Stdout prints the files, each preceded by a comment with its path, and leaves the tree
+	// untouched.
+	Stdout
+	// Check compares the files with those on disk and prints a unified diff of the stale ones.
+	Check
+)
+
+// NewMode returns the mode of the --check and --stdout flags of the generators.
+func NewMode(check bool, stdout bool) (Mode, error) {
+	switch {
+	case check && stdout:
+		return Write, errors.New("--check and --stdout cannot be combined")
+	case check:
+		return Check, nil
+	case stdout:
+		return Stdout, nil
+	}
+	return Write, nil
+}
+
+// Writer handles the generated files according to its mode.
+type Writer struct {
+	mode  Mode
+	out   io.Writer
+	stale []string
+}
+
+// NewWriter returns a writer printing to out in the Stdout and Check modes.
+func NewWriter(mode Mode, out io.Writer) *Writer {
+	return &Writer{mode: mode, out: out}
+}
+
+// WriteFile writes, prints or checks the generated content of the file at path.
+func (w *Writer) WriteFile(path string, content []byte) error {
+	switch w.mode {
+	case Stdout:
+		_, err := fmt.Fprintf(w.out, "// %s\n%s", path, content)
+		return err
+	case Check:
+		current, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if bytes.Equal(current, content) {
+			return nil
+		}
+		w.stale = append(w.stale, path)
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current)),
+			B:        difflib.SplitLines(string(content)),
+			FromFile: path,
+			ToFile:   path + " (generated)",
+			Context:  3,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to diff %s: %w", path, err)
+		}
+		_, err = io.WriteString(w.out, diff)
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Err returns ErrStale, listing the stale files, if a checked file differs from the one on disk.
+func (w *Writer) Err() error {
+	if len(w.stale) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%v: %w", w.stale, ErrStale)
+}
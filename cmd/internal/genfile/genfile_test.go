@@ -0,0 +1,67 @@
+package genfile_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/cmd/internal/genfile"
+)
+
+func TestWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "example_gen.go")
+	content := []byte("package test\n\nconst A = 1\n")
+
+	t.Run("Write", func(t *testing.T) {
+		w := genfile.NewWriter(genfile.Write, nil)
+		require.NoError(t, w.WriteFile(path, content))
+		require.NoError(t, w.Err())
+
+		written, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, content, written)
+	})
+
+	t.Run("Check up to date", func(t *testing.T) {
+		var out bytes.Buffer
+		w := genfile.NewWriter(genfile.Check, &out)
+		require.NoError(t, w.WriteFile(path, content))
+		require.NoError(t, w.Err())
+		require.Empty(t, out.String())
+	})
+
+	t.Run("Check stale", func(t *testing.T) {
+		var out bytes.Buffer
+		w := genfile.NewWriter(genfile.Check, &out)
+		require.NoError(t, w.WriteFile(path, []byte("package test\n\nconst A = 2\n")))
+		require.NoError(t, w.WriteFile(filepath.Join(dir, "missing_gen.go"), content))
+		require.ErrorIs(t, w.Err(), genfile.ErrStale)
+		require.Contains(t, out.String(), "-const A = 1\n+const A = 2\n")
+		require.Contains(t, out.String(), "+++ "+filepath.Join(dir, "missing_gen.go")+" (generated)")
+
+		written, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, content, written)
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		w := genfile.NewWriter(genfile.Stdout, &out)
+		require.NoError(t, w.WriteFile(filepath.Join(dir, "other_gen.go"), content))
+		require.NoError(t, w.Err())
+		require.Equal(t, "// "+filepath.Join(dir, "other_gen.go")+"\n"+string(content), out.String())
+		require.NoFileExists(t, filepath.Join(dir, "other_gen.go"))
+	})
+
+	t.Run("Mode", func(t *testing.T) {
+		_, err := genfile.NewMode(true, true)
+		require.Error(t, err)
+		mode, err := genfile.NewMode(true, false)
+		require.NoError(t, err)
+		require.Equal(t, genfile.Check, mode)
+	})
+}
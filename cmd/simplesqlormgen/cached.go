@@ -0,0 +1,106 @@
+package main
+
+// cachedTableTemplate is the read-through caching decorator of the table API, caching the rows
+// read by Get and the GetBy methods in a simplesql.TableCache.
+const cachedTableTemplate = `
+// Code generated by github.com/msanath/gondolf/simplesqlorm. DO NOT EDIT.
+
+package {{.PkgName}}
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+// Cached{{.CamelCaseTableName}}Table caches the rows read by key from a {{.CamelCaseTableName}}TableAPI, and the
+// simplesql.ErrRecordNotFound of the keys matching none. Writes through it invalidate the cached
+// rows they change, see simplesql.TableCache.
+type Cached{{.CamelCaseTableName}}Table struct {
+	{{.CamelCaseTableName}}TableAPI
+	cache *simplesql.TableCache[{{.StructName}}]
+}
+
+var _ {{.CamelCaseTableName}}TableAPI = (*Cached{{.CamelCaseTableName}}Table)(nil)
+
+// NewCached{{.CamelCaseTableName}}Table caches the rows of the table in cache, e.g. a simplesql.LRUCache.
+func NewCached{{.CamelCaseTableName}}Table(table {{.CamelCaseTableName}}TableAPI, cache simplesql.Cache) *Cached{{.CamelCaseTableName}}Table {
+	return &Cached{{.CamelCaseTableName}}Table{
+		{{.CamelCaseTableName}}TableAPI: table,
+		cache: simplesql.NewTableCache({{.NonCamelCaseTableName}}TableName, cache, simplesql.TableCacheConfig[{{.StructName}}]{
+			PrimaryKey: func(row {{.StructName}}) []interface{} {
+				return []interface{}{ {{- range $i, $c := .Model.PrimaryKey}}{{if $i}}, {{end}}row.{{$c.Path}}{{end -}} }
+			},
+{{- if .Model.OpLock }}
+			Version: func(row {{.StructName}}) uint64 {
+				return uint64(row.{{.Model.OpLock.Path}})
+			},
+{{- end }}
+		}),
+	}
+}
+
+func (s *Cached{{.CamelCaseTableName}}Table) Insert(ctx context.Context, execer sqlx.ExecerContext, row {{.StructName}}) error {
+	err := s.{{.CamelCaseTableName}}TableAPI.Insert(ctx, execer, row)
+	s.cache.InvalidateNotFound(execer)
+	return err
+}
+
+func (s *Cached{{.CamelCaseTableName}}Table) Get(ctx context.Context, keys {{.CamelCaseTableName}}TableGetKeys) ({{.StructName}}, error) {
+	return s.cache.Get(ctx, keys, func() ({{.StructName}}, error) {
+		return s.{{.CamelCaseTableName}}TableAPI.Get(ctx, keys)
+	})
+}
+{{- range .UniqueKeys }}
+
+func (s *Cached{{$.CamelCaseTableName}}Table) {{.Method}}(ctx context.Context, {{.Params}}) ({{$.StructName}}, error) {
+	return s.cache.Get(ctx, {{.KeyType}}{ {{- .Args -}} }, func() ({{$.StructName}}, error) {
+		return s.{{$.CamelCaseTableName}}TableAPI.{{.Method}}(ctx, {{.ArgNames}})
+	})
+}
+{{- end }}
+
+func (s *Cached{{.CamelCaseTableName}}Table) Update(
+	ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey, updateFields {{.CamelCaseTableName}}TableUpdateFields,
+) error {
+	err := s.{{.CamelCaseTableName}}TableAPI.Update(ctx, execer, updateKey, updateFields)
+{{- if .Model.OpLock }}
+	var minVersion uint64
+	if err == nil {
+		minVersion = updateKey.{{.Model.OpLock.Field}} + 1
+	}
+	s.cache.Invalidate(ctx, execer, []interface{}{ {{- range $i, $c := .Model.PrimaryKey}}{{if $i}}, {{end}}updateKey.{{$c.Field}}{{end -}} }, minVersion)
+{{- else }}
+	s.cache.Invalidate(ctx, execer, []interface{}{ {{- range $i, $c := .Model.PrimaryKey}}{{if $i}}, {{end}}updateKey.{{$c.Field}}{{end -}} }, 0)
+{{- end }}
+	return err
+}
+
+func (s *Cached{{.CamelCaseTableName}}Table) Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey) error {
+	err := s.{{.CamelCaseTableName}}TableAPI.Delete(ctx, execer, updateKey)
+	s.cache.Invalidate(ctx, execer, []interface{}{ {{- range $i, $c := .Model.PrimaryKey}}{{if $i}}, {{end}}updateKey.{{$c.Field}}{{end -}} }, 0)
+	return err
+}
+
+func (s *Cached{{.CamelCaseTableName}}Table) UpdateWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, updateFields {{.CamelCaseTableName}}TableUpdateFields, opts ...simplesql.WhereOption,
+) (int64, error) {
+	n, err := s.{{.CamelCaseTableName}}TableAPI.UpdateWhere(ctx, execer, filters, updateFields, opts...)
+	s.cache.InvalidateAll(execer)
+	return n, err
+}
+
+func (s *Cached{{.CamelCaseTableName}}Table) DeleteWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, opts ...simplesql.WhereOption,
+) (int64, error) {
+	n, err := s.{{.CamelCaseTableName}}TableAPI.DeleteWhere(ctx, execer, filters, opts...)
+	s.cache.InvalidateAll(execer)
+	return n, err
+}
+
+// Stats returns the statistics of the reads of the cache.
+func (s *Cached{{.CamelCaseTableName}}Table) Stats() simplesql.CacheStats {
+	return s.cache.Stats()
+}
+`
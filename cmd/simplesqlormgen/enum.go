@@ -0,0 +1,58 @@
+package main
+
+// enumType is the Go enum type of a column tagged with enum, e.g.
+//
+//	State string `db:"state" orm:"enum=Pending,Active"`
+//
+// generates the ClusterState type with the ClusterStatePending and ClusterStateActive constants.
+// The update fields, the filters and the key parameters of the column are of the enum type, and
+// the generated tables reject invalid values before they reach SQL.
+type enumType struct {
+	// Type is the name of the enum type, e.g. ClusterState.
+	Type string
+	// Field is the name of the field in the generated structs, and Path selects it in the row.
+	Field string
+	Path  string
+	// Column is the name of the column.
+	Column string
+	// Values are the values of the column.
+	Values []enumValue
+	// Update is set when the update fields have the column.
+	Update bool
+	// Filters are the select filters of the column.
+	Filters []Filter
+}
+
+// enumValue is a value of an enum type.
+type enumValue struct {
+	// Const is the name of the constant, e.g. ClusterStatePending.
+	Const string
+	// Value is the value stored in the column, e.g. Pending.
+	Value string
+}
+
+// enumTypeName returns the name of the enum type of a field, e.g. ClusterState.
+func enumTypeName(camelCaseTableName, fieldName string) string {
+	return camelCaseTableName + fieldName
+}
+
+// enumTypes returns the enum types of the columns of the model.
+func enumTypes(m Model) []enumType {
+	var enums []enumType
+	for _, c := range m.Columns {
+		if len(c.Enum) == 0 {
+			continue
+		}
+		e := enumType{Type: c.EnumType, Field: c.Field, Path: c.Path, Column: c.Name, Update: c.Update}
+		for _, v := range c.Enum {
+			e.Values = append(e.Values, enumValue{Const: c.EnumType + snakeToCamel(v), Value: v})
+		}
+		for _, f := range m.Filters {
+			if f.Column == c.Name {
+				e.Filters = append(e.Filters, f)
+			}
+		}
+		enums = append(enums, e)
+	}
+	return enums
+}
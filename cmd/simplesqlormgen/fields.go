@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// rowField is a column of the row struct. The fields of embedded structs without a db tag are
+// columns of the row, as are those of struct fields tagged with a column prefix ending in an
+// underscore, e.g. `db:"audit_"`, as simplesql flattens them.
+type rowField struct {
+	// Var is the field.
+	Var *types.Var
+	// Name is the name of the field in the generated structs: the name of the field prefixed
+	// with those of the prefixed structs holding it, e.g. LimitsCPU.
+	Name string
+	// Path selects the field in the row, e.g. Limits.CPU.
+	Path string
+	// Column is the name of the column, prefixed, e.g. limit_cpu.
+	Column string
+	// Tag is the struct tag of the field.
+	Tag reflect.StructTag
+}
+
+// rowFields returns the columns of the row struct, in field order. Errors are reported at the
+// position of the field, e.g. two fields mapping to the same column.
+func rowFields(fset *token.FileSet, s *types.Struct) ([]rowField, error) {
+	fields := flattenFields(s, "", "", "")
+	byColumn := map[string]rowField{}
+	byName := map[string]rowField{}
+	for _, f := range fields {
+		if other, ok := byColumn[f.Column]; ok {
+			return nil, fmt.Errorf("%s: field %s: column %s is already mapped by field %s", fset.Position(f.Var.Pos()), f.Path, f.Column, other.Path)
+		}
+		if other, ok := byName[f.Name]; ok {
+			return nil, fmt.Errorf("%s: field %s: name %s is already used by field %s", fset.Position(f.Var.Pos()), f.Path, f.Name, other.Path)
+		}
+		byColumn[f.Column] = f
+		byName[f.Name] = f
+	}
+	return fields, nil
+}
+
+func flattenFields(s *types.Struct, pathPrefix, namePrefix, columnPrefix string) []rowField {
+	var fields []rowField
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		tag := reflect.StructTag(s.Tag(i))
+		dbTag := tag.Get("db")
+		if dbTag == "-" {
+			continue
+		}
+		path := pathPrefix + field.Name()
+		nested, isNested := nestedStruct(field.Type())
+		switch {
+		case dbTag == "" && field.Embedded() && isNested:
+			fields = append(fields, flattenFields(nested, path+".", namePrefix, columnPrefix)...)
+			continue
+		case strings.HasSuffix(dbTag, "_") && field.Exported() && isNested:
+			fields = append(fields, flattenFields(nested, path+".", namePrefix+field.Name(), columnPrefix+dbTag)...)
+			continue
+		case dbTag == "":
+			continue
+		}
+		fields = append(fields, rowField{
+			Var:    field,
+			Name:   namePrefix + field.Name(),
+			Path:   path,
+			Column: columnPrefix + dbTag,
+			Tag:    tag,
+		})
+	}
+	return fields
+}
+
+// nestedStruct returns the struct of a type whose fields are columns, rather than the type being
+// the value of a column like time.Time, simplesql.Nullable or a sql.Scanner.
+func nestedStruct(t types.Type) (*types.Struct, bool) {
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok || t.String() == "time.Time" {
+		return nil, false
+	}
+	methods := types.NewMethodSet(types.NewPointer(t))
+	for _, name := range []string{"Scan", "Value"} {
+		if methods.Lookup(nil, name) != nil {
+			return nil, false
+		}
+	}
+	return s, true
+}
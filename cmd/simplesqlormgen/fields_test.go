@@ -0,0 +1,86 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowFields(t *testing.T) {
+	parse := func(src string) (*token.FileSet, *types.Struct) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "row.go", src, 0)
+		require.NoError(t, err)
+		pkg, err := (&types.Config{Importer: importer.Default()}).Check("test", fset, []*ast.File{file}, nil)
+		require.NoError(t, err)
+		return fset, pkg.Scope().Lookup("Row").Type().Underlying().(*types.Struct)
+	}
+
+	fset, s := parse(`package test
+
+import "time"
+
+type Audit struct {
+	CreatedBy string    ` + "`db:\"created_by\"`" + `
+	CreatedAt time.Time ` + "`db:\"created_at\"`" + `
+}
+
+type Quota struct {
+	CPU     int64 ` + "`db:\"cpu\"`" + `
+	Ignored int64
+}
+
+type Row struct {
+	ID string ` + "`db:\"id\"`" + `
+	Audit
+	Limits   Quota     ` + "`db:\"limit_\"`" + `
+	Reserved Quota     ` + "`db:\"reserved_\"`" + `
+	Updated  time.Time ` + "`db:\"updated_at\"`" + `
+	Skipped  Quota     ` + "`db:\"-\"`" + `
+}
+`)
+	fields, err := rowFields(fset, s)
+	require.NoError(t, err)
+	var names, paths, columns []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+		paths = append(paths, f.Path)
+		columns = append(columns, f.Column)
+	}
+	require.Equal(t, []string{"ID", "CreatedBy", "CreatedAt", "LimitsCPU", "ReservedCPU", "Updated"}, names)
+	require.Equal(t, []string{"ID", "Audit.CreatedBy", "Audit.CreatedAt", "Limits.CPU", "Reserved.CPU", "Updated"}, paths)
+	require.Equal(t, []string{"id", "created_by", "created_at", "limit_cpu", "reserved_cpu", "updated_at"}, columns)
+
+	fset, s = parse(`package test
+
+type Audit struct {
+	CreatedBy string ` + "`db:\"created_by\"`" + `
+}
+
+type Row struct {
+	Audit
+	Owner string ` + "`db:\"created_by\"`" + `
+}
+`)
+	_, err = rowFields(fset, s)
+	require.EqualError(t, err, "row.go:9:2: field Owner: column created_by is already mapped by field Audit.CreatedBy")
+
+	fset, s = parse(`package test
+
+type Quota struct {
+	CPU int64 ` + "`db:\"cpu\"`" + `
+}
+
+type Row struct {
+	LimitsCPU int64 ` + "`db:\"max_cpu\"`" + `
+	Limits    Quota ` + "`db:\"limit_\"`" + `
+}
+`)
+	_, err = rowFields(fset, s)
+	require.EqualError(t, err, "row.go:4:2: field Limits.CPU: name LimitsCPU is already used by field LimitsCPU")
+}
@@ -1,17 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"go/format"
 	"go/types"
-	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
-	"reflect"
+	"slices"
 	"strings"
-	"text/template"
 
 	"golang.org/x/tools/go/packages"
+
+	"github.com/msanath/gondolf/cmd/internal/genfile"
 )
 
 const bodyTemplate = `
@@ -21,13 +23,62 @@ package {{.PkgName}}
 
 import (
 	"context"
+{{- if .Enums }}
+	"fmt"
+{{- end }}
 
 	"github.com/jmoiron/sqlx"
 	"github.com/msanath/gondolf/pkg/simplesql"
+{{- range .Imports }}
+	{{ . }}
+{{- end }}
 )
 
 const {{.NonCamelCaseTableName}}TableName = "{{.TableName}}"
 
+// Columns of the {{.TableName}} table.
+const (
+{{- range .Model.Columns }}
+	{{$.CamelCaseTableName}}TableColumn{{.Field}} = "{{.Name}}"
+{{- end }}
+)
+
+var {{.NonCamelCaseTableName}}TableColumns = []string{
+{{- range .Model.Columns }}
+	{{$.CamelCaseTableName}}TableColumn{{.Field}},
+{{- end }}
+}
+{{- range $enum := .Enums }}
+
+// {{.Type}} is a value of the {{.Column}} column of the {{$.TableName}} table.
+type {{.Type}} string
+
+const (
+{{- range .Values }}
+	{{.Const}} {{$enum.Type}} = "{{.Value}}"
+{{- end }}
+)
+
+// {{.Type}}Values returns the values of {{.Type}}.
+func {{.Type}}Values() []{{.Type}} {
+	return []{{.Type}}{ {{- range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.Const}}{{end -}} }
+}
+
+// Validate returns an error matching simplesql.ErrCheck if the value is not one of {{.Type}}Values.
+func (v {{.Type}}) Validate() error {
+	switch v {
+	case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.Const}}{{end}}:
+		return nil
+	}
+	return &simplesql.Error{
+		Kind:    simplesql.KindCheck,
+		Table:   {{$.NonCamelCaseTableName}}TableName,
+		Columns: []string{ {{- $.CamelCaseTableName}}TableColumn{{.Field -}} },
+		Err:     fmt.Errorf("invalid value '%s' of column {{.Column}}", string(v)),
+	}
+}
+{{- end }}
+
 
 type {{.CamelCaseTableName}}TableGetKeys struct {
 {{.GetKeysFields}}
@@ -44,6 +95,78 @@ type {{.CamelCaseTableName}}TableUpdateFields struct {
 type {{.CamelCaseTableName}}TableSelectFilters struct {
 {{.SelectFilters}}
 }
+{{- range .UniqueKeys }}
+
+type {{.KeyType}} struct {
+{{.Fields}}
+}
+{{- end }}
+{{- if .Enums }}
+
+// validate{{.CamelCaseTableName}}Row rejects the invalid enum values of the row before they reach SQL.
+func validate{{.CamelCaseTableName}}Row(row {{.StructName}}) error {
+{{- range .Enums }}
+	if err := {{.Type}}(row.{{.Path}}).Validate(); err != nil {
+		return err
+	}
+{{- end }}
+	return nil
+}
+
+func (f {{.CamelCaseTableName}}TableUpdateFields) validate() error {
+{{- range .Enums }}
+{{- if .Update }}
+	if f.{{.Field}} != nil {
+		if err := f.{{.Field}}.Validate(); err != nil {
+			return err
+		}
+	}
+{{- end }}
+{{- end }}
+	return nil
+}
+
+func (f {{.CamelCaseTableName}}TableSelectFilters) validate() error {
+{{- range .Enums }}
+{{- range .Filters }}
+{{- if eq .Operator "In" "NotIn" }}
+	for _, v := range f.{{.Field}} {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+{{- else }}
+	if f.{{.Field}} != nil {
+		if err := f.{{.Field}}.Validate(); err != nil {
+			return err
+		}
+	}
+{{- end }}
+{{- end }}
+{{- end }}
+	return nil
+}
+{{- end }}
+
+// {{.CamelCaseTableName}}TableAPI is implemented by {{.CamelCaseTableName}}Table and by the in-memory {{.CamelCaseTableName}}MemoryTable.
+type {{.CamelCaseTableName}}TableAPI interface {
+	Insert(ctx context.Context, execer sqlx.ExecerContext, row {{.StructName}}) error
+	Get(ctx context.Context, keys {{.CamelCaseTableName}}TableGetKeys) ({{.StructName}}, error)
+{{- range .UniqueKeys }}
+	{{.Method}}(ctx context.Context, {{.Params}}) ({{$.StructName}}, error)
+{{- end }}
+	Update(ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey, updateFields {{.CamelCaseTableName}}TableUpdateFields) error
+	Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey) error
+	List(ctx context.Context, filters {{.CamelCaseTableName}}TableSelectFilters) ([]{{.StructName}}, error)
+	UpdateWhere(
+		ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, updateFields {{.CamelCaseTableName}}TableUpdateFields, opts ...simplesql.WhereOption,
+	) (int64, error)
+	DeleteWhere(ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, opts ...simplesql.WhereOption) (int64, error)
+	// Columns returns the columns of the table, in the order of the fields of the row.
+	Columns() []string
+}
+
+var _ {{.CamelCaseTableName}}TableAPI = (*{{.CamelCaseTableName}}Table)(nil)
 
 type {{.CamelCaseTableName}}Table struct {
 	simplesql.Database
@@ -51,6 +174,8 @@ type {{.CamelCaseTableName}}Table struct {
 }
 
 func New{{.CamelCaseTableName}}Table(db simplesql.Database) *{{.CamelCaseTableName}}Table {
+	// simplesql derives the columns it maintains, such as the automatic timestamps, from the row.
+	db.RegisterTable({{.NonCamelCaseTableName}}TableName, {{.StructName}}{})
 	return &{{.CamelCaseTableName}}Table{
 		Database:  db,
 		tableName: {{.NonCamelCaseTableName}}TableName,
@@ -58,9 +183,15 @@ func New{{.CamelCaseTableName}}Table(db simplesql.Database) *{{.CamelCaseTableNa
 }
 
 func (s *{{.CamelCaseTableName}}Table) Insert(ctx context.Context, execer sqlx.ExecerContext, row {{.StructName}}) error {
+{{- if .Enums }}
+	if err := validate{{.CamelCaseTableName}}Row(row); err != nil {
+		return err
+	}
+{{- end }}
 	return s.Database.Insert(ctx, execer, s.tableName, row)
 }
 
+// Get returns a row matching the set keys. The GetBy methods of the unique keys match a single row.
 func (s *{{.CamelCaseTableName}}Table) Get(ctx context.Context, keys {{.CamelCaseTableName}}TableGetKeys) ({{.StructName}}, error) {
 	var row {{.StructName}}
 	err := s.Database.Get(ctx, s.tableName, keys, &row)
@@ -69,18 +200,40 @@ func (s *{{.CamelCaseTableName}}Table) Get(ctx context.Context, keys {{.CamelCas
 	}
 	return row, nil
 }
+{{- range .UniqueKeys }}
+
+// {{.Method}} returns the row of the unique key ({{.Columns}}).
+func (s *{{$.CamelCaseTableName}}Table) {{.Method}}(ctx context.Context, {{.Params}}) ({{$.StructName}}, error) {
+	var row {{$.StructName}}
+	err := s.Database.Get(ctx, s.tableName, {{.KeyType}}{ {{- .Args -}} }, &row)
+	if err != nil {
+		return {{$.StructName}}{}, err
+	}
+	return row, nil
+}
+{{- end }}
 
 func (s *{{.CamelCaseTableName}}Table) Update(
 	ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey, updateFields {{.CamelCaseTableName}}TableUpdateFields,
 ) error {
+{{- if .Enums }}
+	if err := updateFields.validate(); err != nil {
+		return err
+	}
+{{- end }}
 	return s.Database.Update(ctx, execer, s.tableName, updateKey, updateFields)
 }
 
 func (s *{{.CamelCaseTableName}}Table) Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey) error {
-	return s.Database.Delete(ctx, s.tableName, updateKey)
+	return s.Database.Delete(ctx, execer, s.tableName, updateKey)
 }
 
 func (s *{{.CamelCaseTableName}}Table) List(ctx context.Context, filters {{.CamelCaseTableName}}TableSelectFilters) ([]{{.StructName}}, error) {
+{{- if .Enums }}
+	if err := filters.validate(); err != nil {
+		return nil, err
+	}
+{{- end }}
 	var rows []{{.StructName}}
 	err := s.Database.List(ctx, s.tableName, filters, &rows)
 	if err != nil {
@@ -88,9 +241,72 @@ func (s *{{.CamelCaseTableName}}Table) List(ctx context.Context, filters {{.Came
 	}
 	return rows, nil
 }
+
+// UpdateWhere updates the rows matching the filters and returns their number.
+// See simplesql.Database.UpdateWhere.
+func (s *{{.CamelCaseTableName}}Table) UpdateWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, updateFields {{.CamelCaseTableName}}TableUpdateFields, opts ...simplesql.WhereOption,
+) (int64, error) {
+{{- if .Enums }}
+	if err := filters.validate(); err != nil {
+		return 0, err
+	}
+{{- end }}
+{{- if .Enums }}
+	if err := updateFields.validate(); err != nil {
+		return 0, err
+	}
+{{- end }}
+{{- if .OpLock }}
+	opts = append([]simplesql.WhereOption{simplesql.BumpVersion()}, opts...)
+{{- end }}
+	return s.Database.UpdateWhere(ctx, execer, s.tableName, filters, updateFields, opts...)
+}
+
+// DeleteWhere deletes the rows matching the filters and returns their number.
+// See simplesql.Database.DeleteWhere.
+func (s *{{.CamelCaseTableName}}Table) DeleteWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, opts ...simplesql.WhereOption,
+) (int64, error) {
+{{- if .Enums }}
+	if err := filters.validate(); err != nil {
+		return 0, err
+	}
+{{- end }}
+	return s.Database.DeleteWhere(ctx, execer, s.tableName, filters, opts...)
+}
+
+func (s *{{.CamelCaseTableName}}Table) Columns() []string {
+	return append([]string(nil), {{.NonCamelCaseTableName}}TableColumns...)
+}
+
+func (s *{{.CamelCaseTableName}}Table) VerifySchema(ctx context.Context) (simplesql.SchemaDiff, error) {
+	return s.Database.VerifySchema(ctx, s.tableName, {{.StructName}}{})
+}
+{{- if .Audit }}
+
+type {{.CamelCaseTableName}}TableHistoryKey struct {
+{{.HistoryKeyFields}}
+}
+
+// History returns the changes of the rows matching the key, oldest first.
+// The table must be audited with simplesql.WithAudit({{.NonCamelCaseTableName}}TableName).
+func (s *{{.CamelCaseTableName}}Table) History(ctx context.Context, key {{.CamelCaseTableName}}TableHistoryKey) ([]simplesql.HistoryRecord[{{.StructName}}], error) {
+	entries, err := s.Database.History(ctx, s.tableName, key)
+	if err != nil {
+		return nil, err
+	}
+	return simplesql.DecodeHistory[{{.StructName}}](entries)
+}
+{{- end }}
 `
 
+// generator holds the data of the templates. Templates of a template directory should only use
+// Model, which is versioned, the other fields are snippets of the built-in templates.
 type generator struct {
+	// Model is the parsed row struct.
+	Model Model
+
 	OutputPath            string
 	PkgName               string
 	TableName             string
@@ -102,14 +318,37 @@ type generator struct {
 	SelectFilters         string
 	StructName            string
 	StructType            *types.Struct
+	Imports               []string
+	// OpLock is set when the row has an op_lock version, which bulk updates increment.
+	OpLock bool
+	// UniqueKeys are the primary key and the unique indexes, each with a GetBy method.
+	UniqueKeys []uniqueKey
+	// Enums are the enum types of the columns tagged with enum.
+	Enums []enumType
+
+	// Audit enables generation of the History accessor and of the history table migrations.
+	Audit            bool
+	HistoryKeyFields string
+
+	// Cache enables generation of the caching decorator of the table.
+	Cache bool
+
+	// GenerateMigrations enables generation of the table migrations starting at MigrationVersion.
+	GenerateMigrations bool
+	MigrationVersion   int
+	Dialects           []migrationDialect
+	Migrations         map[string][]renderedMigration
+
+	// fields are the columns of StructType, and ormTags their parsed orm tags.
+	fields  []rowField
+	ormTags []ormTag
+	// files writes, prints or checks the generated files.
+	files *genfile.Writer
+	// templates are the templates of the generated files.
+	templates templates
 }
 
-func newGenerator(o ORMGenOptions) (*generator, error) {
-	pkg, err := getCurrentPackage()
-	if err != nil {
-		return nil, err
-	}
-
+func newGenerator(pkg *packages.Package, o ORMGenOptions, files *genfile.Writer, templates templates) (*generator, error) {
 	obj := pkg.Types.Scope().Lookup(o.StructName)
 	if obj == nil {
 		return nil, fmt.Errorf("type '%s' not found", o.StructName)
@@ -120,95 +359,244 @@ func newGenerator(o ORMGenOptions) (*generator, error) {
 		return nil, fmt.Errorf("type '%s' must be a struct", o.StructName)
 	}
 
-	getKeys, updateKey, updateFields, selectFilters := parseStructFields(s)
-	return &generator{
+	g := &generator{}
+	qualifier := g.qualifier(pkg.Types.Path())
+	fields, err := rowFields(pkg.Fset, s)
+	if err != nil {
+		return nil, err
+	}
+	ormTags, err := parseORMTags(pkg.Fset, fields)
+	if err != nil {
+		return nil, err
+	}
+	camelCaseTableName := snakeToCamel(o.TableName)
+	getKeys, updateKey, updateFields, selectFilters := parseStructFields(fields, ormTags, camelCaseTableName, qualifier)
+	historyKey := historyKeyFields(fields, ormTags, camelCaseTableName, qualifier)
+	// The qualifier appends to g.Imports, which must be read after every call.
+	keys := uniqueKeys(fields, ormTags, camelCaseTableName, qualifier)
+	*g = generator{
 		PkgName:               pkg.Name,
 		TableName:             o.TableName,
-		CamelCaseTableName:    snakeToCamel(o.TableName),
+		CamelCaseTableName:    camelCaseTableName,
 		NonCamelCaseTableName: strings.ToLower(o.TableName),
 		GetKeysFields:         getKeys,
 		UpdateKeyFields:       updateKey,
 		UpdateFields:          updateFields,
 		SelectFilters:         selectFilters,
 		StructName:            o.StructName,
-	}, nil
+		StructType:            s,
+		Imports:               g.Imports,
+		GenerateMigrations:    o.Migrations,
+		MigrationVersion:      o.MigrationVersion,
+		Audit:                 o.Audit,
+		Cache:                 o.Cache,
+		HistoryKeyFields:      historyKey,
+		OpLock:                hasOpLock(ormTags),
+		UniqueKeys:            keys,
+		fields:                fields,
+		ormTags:               ormTags,
+		files:                 files,
+		templates:             templates,
+	}
+	g.Model = newModel(g, fields, ormTags, qualifier)
+	g.Enums = enumTypes(g.Model)
+	if g.Cache && len(g.Model.PrimaryKey) == 0 {
+		// The cached rows are invalidated by primary key.
+		return nil, fmt.Errorf("type '%s' must have a primary key to be cached", o.StructName)
+	}
+	return g, nil
 }
 
-func parseStructFields(s *types.Struct) (getKeys, updateKey, updateFields, selectFilters string) {
+// hasOpLock returns true if a field of the row is the version of optimistic locking.
+func hasOpLock(ormTags []ormTag) bool {
+	for _, tag := range ormTags {
+		if tag.OpLock {
+			return true
+		}
+	}
+	return false
+}
+
+// historyKeyFields returns the fields of the key selecting the history of rows: the primary key.
+func historyKeyFields(fields []rowField, ormTags []ormTag, camelCaseTableName string, qualifier types.Qualifier) string {
+	keyFields := ""
+	for i, f := range fields {
+		if !ormTags[i].PrimaryKey {
+			continue
+		}
+		keyFields += fmt.Sprintf("%s %s `db:\"%s\"`\n", f.Name, columnFieldTypes(f, ormTags[i], camelCaseTableName, qualifier).optional, f.Column)
+	}
+	return keyFields
+}
+
+func parseStructFields(fields []rowField, ormTags []ormTag, camelCaseTableName string, qualifier types.Qualifier) (getKeys, updateKey, updateFields, selectFilters string) {
 	getKeys = ""
 	updateKey = ""
 	updateFields = ""
 	selectFilters = ""
 
-	// TODO: This is a bit ugly. Refactor this to be more readable.
-	for i := 0; i < s.NumFields(); i++ {
-		field := s.Field(i)
-		fieldName := field.Name()
+	tenantColumn := ""
 
-		tags := reflect.StructTag(s.Tag(i))
+	for i, field := range fields {
+		fieldName := field.Name
+		tag := ormTags[i]
+		dbTag := field.Column
 
-		ormTags := tags.Get("orm")
-		if ormTags == "" {
+		// The tenant is set by simplesql from the context, it is not part of the keys or filters.
+		if tag.Tenant {
+			tenantColumn = dbTag
 			continue
 		}
 
-		dbTag := tags.Get("db")
-		if dbTag == "" {
-			continue
-		}
+		ft := columnFieldTypes(field, tag, camelCaseTableName, qualifier)
 
-		if strings.Contains(ormTags, "op=get") {
-			getKeys += fmt.Sprintf("%s *%s `db:\"%s\"`\n", fieldName, field.Type().String(), dbTag)
-		}
-		if strings.Contains(ormTags, "soft_delete=true") {
-			getKeys += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, field.Type().String(), dbTag)
-			updateKey += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, field.Type().String(), dbTag)
-			updateFields += fmt.Sprintf("%s *%s `db:\"%s\"`\n", fieldName, field.Type().String(), dbTag)
-			selectFilters += fmt.Sprintf("%sEq *%s `db:\"%s:eq\"`\n", fieldName, field.Type().String(), dbTag)
-			selectFilters += fmt.Sprintf("%sGte *%s `db:\"%s:gte\"`\n", fieldName, field.Type().String(), dbTag)
+		if tag.Get {
+			getKeys += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, ft.optional, dbTag)
 		}
-		if strings.Contains(ormTags, "key=primary") {
-			updateKey += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, field.Type().String(), dbTag)
+		if tag.SoftDelete {
+			getKeys += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, ft.typ, dbTag)
+			updateKey += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, ft.typ, dbTag)
+			updateFields += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, ft.optional, dbTag)
 		}
-		if strings.Contains(ormTags, "op_lock=true") {
-			updateKey += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, field.Type().String(), dbTag)
+		if tag.PrimaryKey {
+			updateKey += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, ft.typ, dbTag)
 		}
-		if strings.Contains(ormTags, "op=update") {
-			updateFields += fmt.Sprintf("%s *%s `db:\"%s\"`\n", fieldName, field.Type().String(), dbTag)
+		if tag.OpLock {
+			updateKey += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, ft.typ, dbTag)
 		}
-		if strings.Contains(ormTags, "filter") {
-			if strings.Contains(ormTags, "In") {
-				selectFilters += fmt.Sprintf("%sIn []%s `db:\"%s:in\"`\n", fieldName, field.Type().String(), dbTag)
-			}
-			if strings.Contains(ormTags, "NotIn") {
-				selectFilters += fmt.Sprintf("%sNotIn []%s `db:\"%s:not_in\"`\n", fieldName, field.Type().String(), dbTag)
-			}
-			if strings.Contains(ormTags, "Gte") {
-				selectFilters += fmt.Sprintf("%sGte *%s `db:\"%s:gte\"`\n", fieldName, field.Type().String(), dbTag)
-			}
-			if strings.Contains(ormTags, "Lte") {
-				selectFilters += fmt.Sprintf("%sLte *%s `db:\"%s:lte\"`\n", fieldName, field.Type().String(), dbTag)
-			}
-			if strings.Contains(ormTags, "Eq") {
-				selectFilters += fmt.Sprintf("%sEq *%s `db:\"%s:eq\"`\n", fieldName, field.Type().String(), dbTag)
+		if tag.Update {
+			if tag.JSON {
+				// simplesql marshals the field only if the update field is also tagged as json.
+				updateFields += fmt.Sprintf("%s %s `db:\"%s\" orm:\"json\"`\n", fieldName, ft.optional, dbTag)
+			} else {
+				updateFields += fmt.Sprintf("%s %s `db:\"%s\"`\n", fieldName, ft.optional, dbTag)
 			}
 		}
+		for _, filter := range columnFilters(fieldName, dbTag, tag, ft) {
+			selectFilters += fmt.Sprintf("%s %s `db:\"%s\"`\n", filter.Field, filter.Type, filter.Tag())
+		}
 	}
 
 	selectFilters += "Limit uint32 `db:\"limit\"`"
+
+	if tenantColumn != "" {
+		// A blank field declares the tenant column so that simplesql scopes every query to the
+		// tenant of the context.
+		marker := fmt.Sprintf("// Scoped to the tenant of the context.\n_ struct{} `db:\"%s\" orm:\"tenant\"`\n", tenantColumn)
+		getKeys = marker + getKeys
+		updateKey = marker + updateKey
+		selectFilters = marker + selectFilters
+	}
 	return getKeys, updateKey, updateFields, selectFilters
 }
 
+const simplesqlPkgPath = "github.com/msanath/gondolf/pkg/simplesql"
+
+// fieldTypes is how the type of a row struct field is rendered in the generated structs.
+type fieldTypes struct {
+	// typ is the type of the field.
+	typ string
+	// optional is the type used where a value may be left unset: *T, or simplesql.Nullable[T]
+	// for nullable columns so that unset and NULL can be told apart.
+	optional string
+	// elem is the non-nullable type of the column values.
+	elem string
+}
+
+func newFieldTypes(t types.Type, qualifier types.Qualifier) fieldTypes {
+	if elem, ok := nullableElem(t); ok {
+		elemStr := types.TypeString(elem, qualifier)
+		return fieldTypes{
+			typ:      types.TypeString(t, qualifier),
+			optional: fmt.Sprintf("simplesql.Nullable[%s]", elemStr),
+			elem:     elemStr,
+		}
+	}
+	typ := types.TypeString(t, qualifier)
+	return fieldTypes{typ: typ, optional: "*" + typ, elem: typ}
+}
+
+// columnFieldTypes returns the field types of a row struct field. The values of enum columns are
+// of their enum type.
+func columnFieldTypes(field rowField, tag ormTag, camelCaseTableName string, qualifier types.Qualifier) fieldTypes {
+	ft := newFieldTypes(field.Var.Type(), qualifier)
+	if len(tag.Enum) > 0 {
+		enum := enumTypeName(camelCaseTableName, field.Name)
+		ft.optional = "*" + enum
+		ft.elem = enum
+	}
+	return ft
+}
+
+// nullableElem returns T if the type of a nullable column, *T or simplesql.Nullable[T].
+func nullableElem(t types.Type) (types.Type, bool) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		return ptr.Elem(), true
+	}
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == simplesqlPkgPath && obj.Name() == "Nullable" {
+			return named.TypeArgs().At(0), true
+		}
+	}
+	return nil, false
+}
+
+// qualifier renders types of the generated package unqualified and records the imports
+// needed for types of other packages.
+func (g *generator) qualifier(pkgPath string) types.Qualifier {
+	return func(p *types.Package) string {
+		if p.Path() == pkgPath {
+			return ""
+		}
+		if p.Path() != simplesqlPkgPath {
+			imp := fmt.Sprintf("%q", p.Path())
+			if p.Name() != path.Base(p.Path()) {
+				imp = p.Name() + " " + imp
+			}
+			if !slices.Contains(g.Imports, imp) {
+				g.Imports = append(g.Imports, imp)
+			}
+		}
+		return p.Name()
+	}
+}
+
 func (g *generator) Generate() error {
-	err := executeTemplate("body", bodyTemplate, g.OutputPath, fmt.Sprintf("%s_table_gen.go", g.TableName), g)
+	err := g.executeTemplate(tableTemplate, g.generatedFileName(tableTemplate))
 	if err != nil {
 		return fmt.Errorf("failed to generate file: %w", err)
 	}
-	return g.tidyGeneratedFile()
+	err = g.executeTemplate(memoryTemplate, g.generatedFileName(memoryTemplate))
+	if err != nil {
+		return fmt.Errorf("failed to generate memory table: %w", err)
+	}
+	if g.Cache {
+		if err := g.executeTemplate(cachedTemplate, g.generatedFileName(cachedTemplate)); err != nil {
+			return fmt.Errorf("failed to generate cached table: %w", err)
+		}
+	}
+	if g.GenerateMigrations {
+		if err := g.generateMigrations(); err != nil {
+			return fmt.Errorf("failed to generate migrations: %w", err)
+		}
+	}
+	for _, name := range g.templates.extras {
+		if err := g.executeTemplate(name, g.generatedFileName(name)); err != nil {
+			return fmt.Errorf("failed to generate template '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// generatedFileName returns the name of the file generated by the template, e.g.
+// cluster_table_gen.go.
+func (g *generator) generatedFileName(templateName string) string {
+	return fmt.Sprintf("%s_%s_gen.go", g.TableName, templateName)
 }
 
 func getCurrentPackage() (*packages.Package, error) {
-	cfg := &packages.Config{Mode: packages.NeedTypes | packages.NeedTypesInfo | packages.NeedName | packages.NeedImports | packages.NeedDeps}
+	cfg := &packages.Config{Mode: packages.NeedTypes | packages.NeedTypesInfo | packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax}
 
 	pkgs, err := packages.Load(cfg, ".")
 	if err != nil {
@@ -229,30 +617,22 @@ func getCurrentPackage() (*packages.Package, error) {
 	return nil, errors.New("no package found")
 }
 
-func executeTemplate(templateName, templateStr, path, fileName string, data any) error {
-	// Generate the records file
-	filePath := filepath.Join(path, fileName)
-	// Check if the file already exists. If so delete it and recreate it.
-	if _, err := os.Stat(filePath); err == nil {
-		if err := os.Remove(filePath); err != nil {
-			return fmt.Errorf("failed to remove existing record file: %w", err)
-		}
+// executeTemplate renders the template into the file, formatted with go/format. Extra templates
+// rendering only white space generate no file.
+func (g *generator) executeTemplate(templateName, fileName string) error {
+	var buf bytes.Buffer
+	if err := g.templates.byName[templateName].Execute(&buf, g); err != nil {
+		return fmt.Errorf("failed to execute record template: %w", err)
 	}
-
-	// Create the record file
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create record file: %w", err)
+	if _, builtin := builtinTemplates[templateName]; !builtin && len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return nil
 	}
-	defer f.Close()
-
-	// Execute the template
-	t := template.Must(template.New(templateName).Parse(templateStr))
-	err = t.Execute(f, data)
+	content, err := format.Source(buf.Bytes())
 	if err != nil {
-		return fmt.Errorf("failed to execute record template: %w", err)
+		return fmt.Errorf("failed to format %s: %w", fileName, err)
 	}
-	return nil
+
+	return g.files.WriteFile(filepath.Join(g.OutputPath, fileName), content)
 }
 
 func snakeToCamel(s string) string {
@@ -265,25 +645,3 @@ func snakeToCamel(s string) string {
 	}
 	return strings.Join(parts, "")
 }
-
-func (g *generator) tidyGeneratedFile() error {
-	tidyCmd := exec.Command("go", "mod", "tidy")
-	tidyCmd.Dir = g.OutputPath // Set the working directory to the destination path
-	tidyCmd.Stdout = os.Stdout
-	tidyCmd.Stderr = os.Stderr
-	if err := tidyCmd.Run(); err != nil {
-		fmt.Println("Error running 'go mod tidy':", err)
-		return err
-	}
-
-	fmtCmd := exec.Command("go", "fmt", "./...")
-	fmtCmd.Dir = g.OutputPath // Set the working directory to the destination path
-	fmtCmd.Stdout = os.Stdout
-	fmtCmd.Stderr = os.Stderr
-	if err := fmtCmd.Run(); err != nil {
-		fmt.Println("Error running 'go fmt':", err)
-		return err
-	}
-
-	return nil
-}
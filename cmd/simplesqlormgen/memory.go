@@ -0,0 +1,107 @@
+package main
+
+// memoryTableTemplate is the in-memory implementation of the table API, for tests of code using the
+// table without a database. It is backed by simplesql.MemoryTable.
+const memoryTableTemplate = `
+// Code generated by github.com/msanath/gondolf/simplesqlorm. DO NOT EDIT.
+
+package {{.PkgName}}
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+// {{.CamelCaseTableName}}MemoryTable is an in-memory {{.CamelCaseTableName}}TableAPI for tests. Execers are ignored and
+// changes are applied immediately. See simplesql.MemoryTable.
+type {{.CamelCaseTableName}}MemoryTable struct {
+	table *simplesql.MemoryTable[{{.StructName}}]
+}
+
+var _ {{.CamelCaseTableName}}TableAPI = (*{{.CamelCaseTableName}}MemoryTable)(nil)
+
+func New{{.CamelCaseTableName}}MemoryTable(opts ...simplesql.MemoryTableOption) *{{.CamelCaseTableName}}MemoryTable {
+	return &{{.CamelCaseTableName}}MemoryTable{
+		table: simplesql.NewMemoryTable[{{.StructName}}]({{.NonCamelCaseTableName}}TableName, opts...),
+	}
+}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) Insert(ctx context.Context, execer sqlx.ExecerContext, row {{.StructName}}) error {
+{{- if .Enums }}
+	if err := validate{{.CamelCaseTableName}}Row(row); err != nil {
+		return err
+	}
+{{- end }}
+	return s.table.Insert(ctx, row)
+}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) Get(ctx context.Context, keys {{.CamelCaseTableName}}TableGetKeys) ({{.StructName}}, error) {
+	return s.table.Get(ctx, keys)
+}
+{{- range .UniqueKeys }}
+
+func (s *{{$.CamelCaseTableName}}MemoryTable) {{.Method}}(ctx context.Context, {{.Params}}) ({{$.StructName}}, error) {
+	return s.table.Get(ctx, {{.KeyType}}{ {{- .Args -}} })
+}
+{{- end }}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) Update(
+	ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey, updateFields {{.CamelCaseTableName}}TableUpdateFields,
+) error {
+{{- if .Enums }}
+	if err := updateFields.validate(); err != nil {
+		return err
+	}
+{{- end }}
+	return s.table.Update(ctx, updateKey, updateFields)
+}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey {{.CamelCaseTableName}}TableUpdateKey) error {
+	return s.table.Delete(ctx, updateKey)
+}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) List(ctx context.Context, filters {{.CamelCaseTableName}}TableSelectFilters) ([]{{.StructName}}, error) {
+{{- if .Enums }}
+	if err := filters.validate(); err != nil {
+		return nil, err
+	}
+{{- end }}
+	return s.table.List(ctx, filters)
+}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) UpdateWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, updateFields {{.CamelCaseTableName}}TableUpdateFields, opts ...simplesql.WhereOption,
+) (int64, error) {
+{{- if .Enums }}
+	if err := filters.validate(); err != nil {
+		return 0, err
+	}
+{{- end }}
+{{- if .Enums }}
+	if err := updateFields.validate(); err != nil {
+		return 0, err
+	}
+{{- end }}
+{{- if .OpLock }}
+	opts = append([]simplesql.WhereOption{simplesql.BumpVersion()}, opts...)
+{{- end }}
+	return s.table.UpdateWhere(ctx, filters, updateFields, opts...)
+}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) DeleteWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters {{.CamelCaseTableName}}TableSelectFilters, opts ...simplesql.WhereOption,
+) (int64, error) {
+{{- if .Enums }}
+	if err := filters.validate(); err != nil {
+		return 0, err
+	}
+{{- end }}
+	return s.table.DeleteWhere(ctx, filters, opts...)
+}
+
+func (s *{{.CamelCaseTableName}}MemoryTable) Columns() []string {
+	return append([]string(nil), {{.NonCamelCaseTableName}}TableColumns...)
+}
+`
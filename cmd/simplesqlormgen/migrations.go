@@ -0,0 +1,505 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/msanath/gondolf/cmd/internal/genfile"
+)
+
+const migrationsTableTemplate = `
+// Code generated by github.com/msanath/gondolf/simplesqlorm. DO NOT EDIT.
+
+package {{.PkgName}}
+
+import (
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+// {{.CamelCaseTableName}}TableMigrations returns the migrations which create and evolve the {{.TableName}} table.
+func {{.CamelCaseTableName}}TableMigrations(dialect simplesql.Dialect) []simplesql.Migration {
+	switch dialect {
+{{- range $dialect := .Dialects }}
+	case simplesql.{{ $dialect.ConstName }}:
+		return []simplesql.Migration{
+{{- range index $.Migrations $dialect.Name }}
+			{
+				Version: {{ .Version }},
+				Up: ` + "`" + `
+					{{ .Up }}
+				` + "`" + `,
+				Down: ` + "`" + `
+					{{ .Down }}
+				` + "`" + `,
+			},
+{{- end }}
+		}
+{{- end }}
+	}
+	return nil
+}
+`
+
+// migrationDialect is a dialect for which migrations are generated.
+type migrationDialect struct {
+	Name      string
+	ConstName string
+}
+
+var migrationDialects = []migrationDialect{
+	{Name: "mysql", ConstName: "DialectMySQL"},
+	{Name: "sqlite3", ConstName: "DialectSQLite"},
+}
+
+// schemaSnapshot is the schema of a table as of the last generation, along with every migration
+// generated so far. It is stored next to the generated code so that later generations can compute
+// the additive migrations needed to reach the new schema.
+type schemaSnapshot struct {
+	Table      string              `json:"table"`
+	Columns    []columnSchema      `json:"columns"`
+	PrimaryKey []string            `json:"primary_key"`
+	Indexes    []indexSchema       `json:"indexes,omitempty"`
+	History    bool                `json:"history,omitempty"`
+	Migrations []migrationSnapshot `json:"migrations"`
+}
+
+type columnSchema struct {
+	Name     string            `json:"name"`
+	Types    map[string]string `json:"types"`
+	Nullable bool              `json:"nullable"`
+	Default  string            `json:"default,omitempty"`
+}
+
+type indexSchema struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+}
+
+// renderedMigration is a migration of a single dialect, indented to fit the migrations template.
+type renderedMigration struct {
+	Version int
+	Up      string
+	Down    string
+}
+
+type migrationSnapshot struct {
+	Version int               `json:"version"`
+	Up      map[string]string `json:"up"`
+	Down    map[string]string `json:"down"`
+}
+
+func (g *generator) migrationsFileName() string {
+	return g.generatedFileName(migrationsTemplate)
+}
+
+func (g *generator) snapshotFileName() string {
+	return fmt.Sprintf("%s_schema_gen.json", g.TableName)
+}
+
+// generateMigrations renders the migrations file for the table. If a snapshot from a previous
+// generation exists, only the changes since then are appended as new migrations. Changes which may
+// lose data fail the generation, see alterTableMigrations.
+func (g *generator) generateMigrations() error {
+	current, err := g.tableSchema()
+	if err != nil {
+		return err
+	}
+
+	snapshotPath := filepath.Join(g.OutputPath, g.snapshotFileName())
+	previous, err := readSchemaSnapshot(snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	if previous == nil {
+		current.Migrations = createTableMigrations(current, g.MigrationVersion)
+	} else {
+		migrations, err := alterTableMigrations(previous, current, snapshotPath)
+		if err != nil {
+			return err
+		}
+		current.Migrations = append(previous.Migrations, migrations...)
+	}
+	if g.Audit && (previous == nil || !previous.History) {
+		version := current.Migrations[len(current.Migrations)-1].Version + 1
+		current.Migrations = append(current.Migrations, historyTableMigrations(current, version)...)
+	}
+	// The history table is kept once created, even if the table is no longer audited.
+	current.History = g.Audit || (previous != nil && previous.History)
+	g.Dialects = migrationDialects
+	g.Migrations = map[string][]renderedMigration{}
+	for _, d := range migrationDialects {
+		for _, m := range current.Migrations {
+			g.Migrations[d.Name] = append(g.Migrations[d.Name], renderedMigration{
+				Version: m.Version,
+				Up:      strings.ReplaceAll(m.Up[d.Name], "\n", "\n\t\t\t\t\t"),
+				Down:    strings.ReplaceAll(m.Down[d.Name], "\n", "\n\t\t\t\t\t"),
+			})
+		}
+	}
+
+	if err := writeSchemaSnapshot(g.files, snapshotPath, current); err != nil {
+		return err
+	}
+	return g.executeTemplate(migrationsTemplate, g.migrationsFileName())
+}
+
+// tableSchema deduces the schema of the table from the db and orm tags of the row struct.
+func (g *generator) tableSchema() (*schemaSnapshot, error) {
+	snapshot := &schemaSnapshot{Table: g.TableName}
+	indexes := map[string]*indexSchema{}
+	indexOrder := []string{}
+	tenantColumn := ""
+
+	for i, field := range g.fields {
+		dbTag := field.Column
+		ormTag := g.ormTags[i]
+
+		column, err := columnSchemaFor(dbTag, field.Var.Type(), field.Tag.Get("sqltype"), ormTag.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Path, err)
+		}
+		if ormTag.SoftDelete {
+			column.Default = "0"
+		}
+		snapshot.Columns = append(snapshot.Columns, column)
+
+		if ormTag.Tenant {
+			tenantColumn = dbTag
+		}
+		if ormTag.PrimaryKey {
+			snapshot.PrimaryKey = append(snapshot.PrimaryKey, dbTag)
+		}
+
+		for _, unique := range []bool{true, false} {
+			prefix, groups := "idx", ormTag.Index
+			if unique {
+				prefix, groups = "uniq", ormTag.Unique
+			}
+			for _, group := range groups {
+				name := fmt.Sprintf("%s_%s_%s", prefix, g.TableName, group)
+				if _, ok := indexes[name]; !ok {
+					indexes[name] = &indexSchema{Name: name, Unique: unique}
+					indexOrder = append(indexOrder, name)
+				}
+				indexes[name].Columns = append(indexes[name].Columns, dbTag)
+			}
+		}
+	}
+
+	if len(snapshot.PrimaryKey) == 0 {
+		return nil, fmt.Errorf("struct '%s' has no field tagged with orm:\"key=primary\"", g.StructName)
+	}
+	// Rows of tenant scoped tables are keyed per tenant, like their unique keys.
+	if tenantColumn != "" && !slices.Contains(snapshot.PrimaryKey, tenantColumn) {
+		snapshot.PrimaryKey = append([]string{tenantColumn}, snapshot.PrimaryKey...)
+	}
+	for _, name := range indexOrder {
+		index := *indexes[name]
+		// Unique keys of tenant scoped tables are unique per tenant.
+		if index.Unique && tenantColumn != "" && !slices.Contains(index.Columns, tenantColumn) {
+			index.Columns = append([]string{tenantColumn}, index.Columns...)
+		}
+		snapshot.Indexes = append(snapshot.Indexes, index)
+	}
+	return snapshot, nil
+}
+
+// columnSchemaFor infers the column types for each dialect from the Go type of the field.
+// sqlType, when set, overrides the inferred type for all dialects. JSON columns hold any Go type.
+func columnSchemaFor(name string, typ types.Type, sqlType string, isJSON bool) (columnSchema, error) {
+	column := columnSchema{Name: name, Types: map[string]string{}}
+
+	if elem, ok := nullableElem(typ); ok {
+		column.Nullable = true
+		typ = elem
+	}
+
+	if sqlType != "" {
+		for _, d := range migrationDialects {
+			column.Types[d.Name] = sqlType
+		}
+		return column, nil
+	}
+
+	var mysqlType, sqliteType string
+	switch {
+	case isJSON:
+		mysqlType, sqliteType = "JSON", "TEXT"
+	case typ.String() == "time.Time":
+		mysqlType, sqliteType = "DATETIME(6)", "DATETIME"
+	case isByteSlice(typ):
+		mysqlType, sqliteType = "BLOB", "BLOB"
+		column.Nullable = true
+	default:
+		basic, ok := typ.Underlying().(*types.Basic)
+		if !ok {
+			return column, fmt.Errorf("cannot infer the SQL type of %s, set it with a sqltype tag", typ.String())
+		}
+		switch {
+		case basic.Info()&types.IsBoolean != 0:
+			mysqlType, sqliteType = "BOOLEAN", "BOOLEAN"
+		case basic.Info()&types.IsInteger != 0:
+			switch basic.Kind() {
+			case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16:
+				mysqlType, sqliteType = "INT", "INTEGER"
+			default:
+				mysqlType, sqliteType = "BIGINT", "INTEGER"
+			}
+		case basic.Info()&types.IsFloat != 0:
+			mysqlType, sqliteType = "DOUBLE", "REAL"
+		case basic.Info()&types.IsString != 0:
+			mysqlType, sqliteType = "VARCHAR(255)", "TEXT"
+		default:
+			return column, fmt.Errorf("cannot infer the SQL type of %s, set it with a sqltype tag", typ.String())
+		}
+	}
+
+	column.Types["mysql"] = mysqlType
+	column.Types["sqlite3"] = sqliteType
+	return column, nil
+}
+
+func isByteSlice(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	basic, ok := slice.Elem().Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Byte
+}
+
+// columnDefinition renders the column as it appears in CREATE TABLE and ALTER TABLE statements.
+func columnDefinition(c columnSchema, dialect string, withZeroDefault bool) string {
+	def := fmt.Sprintf("%s %s", c.Name, c.Types[dialect])
+	if !c.Nullable {
+		def += " NOT NULL"
+	}
+	switch {
+	case c.Default != "":
+		def += " DEFAULT " + c.Default
+	case withZeroDefault && !c.Nullable:
+		// Existing rows need a value for the new column.
+		def += " DEFAULT " + zeroDefault(c, dialect)
+	}
+	return def
+}
+
+// zeroDefault returns the default holding the zero value of the column. JSON columns default to
+// the JSON null, which decodes to the zero value of any Go type.
+func zeroDefault(c columnSchema, dialect string) string {
+	t := strings.ToUpper(c.Types[dialect])
+	literal := "''"
+	switch {
+	case strings.ToUpper(c.Types["mysql"]) == "JSON":
+		literal = "'null'"
+	case strings.HasPrefix(t, "BOOL"):
+		literal = "FALSE"
+	case strings.Contains(t, "DATE"), strings.Contains(t, "TIME"):
+		literal = "'1970-01-01 00:00:00'"
+	case strings.Contains(t, "INT"), strings.Contains(t, "REAL"), strings.Contains(t, "DOUB"),
+		strings.Contains(t, "FLOA"), strings.Contains(t, "DEC"), strings.Contains(t, "NUMERIC"):
+		literal = "0"
+	}
+	// MySQL only accepts expression defaults for TEXT, BLOB and JSON columns.
+	if dialect == "mysql" && (strings.Contains(t, "TEXT") || strings.Contains(t, "BLOB") || t == "JSON") {
+		return "(" + literal + ")"
+	}
+	return literal
+}
+
+func createIndexStatement(table string, index indexSchema) string {
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, index.Name, table, strings.Join(index.Columns, ", "))
+}
+
+func dropIndexStatement(table string, index indexSchema, dialect string) string {
+	if dialect == "mysql" {
+		return fmt.Sprintf("DROP INDEX %s ON %s;", index.Name, table)
+	}
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;", index.Name)
+}
+
+// createTableMigrations returns the migrations creating the table and its indexes.
+// Each migration holds a single statement as MySQL does not run multiple statements per Exec by default.
+func createTableMigrations(schema *schemaSnapshot, firstVersion int) []migrationSnapshot {
+	create := migrationSnapshot{Version: firstVersion, Up: map[string]string{}, Down: map[string]string{}}
+	for _, d := range migrationDialects {
+		definitions := []string{}
+		for _, c := range schema.Columns {
+			definitions = append(definitions, columnDefinition(c, d.Name, false))
+		}
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(schema.PrimaryKey, ", ")))
+
+		create.Up[d.Name] = fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);", schema.Table, strings.Join(definitions, ",\n\t"))
+		create.Down[d.Name] = fmt.Sprintf("DROP TABLE IF EXISTS %s;", schema.Table)
+	}
+
+	migrations := []migrationSnapshot{create}
+	for _, index := range schema.Indexes {
+		migrations = append(migrations, indexMigration(schema.Table, index, firstVersion+len(migrations)))
+	}
+	return migrations
+}
+
+// historyTableMigrations returns the migrations creating the history table of an audited table,
+// see simplesql.WithAudit. The history table is keyed by the primary key of the table.
+func historyTableMigrations(schema *schemaSnapshot, firstVersion int) []migrationSnapshot {
+	historyTable := schema.Table + "_history"
+	columns := map[string]columnSchema{}
+	for _, c := range schema.Columns {
+		columns[c.Name] = c
+	}
+
+	create := migrationSnapshot{Version: firstVersion, Up: map[string]string{}, Down: map[string]string{}}
+	for _, d := range migrationDialects {
+		definitions := []string{"seq INTEGER PRIMARY KEY AUTOINCREMENT"}
+		imageType := "TEXT"
+		if d.Name == "mysql" {
+			definitions = []string{"seq BIGINT NOT NULL AUTO_INCREMENT"}
+			imageType = "JSON"
+		}
+		for _, name := range schema.PrimaryKey {
+			c := columns[name]
+			c.Nullable = false
+			c.Default = ""
+			definitions = append(definitions, columnDefinition(c, d.Name, false))
+		}
+		definitions = append(definitions,
+			columnDefinition(columnSchema{Name: "operation", Types: map[string]string{"mysql": "VARCHAR(16)", "sqlite3": "TEXT"}}, d.Name, false),
+			columnDefinition(columnSchema{Name: "actor", Types: map[string]string{"mysql": "VARCHAR(255)", "sqlite3": "TEXT"}}, d.Name, false),
+			columnDefinition(columnSchema{Name: "changed_at", Types: map[string]string{"mysql": "DATETIME(6)", "sqlite3": "DATETIME"}}, d.Name, false),
+			"before_image "+imageType,
+			"after_image "+imageType,
+		)
+		if d.Name == "mysql" {
+			definitions = append(definitions, "PRIMARY KEY (seq)")
+		}
+
+		create.Up[d.Name] = fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);", historyTable, strings.Join(definitions, ",\n\t"))
+		create.Down[d.Name] = fmt.Sprintf("DROP TABLE IF EXISTS %s;", historyTable)
+	}
+
+	index := indexSchema{Name: fmt.Sprintf("idx_%s_key", historyTable), Columns: schema.PrimaryKey}
+	return []migrationSnapshot{create, indexMigration(historyTable, index, firstVersion+1)}
+}
+
+func indexMigration(table string, index indexSchema, version int) migrationSnapshot {
+	m := migrationSnapshot{Version: version, Up: map[string]string{}, Down: map[string]string{}}
+	for _, d := range migrationDialects {
+		m.Up[d.Name] = createIndexStatement(table, index)
+		m.Down[d.Name] = dropIndexStatement(table, index, d.Name)
+	}
+	return m
+}
+
+// alterTableMigrations returns the migrations adding the columns and indexes present in current but
+// not in previous, and dropping the indexes no longer present. Removed or modified columns, modified
+// indexes and primary keys are not migrated automatically since that may lose data. Generation fails
+// instead, so that a migration is written by hand and added to the snapshot at snapshotPath along
+// with the new definition.
+func alterTableMigrations(previous, current *schemaSnapshot, snapshotPath string) ([]migrationSnapshot, error) {
+	nextVersion := 1
+	if len(previous.Migrations) > 0 {
+		nextVersion = previous.Migrations[len(previous.Migrations)-1].Version + 1
+	}
+	migrations := []migrationSnapshot{}
+	unsupported := func(what string) error {
+		return fmt.Errorf("%s of table '%s' cannot be migrated automatically, write a migration for it by hand and add it to %s along with the new definition",
+			what, current.Table, snapshotPath)
+	}
+
+	previousColumns := map[string]columnSchema{}
+	for _, c := range previous.Columns {
+		previousColumns[c.Name] = c
+	}
+	currentColumns := map[string]bool{}
+	for _, c := range current.Columns {
+		currentColumns[c.Name] = true
+		old, ok := previousColumns[c.Name]
+		if !ok {
+			m := migrationSnapshot{Version: nextVersion, Up: map[string]string{}, Down: map[string]string{}}
+			for _, d := range migrationDialects {
+				m.Up[d.Name] = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", current.Table, columnDefinition(c, d.Name, true))
+				m.Down[d.Name] = fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", current.Table, c.Name)
+			}
+			migrations = append(migrations, m)
+			nextVersion++
+			continue
+		}
+		if !reflect.DeepEqual(old, c) {
+			return nil, unsupported(fmt.Sprintf("changed column '%s'", c.Name))
+		}
+	}
+	for _, c := range previous.Columns {
+		if !currentColumns[c.Name] {
+			return nil, unsupported(fmt.Sprintf("removed column '%s'", c.Name))
+		}
+	}
+	if !slices.Equal(previous.PrimaryKey, current.PrimaryKey) {
+		return nil, unsupported("changed primary key")
+	}
+
+	previousIndexes := map[string]indexSchema{}
+	for _, index := range previous.Indexes {
+		previousIndexes[index.Name] = index
+	}
+	currentIndexes := map[string]bool{}
+	for _, index := range current.Indexes {
+		currentIndexes[index.Name] = true
+		old, ok := previousIndexes[index.Name]
+		if !ok {
+			migrations = append(migrations, indexMigration(current.Table, index, nextVersion))
+			nextVersion++
+			continue
+		}
+		if !reflect.DeepEqual(old, index) {
+			return nil, unsupported(fmt.Sprintf("changed index '%s'", index.Name))
+		}
+	}
+	for _, index := range previous.Indexes {
+		if !currentIndexes[index.Name] {
+			m := indexMigration(current.Table, index, nextVersion)
+			m.Up, m.Down = m.Down, m.Up
+			migrations = append(migrations, m)
+			nextVersion++
+		}
+	}
+
+	return migrations, nil
+}
+
+func readSchemaSnapshot(path string) (*schemaSnapshot, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema snapshot: %w", err)
+	}
+
+	var snapshot schemaSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse schema snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+func writeSchemaSnapshot(files *genfile.Writer, path string, snapshot *schemaSnapshot) error {
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema snapshot: %w", err)
+	}
+	return files.WriteFile(path, append(content, '\n'))
+}
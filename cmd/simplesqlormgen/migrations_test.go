@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlterTableMigrations(t *testing.T) {
+	column := func(name, mysqlType, sqliteType string) columnSchema {
+		return columnSchema{Name: name, Types: map[string]string{"mysql": mysqlType, "sqlite3": sqliteType}}
+	}
+	previous := &schemaSnapshot{
+		Table:      "node",
+		Columns:    []columnSchema{column("id", "VARCHAR(255)", "TEXT"), column("name", "VARCHAR(255)", "TEXT")},
+		PrimaryKey: []string{"id"},
+		Indexes:    []indexSchema{{Name: "idx_node_name", Columns: []string{"name"}}},
+		Migrations: []migrationSnapshot{{Version: 1}, {Version: 2}},
+	}
+	snapshot := func() *schemaSnapshot {
+		s := *previous
+		s.Columns = append([]columnSchema{}, previous.Columns...)
+		s.Indexes = append([]indexSchema{}, previous.Indexes...)
+		return &s
+	}
+
+	t.Run("Added columns and indexes", func(t *testing.T) {
+		current := snapshot()
+		current.Columns = append(current.Columns,
+			column("labels", "JSON", "TEXT"),
+			column("description", "TEXT", "TEXT"),
+			column("count", "BIGINT", "INTEGER"),
+		)
+		current.Indexes = []indexSchema{{Name: "uniq_node_description", Columns: []string{"description"}, Unique: true}}
+
+		migrations, err := alterTableMigrations(previous, current, "node_schema_gen.json")
+		require.NoError(t, err)
+		require.Len(t, migrations, 5)
+		for i, m := range migrations {
+			require.Equal(t, 3+i, m.Version)
+		}
+		// MySQL rejects literal defaults on TEXT, BLOB and JSON columns.
+		require.Equal(t, "ALTER TABLE node ADD COLUMN labels JSON NOT NULL DEFAULT ('null');", migrations[0].Up["mysql"])
+		require.Equal(t, "ALTER TABLE node ADD COLUMN labels TEXT NOT NULL DEFAULT 'null';", migrations[0].Up["sqlite3"])
+		require.Equal(t, "ALTER TABLE node ADD COLUMN description TEXT NOT NULL DEFAULT ('');", migrations[1].Up["mysql"])
+		require.Equal(t, "ALTER TABLE node ADD COLUMN description TEXT NOT NULL DEFAULT '';", migrations[1].Up["sqlite3"])
+		require.Equal(t, "ALTER TABLE node ADD COLUMN count BIGINT NOT NULL DEFAULT 0;", migrations[2].Up["mysql"])
+		require.Equal(t, "CREATE UNIQUE INDEX uniq_node_description ON node (description);", migrations[3].Up["mysql"])
+		require.Equal(t, "DROP INDEX idx_node_name ON node;", migrations[4].Up["mysql"])
+		require.Equal(t, "CREATE INDEX idx_node_name ON node (name);", migrations[4].Down["mysql"])
+	})
+
+	t.Run("Unsupported changes", func(t *testing.T) {
+		for name, change := range map[string]func(s *schemaSnapshot){
+			"changed column 'name'":   func(s *schemaSnapshot) { s.Columns[1].Nullable = true },
+			"removed column 'name'":   func(s *schemaSnapshot) { s.Columns = s.Columns[:1]; s.Indexes = nil },
+			"changed primary key":     func(s *schemaSnapshot) { s.PrimaryKey = []string{"id", "name"} },
+			"changed index 'idx_node": func(s *schemaSnapshot) { s.Indexes[0].Unique = true },
+		} {
+			current := snapshot()
+			change(current)
+			_, err := alterTableMigrations(previous, current, "node_schema_gen.json")
+			require.ErrorContains(t, err, name)
+			require.ErrorContains(t, err, "node_schema_gen.json")
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// ModelVersion is the version of Model. It is incremented when a field of the model is removed
+// or changes meaning, never when one is added, so a template written against a version renders
+// the same with every simplesqlormgen providing it. Templates check it with requireModelVersion.
+const ModelVersion = 1
+
+// Model is the parsed row struct, passed to templates as .Model. The other fields of the template
+// data are snippets of the built-in templates and may change with any release.
+type Model struct {
+	// Version is ModelVersion.
+	Version int
+	// Package is the name of the generated package.
+	Package string
+	// Table is the name of the table, e.g. cluster_node.
+	Table string
+	// CamelCaseTable prefixes the generated types, e.g. ClusterNode.
+	CamelCaseTable string
+	// Row is the name of the row struct.
+	Row string
+	// Imports are the quoted import paths, possibly named, of the types of the columns.
+	Imports []string
+
+	// Columns are the columns of the row, in field order.
+	Columns []Column
+	// PrimaryKey are the columns of the primary key.
+	PrimaryKey []Column
+	// Keys are the primary key and the unique indexes, each read by a GetBy method.
+	Keys []Key
+	// Filters are the fields of the SelectFilters struct, in order, without Limit.
+	Filters []Filter
+	// OpLock, SoftDelete and Tenant are the columns with the option, nil if none.
+	OpLock     *Column
+	SoftDelete *Column
+	Tenant     *Column
+
+	// Audit is set when the table has a history table.
+	Audit bool
+	// Migrations is set when the table migrations are generated.
+	Migrations bool
+	// Cache is set when the caching decorator of the table is generated.
+	Cache bool
+}
+
+// Column is a field of the row struct with a db tag, or of a struct it embeds or nests with a
+// column prefix, see rowField.
+type Column struct {
+	// Field is the name of the field in the generated structs, prefixed with the names of the
+	// prefixed structs holding it, e.g. LimitsCPU.
+	Field string
+	// Path selects the field in the row, e.g. Limits.CPU.
+	Path string
+	// Name is the name of the column.
+	Name string
+	// Type is the type of the field. OptionalType is the type used where a value may be unset,
+	// *T or simplesql.Nullable[T] for nullable columns, and ElemType the type of the values of
+	// the column, T for nullable columns.
+	Type         string
+	OptionalType string
+	ElemType     string
+	// Nullable is set for the *T and simplesql.Nullable[T] fields.
+	Nullable bool
+
+	// The options of the orm tag, see ormTag.
+	Get        bool
+	Update     bool
+	PrimaryKey bool
+	Filters    []string
+	OpLock     bool
+	SoftDelete bool
+	Auto       string
+	Unique     []string
+	Index      []string
+	JSON       bool
+	Tenant     bool
+	Enum       []string
+
+	// EnumType is the enum type of the values of the column, set when Enum is. ElemType and
+	// OptionalType are then of the enum type.
+	EnumType string
+}
+
+// Key is a key matching at most one row.
+type Key struct {
+	// Name is the name of the unique index, empty for the primary key.
+	Name string
+	// Method is the name of the GetBy method reading the key, e.g. GetByNameAndDeletedAt.
+	Method string
+	// Columns are the columns of the key, in the order of the method parameters.
+	Columns []Column
+}
+
+// Filter is a field of the SelectFilters struct.
+type Filter struct {
+	// Field is the name of the field, e.g. StateNotIn.
+	Field string
+	// Column is the name of the filtered column.
+	Column string
+	// Operator is In, NotIn, Gte, Lte or Eq.
+	Operator string
+	// Type is the type of the field, e.g. []string.
+	Type string
+}
+
+// filterTags are the db tag suffixes of the filter operators.
+var filterTags = map[string]string{
+	"In":    "in",
+	"NotIn": "not_in",
+	"Gte":   "gte",
+	"Lte":   "lte",
+	"Eq":    "eq",
+}
+
+// Tag returns the db tag of the filter field, e.g. state:not_in.
+func (f Filter) Tag() string {
+	return f.Column + ":" + filterTags[f.Operator]
+}
+
+// columnFilters returns the select filters of a column. Soft deleted rows are filtered with Eq and
+// Gte whatever the tag.
+func columnFilters(fieldName, column string, tag ormTag, ft fieldTypes) []Filter {
+	filterTypes := map[string]string{
+		"In":    "[]" + ft.elem,
+		"NotIn": "[]" + ft.elem,
+		"Gte":   "*" + ft.elem,
+		"Lte":   "*" + ft.elem,
+		"Eq":    ft.optional,
+	}
+	var filters []Filter
+	add := func(operator string) {
+		filters = append(filters, Filter{Field: fieldName + operator, Column: column, Operator: operator, Type: filterTypes[operator]})
+	}
+	if tag.SoftDelete {
+		add("Eq")
+		add("Gte")
+	}
+	for _, operator := range []string{"In", "NotIn", "Gte", "Lte", "Eq"} {
+		if tag.hasFilter(operator) {
+			add(operator)
+		}
+	}
+	return filters
+}
+
+// newModel returns the model of the row struct.
+func newModel(g *generator, fields []rowField, ormTags []ormTag, qualifier types.Qualifier) Model {
+	m := Model{
+		Version:        ModelVersion,
+		Package:        g.PkgName,
+		Table:          g.TableName,
+		CamelCaseTable: g.CamelCaseTableName,
+		Row:            g.StructName,
+		Audit:          g.Audit,
+		Migrations:     g.GenerateMigrations,
+		Cache:          g.Cache,
+	}
+	columns := map[string]Column{}
+	for i, field := range fields {
+		tag := ormTags[i]
+		ft := columnFieldTypes(field, tag, g.CamelCaseTableName, qualifier)
+		_, nullable := nullableElem(field.Var.Type())
+		c := Column{
+			Field:        field.Name,
+			Path:         field.Path,
+			Name:         field.Column,
+			Type:         ft.typ,
+			OptionalType: ft.optional,
+			ElemType:     ft.elem,
+			Nullable:     nullable,
+			Get:          tag.Get,
+			Update:       tag.Update,
+			PrimaryKey:   tag.PrimaryKey,
+			Filters:      tag.Filters,
+			OpLock:       tag.OpLock,
+			SoftDelete:   tag.SoftDelete,
+			Auto:         tag.Auto,
+			Unique:       tag.Unique,
+			Index:        tag.Index,
+			JSON:         tag.JSON,
+			Tenant:       tag.Tenant,
+			Enum:         tag.Enum,
+		}
+		if len(tag.Enum) > 0 {
+			c.EnumType = ft.elem
+		}
+		m.Columns = append(m.Columns, c)
+		columns[c.Name] = c
+		if c.PrimaryKey {
+			m.PrimaryKey = append(m.PrimaryKey, c)
+		}
+		switch {
+		case c.OpLock:
+			m.OpLock = &c
+		case c.SoftDelete:
+			m.SoftDelete = &c
+		case c.Tenant:
+			m.Tenant = &c
+		}
+		if !c.Tenant {
+			m.Filters = append(m.Filters, columnFilters(c.Field, c.Name, tag, ft)...)
+		}
+	}
+	for _, k := range g.UniqueKeys {
+		key := Key{Name: k.Name, Method: k.Method}
+		for _, column := range strings.Split(k.Columns, ", ") {
+			key.Columns = append(key.Columns, columns[column])
+		}
+		m.Keys = append(m.Keys, key)
+	}
+	// The imports are recorded by the qualifier, so only once the columns are rendered.
+	m.Imports = g.Imports
+	return m
+}
+
+// requireModelVersion fails the rendering of a template written against another version of the
+// model, e.g. {{requireModelVersion 1}}.
+func requireModelVersion(version int) (string, error) {
+	if version != ModelVersion {
+		return "", fmt.Errorf("template requires model version %d, simplesqlormgen provides version %d", version, ModelVersion)
+	}
+	return "", nil
+}
@@ -2,42 +2,104 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/msanath/gondolf/cmd/internal/genfile"
 )
 
 type ORMGenOptions struct {
 	StructName string
 	TableName  string
+	// Scan generates the tables of every struct of the package marked with a table directive.
+	Scan bool
+
+	Migrations       bool
+	MigrationVersion int
+	Audit            bool
+	// Cache generates the Cached<Table> read-through caching decorator of the table.
+	Cache bool
+
+	// Check compares the generated files with those on disk instead of writing them, and fails
+	// with a diff when they are stale.
+	Check bool
+	// Stdout prints the generated files instead of writing them.
+	Stdout bool
+	// TemplateDir holds templates replacing the built-in ones or generating extra files, see
+	// templates.
+	TemplateDir string
 }
 
 func (o ORMGenOptions) Run(ctx context.Context) error {
-	generator, err := newGenerator(o)
+	if !o.Scan && (o.StructName == "" || o.TableName == "") {
+		return errors.New("--struct-name and --table-name are required without --scan")
+	}
+
+	mode, err := genfile.NewMode(o.Check, o.Stdout)
+	if err != nil {
+		return err
+	}
+	files := genfile.NewWriter(mode, os.Stdout)
+
+	templates, err := loadTemplates(o.TemplateDir)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := getCurrentPackage()
 	if err != nil {
 		return err
 	}
-	return generator.Generate()
+	tables := []ORMGenOptions{o}
+	if o.Scan {
+		if tables, err = scanTables(pkg); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range tables {
+		generator, err := newGenerator(pkg, table, files, templates)
+		if err != nil {
+			return err
+		}
+		if err := generator.Generate(); err != nil {
+			return fmt.Errorf("failed to generate table '%s': %w", table.TableName, err)
+		}
+	}
+	return files.Err()
 }
 
 func main() {
 	o := ORMGenOptions{}
 
 	cmd := cobra.Command{
-		Use:   "simplesqlorm-gen",
-		Short: "Generate ORM code for a given struct",
+		Use:          "simplesqlorm-gen",
+		Short:        "Generate ORM code for a given struct",
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return o.Run(context.Background())
 		},
 	}
 
 	cmd.Flags().StringVar(&o.StructName, "struct-name", "", "Name of the struct to generate ORM code for")
-	cmd.MarkFlagRequired("struct-name")
-
 	cmd.Flags().StringVar(&o.TableName, "table-name", "", "Name of the table in the database")
-	cmd.MarkFlagRequired("table-name")
+	cmd.Flags().BoolVar(&o.Scan, "scan", false, "Generate the ORM code of every struct of the package with a //simplesqlorm:table=<name> directive")
 
-	err := cmd.Execute()
-	if err != nil {
-		panic(err)
+	cmd.Flags().BoolVar(&o.Migrations, "migrations", false, "Generate the CREATE TABLE and ALTER TABLE migrations for the table")
+	cmd.Flags().IntVar(&o.MigrationVersion, "migration-version", 1, "Version of the first generated migration of the table")
+
+	cmd.Flags().BoolVar(&o.Audit, "audit", false, "Generate the History accessor and, with --migrations, the history table of the table")
+	cmd.Flags().BoolVar(&o.Cache, "cache", false, "Generate the Cached<Table> decorator caching the rows read by key")
+
+	cmd.Flags().BoolVar(&o.Check, "check", false, "Compare the generated files with those on disk, print a diff and fail when they are stale")
+	cmd.Flags().BoolVar(&o.Stdout, "stdout", false, "Print the generated files instead of writing them")
+
+	cmd.Flags().StringVar(&o.TemplateDir, "template-dir", "", "Directory of <name>.tmpl templates replacing the built-in table, memory, migrations and cached templates or generating <table>_<name>_gen.go files")
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// tableDirective prefixes the comment directive marking a struct as the row of a table in scan
+// mode:
+//
+//	//simplesqlorm:table=<name> [migrations] [migration-version=<n>] [audit] [cache]
+//
+// The options after the table name are those of the command line flags of the same name.
+const tableDirective = "//simplesqlorm:"
+
+// scanTables returns the options of the structs of the package marked with a table directive.
+func scanTables(pkg *packages.Package) ([]ORMGenOptions, error) {
+	var tables []ORMGenOptions
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				// The doc of a single type declaration is attached to the declaration.
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				if doc == nil {
+					continue
+				}
+				for _, comment := range doc.List {
+					if !strings.HasPrefix(comment.Text, tableDirective) {
+						continue
+					}
+					o, err := parseTableDirective(strings.TrimPrefix(comment.Text, tableDirective))
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", pkg.Fset.Position(comment.Pos()), err)
+					}
+					o.StructName = typeSpec.Name.Name
+					tables = append(tables, o)
+				}
+			}
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no struct of package %s has a %stable directive", pkg.Name, tableDirective)
+	}
+	return tables, nil
+}
+
+func parseTableDirective(directive string) (ORMGenOptions, error) {
+	o := ORMGenOptions{MigrationVersion: 1}
+	for _, option := range strings.Fields(directive) {
+		key, value, hasValue := strings.Cut(option, "=")
+		switch {
+		case key == "table" && hasValue && value != "":
+			o.TableName = value
+		case key == "migrations" && !hasValue:
+			o.Migrations = true
+		case key == "migration-version" && hasValue:
+			version, err := strconv.Atoi(value)
+			if err != nil {
+				return o, fmt.Errorf("invalid migration version '%s'", value)
+			}
+			o.MigrationVersion = version
+		case key == "audit" && !hasValue:
+			o.Audit = true
+		case key == "cache" && !hasValue:
+			o.Cache = true
+		default:
+			return o, fmt.Errorf("invalid table directive option '%s'", option)
+		}
+	}
+	if o.TableName == "" {
+		return o, errors.New("table directive without table=<name>")
+	}
+	return o, nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ormTag is the parsed orm tag of a row struct field. The grammar of the tag is:
+//
+//	tag    = [ option { " " option } ] .
+//	option = flag | key "=" value { "," value } .
+//	flag   = "json" | "tenant" | "shard_key" .
+//	key    = "op" | "key" | "filter" | "op_lock" | "soft_delete" | "auto" | "unique" | "index" | "enum" .
+//
+// The values of op are get and update, of key primary, of filter In, NotIn, Gte, Lte and Eq,
+// of op_lock and soft_delete true, and of auto create_time and update_time. The values of unique
+// and index are names of the indexes holding the column, which are created across the fields
+// naming them. The values of enum are the values of a string column, which must be identifiers.
+// Options may not repeat. shard_key is rejected: the generated tables do not route through
+// simplesql.ShardedDatabase.
+//
+// For example `orm:"op=get key=primary filter=In,NotIn unique=name"`.
+type ormTag struct {
+	// Get adds the column to the get keys.
+	Get bool
+	// Update adds the column to the update fields.
+	Update bool
+	// PrimaryKey makes the column part of the primary key.
+	PrimaryKey bool
+	// Filters are the operators of the select filters of the column.
+	Filters []string
+	// OpLock makes the column the version of optimistic locking.
+	OpLock bool
+	// SoftDelete makes the column the deletion marker of soft deletes.
+	SoftDelete bool
+	// Auto is the automatic timestamp of the column, create_time or update_time, stamped by
+	// simplesql.
+	Auto string
+	// Unique and Index are the names of the indexes of the column.
+	Unique []string
+	Index  []string
+	// JSON stores the field as JSON.
+	JSON bool
+	// Tenant makes the column the tenant of tenant scoped tables.
+	Tenant bool
+	// ShardKey makes the column the shard key of sharded tables, which are not generated yet.
+	ShardKey bool
+	// Enum are the values of the column, generated as a Go enum type.
+	Enum []string
+}
+
+// enumValuePattern restricts the enum values, which name the constants of the enum type.
+var enumValuePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// ormTagValues are the values allowed for the keys of the orm tag. Keys mapped to nil take any
+// name.
+var ormTagValues = map[string][]string{
+	"op":          {"get", "update"},
+	"key":         {"primary"},
+	"filter":      {"In", "NotIn", "Gte", "Lte", "Eq"},
+	"op_lock":     {"true"},
+	"soft_delete": {"true"},
+	"auto":        {"create_time", "update_time"},
+	"unique":      nil,
+	"index":       nil,
+	"enum":        nil,
+}
+
+// parseORMTag parses an orm tag, see ormTag.
+func parseORMTag(tag string) (ormTag, error) {
+	t := ormTag{}
+	seen := map[string]bool{}
+	for _, option := range strings.Fields(tag) {
+		key, value, hasValue := strings.Cut(option, "=")
+		if seen[key] {
+			return t, fmt.Errorf("orm option '%s' is repeated", key)
+		}
+		seen[key] = true
+
+		if !hasValue {
+			switch key {
+			case "json":
+				t.JSON = true
+			case "tenant":
+				t.Tenant = true
+			case "shard_key":
+				t.ShardKey = true
+			default:
+				if _, ok := ormTagValues[key]; ok {
+					return t, fmt.Errorf("orm option '%s' requires a value", key)
+				}
+				return t, fmt.Errorf("unknown orm option '%s'", option)
+			}
+			continue
+		}
+
+		allowed, ok := ormTagValues[key]
+		if !ok {
+			return t, fmt.Errorf("unknown orm option '%s'", key)
+		}
+		values := strings.Split(value, ",")
+		for i, v := range values {
+			if v == "" {
+				return t, fmt.Errorf("empty value of orm option '%s'", key)
+			}
+			if allowed != nil && !slices.Contains(allowed, v) {
+				return t, fmt.Errorf("unknown value '%s' of orm option '%s', expected one of %s", v, key, strings.Join(allowed, ", "))
+			}
+			if slices.Contains(values[:i], v) {
+				return t, fmt.Errorf("value '%s' of orm option '%s' is repeated", v, key)
+			}
+		}
+
+		switch key {
+		case "op":
+			t.Get = slices.Contains(values, "get")
+			t.Update = slices.Contains(values, "update")
+		case "key":
+			t.PrimaryKey = true
+		case "filter":
+			t.Filters = values
+		case "op_lock":
+			t.OpLock = true
+		case "soft_delete":
+			t.SoftDelete = true
+		case "auto":
+			if len(values) > 1 {
+				return t, fmt.Errorf("orm option 'auto' takes a single value")
+			}
+			t.Auto = value
+		case "unique":
+			t.Unique = values
+		case "index":
+			t.Index = values
+		case "enum":
+			for i, v := range values {
+				if !enumValuePattern.MatchString(v) {
+					return t, fmt.Errorf("enum value '%s' is not an identifier", v)
+				}
+				for _, other := range values[:i] {
+					if snakeToCamel(other) == snakeToCamel(v) {
+						return t, fmt.Errorf("enum values '%s' and '%s' name the same constant", other, v)
+					}
+				}
+			}
+			t.Enum = values
+		}
+	}
+	return t, t.validate()
+}
+
+// validate rejects the options which cannot be combined.
+func (t ormTag) validate() error {
+	switch {
+	case t.ShardKey:
+		return fmt.Errorf("shard_key is not supported: the generated tables do not route through simplesql.ShardedDatabase")
+	case t.Update && t.PrimaryKey:
+		return fmt.Errorf("primary key columns cannot be updated with op=update")
+	case t.Update && t.OpLock:
+		return fmt.Errorf("op_lock columns are updated by simplesql and cannot be op=update")
+	case t.PrimaryKey && t.SoftDelete:
+		return fmt.Errorf("soft_delete columns cannot be part of the primary key")
+	case t.OpLock && t.SoftDelete:
+		return fmt.Errorf("op_lock and soft_delete cannot be combined")
+	case t.Auto != "" && (t.Update || t.PrimaryKey || t.OpLock || t.SoftDelete || t.Tenant):
+		return fmt.Errorf("auto columns are set by simplesql and cannot be op=update, key, op_lock, soft_delete or tenant")
+	case t.Tenant && (t.Get || t.Update || len(t.Filters) > 0 || t.OpLock || t.SoftDelete):
+		return fmt.Errorf("tenant columns are set from the context and cannot have op, filter, op_lock or soft_delete")
+	case len(t.Enum) > 0 && (t.JSON || t.Auto != "" || t.OpLock || t.SoftDelete || t.Tenant):
+		return fmt.Errorf("enum columns cannot be json, auto, op_lock, soft_delete or tenant")
+	}
+	return nil
+}
+
+// hasFilter returns true if the tag has the filter operator.
+func (t ormTag) hasFilter(operator string) bool {
+	return slices.Contains(t.Filters, operator)
+}
+
+// parseORMTags parses the orm tags of the columns of the row struct. Errors are reported at the
+// position of the field.
+func parseORMTags(fset *token.FileSet, fields []rowField) ([]ormTag, error) {
+	tags := make([]ormTag, len(fields))
+	var opLock, softDelete, tenant string
+	for i, f := range fields {
+		field := f.Var
+		tag, err := parseORMTag(f.Tag.Get("orm"))
+		if err == nil {
+			err = checkUnique(f.Path, tag.OpLock, "op_lock", &opLock)
+		}
+		if err == nil {
+			err = checkUnique(f.Path, tag.SoftDelete, "soft_delete", &softDelete)
+		}
+		if err == nil {
+			err = checkUnique(f.Path, tag.Tenant, "tenant", &tenant)
+		}
+		if err == nil && tag.OpLock && f.Column != "version" {
+			// simplesql increments the version column of op-locked tables.
+			err = fmt.Errorf("op_lock column must be named version")
+		}
+		if basic, ok := field.Type().Underlying().(*types.Basic); err == nil && len(tag.Enum) > 0 && (!ok || basic.Kind() != types.String) {
+			err = fmt.Errorf("enum columns must be strings")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: field %s: %w", fset.Position(field.Pos()), f.Path, err)
+		}
+		tags[i] = tag
+	}
+	return tags, nil
+}
+
+// checkUnique records the field holding an option which a struct may have only once.
+func checkUnique(fieldName string, set bool, option string, holder *string) error {
+	if !set {
+		return nil
+	}
+	if *holder != "" {
+		return fmt.Errorf("orm option %s is already set on field %s", option, *holder)
+	}
+	*holder = fieldName
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseORMTag(t *testing.T) {
+	tag, err := parseORMTag("op=get,update filter=NotIn unique=name,zone json")
+	require.NoError(t, err)
+	require.True(t, tag.Get)
+	require.True(t, tag.Update)
+	require.True(t, tag.JSON)
+	require.Equal(t, []string{"name", "zone"}, tag.Unique)
+	require.True(t, tag.hasFilter("NotIn"))
+	require.False(t, tag.hasFilter("In"))
+
+	for tag, message := range map[string]string{
+		"key:primary":                  "unknown orm option 'key:primary'",
+		"op=create":                    "unknown value 'create' of orm option 'op'",
+		"filter=In,Index":              "unknown value 'Index' of orm option 'filter'",
+		"filter=In filter=Eq":          "orm option 'filter' is repeated",
+		"filter=In,In":                 "value 'In' of orm option 'filter' is repeated",
+		"unique=":                      "empty value of orm option 'unique'",
+		"op_lock":                      "orm option 'op_lock' requires a value",
+		"op=update key=primary":        "primary key columns cannot be updated with op=update",
+		"op=update op_lock=true":       "op_lock columns are updated by simplesql and cannot be op=update",
+		"op=update auto=update_time":   "auto columns are set by simplesql",
+		"auto=delete_time":             "unknown value 'delete_time' of orm option 'auto'",
+		"tenant filter=Eq":             "tenant columns are set from the context",
+		"key=primary soft_delete=true": "soft_delete columns cannot be part of the primary key",
+		"enum=in-progress":             "enum value 'in-progress' is not an identifier",
+		"enum=in_progress,InProgress":  "enum values 'in_progress' and 'InProgress' name the same constant",
+		"enum=Active json":             "enum columns cannot be json",
+		"key=primary shard_key":        "shard_key is not supported",
+	} {
+		_, err := parseORMTag(tag)
+		require.ErrorContains(t, err, message, tag)
+	}
+}
+
+func TestParseORMTags(t *testing.T) {
+	src := `package test
+
+type Row struct {
+	ID      string ` + "`db:\"id\" orm:\"key=primary\"`" + `
+	Version uint64 ` + "`db:\"version\" orm:\"op_lock=true\"`" + `
+	Name    string ` + "`db:\"name\" orm:\"op=update filter=Like\"`" + `
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "row.go", src, 0)
+	require.NoError(t, err)
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+	s := pkg.Scope().Lookup("Row").Type().Underlying().(*types.Struct)
+
+	fields, err := rowFields(fset, s)
+	require.NoError(t, err)
+	_, err = parseORMTags(fset, fields)
+	require.EqualError(t, err, "row.go:6:2: field Name: unknown value 'Like' of orm option 'filter', expected one of In, NotIn, Gte, Lte, Eq")
+
+	src = `package test
+
+type Row struct {
+	ID    string ` + "`db:\"id\" orm:\"key=primary\"`" + `
+	State int    ` + "`db:\"state\" orm:\"enum=Pending,Active\"`" + `
+}
+`
+	file, err = parser.ParseFile(fset, "enum.go", src, 0)
+	require.NoError(t, err)
+	pkg, err = (&types.Config{Importer: importer.Default()}).Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+	s = pkg.Scope().Lookup("Row").Type().Underlying().(*types.Struct)
+
+	fields, err = rowFields(fset, s)
+	require.NoError(t, err)
+	_, err = parseORMTags(fset, fields)
+	require.EqualError(t, err, "enum.go:5:2: field State: enum columns must be strings")
+}
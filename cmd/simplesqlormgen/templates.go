@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// The built-in templates, named after the suffix of the files they generate, e.g.
+// cluster_table_gen.go.
+const (
+	tableTemplate      = "table"
+	memoryTemplate     = "memory"
+	migrationsTemplate = "migrations"
+	cachedTemplate     = "cached"
+)
+
+// builtinTemplates are the texts of the built-in templates.
+var builtinTemplates = map[string]string{
+	tableTemplate:      bodyTemplate,
+	memoryTemplate:     memoryTableTemplate,
+	migrationsTemplate: migrationsTableTemplate,
+	cachedTemplate:     cachedTableTemplate,
+}
+
+// templateFuncs are the functions available to the templates besides the text/template ones.
+var templateFuncs = template.FuncMap{
+	"requireModelVersion": requireModelVersion,
+	"camel":               snakeToCamel,
+	"lowerFirst":          lowerFirst,
+	"param":               paramName,
+	"join":                strings.Join,
+}
+
+// templateNamePattern restricts the names of the templates of a template directory, which name
+// the generated files.
+var templateNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// templates are the templates of the generated files of a table.
+//
+// A template directory holds <name>.tmpl files. A file named after a built-in template, e.g.
+// table.tmpl, replaces it, and any other file is an extra template generating
+// <table>_<name>_gen.go for every table, e.g. tracing.tmpl generates cluster_tracing_gen.go.
+// Templates are rendered with the generator, whose Model field is the versioned description of
+// the row, see Model. An extra template rendering only white space generates no file, so it can
+// skip tables with {{if}}.
+type templates struct {
+	byName map[string]*template.Template
+	// extras are the names of the extra templates, sorted.
+	extras []string
+}
+
+// loadTemplates parses the built-in templates and those of the template directory, if any.
+func loadTemplates(dir string) (templates, error) {
+	t := templates{byName: map[string]*template.Template{}}
+	for name, text := range builtinTemplates {
+		t.byName[name] = template.Must(template.New(name).Funcs(templateFuncs).Parse(text))
+	}
+	if dir == "" {
+		return t, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return t, fmt.Errorf("failed to list templates: %w", err)
+	}
+	if len(paths) == 0 {
+		return t, fmt.Errorf("template directory %s has no .tmpl file", dir)
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		if !templateNamePattern.MatchString(name) {
+			return t, fmt.Errorf("template %s: name must be lower case letters, digits and underscores", path)
+		}
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return t, fmt.Errorf("failed to read template: %w", err)
+		}
+		tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(text))
+		if err != nil {
+			return t, fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		if _, ok := builtinTemplates[name]; !ok {
+			t.extras = append(t.extras, name)
+		}
+		t.byName[name] = tmpl
+	}
+	slices.Sort(t.extras)
+	return t, nil
+}
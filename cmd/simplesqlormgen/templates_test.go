@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/msanath/gondolf/cmd/internal/genfile"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "memory.tmpl"), []byte("package {{.Model.Package}}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tracing.tmpl"), []byte("{{requireModelVersion 1}}package {{.Model.Package}}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "audit.tmpl"), []byte("{{if .Model.Audit}}package {{.Model.Package}}{{end}}\n"), 0644))
+
+	templates, err := loadTemplates(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"audit", "tracing"}, templates.extras)
+	require.Len(t, templates.byName, 6)
+
+	var buf bytes.Buffer
+	require.NoError(t, templates.byName[memoryTemplate].Execute(&buf, generator{Model: Model{Package: "test"}}))
+	require.Equal(t, "package test\n", buf.String())
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := loadTemplates(t.TempDir())
+		require.ErrorContains(t, err, "has no .tmpl file")
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Tracing.tmpl"), nil, 0644))
+		_, err = loadTemplates(dir)
+		require.ErrorContains(t, err, "name must be lower case letters, digits and underscores")
+
+		dir = t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "tracing.tmpl"), []byte("{{.Model"), 0644))
+		_, err = loadTemplates(dir)
+		require.ErrorContains(t, err, "failed to parse template")
+
+		dir = t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "tracing.tmpl"), []byte("{{requireModelVersion 2}}"), 0644))
+		templates, err := loadTemplates(dir)
+		require.NoError(t, err)
+		err = templates.byName["tracing"].Execute(&buf, generator{})
+		require.ErrorContains(t, err, "template requires model version 2, simplesqlormgen provides version 1")
+	})
+}
+
+func TestGenerateTemplates(t *testing.T) {
+	src := `package test
+
+import "time"
+
+type Row struct {
+	TenantID  string     ` + "`db:\"tenant_id\" orm:\"tenant\"`" + `
+	ID        string     ` + "`db:\"id\" orm:\"op=get key=primary filter=In\"`" + `
+	Version   uint64     ` + "`db:\"version\" orm:\"op_lock=true\"`" + `
+	Name      string     ` + "`db:\"name\" orm:\"op=update filter=Eq unique=name\"`" + `
+	DeletedAt int64      ` + "`db:\"deleted_at\" orm:\"soft_delete=true unique=name\"`" + `
+	StartedAt *time.Time ` + "`db:\"started_at\" orm:\"op=update filter=Gte,Lte\"`" + `
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "row.go", src, 0)
+	require.NoError(t, err)
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("test", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keys.tmpl"), []byte(`package {{.Model.Package}}
+
+// {{range .Model.Keys}}{{.Method}}({{range .Columns}}{{param .Field}} {{.ElemType}},{{end}}) {{end}}
+// {{range .Model.Filters}}{{.Field}} {{.Type}} {{.Tag}}, {{end}}
+// {{.Model.OpLock.Name}} {{.Model.SoftDelete.Name}} {{.Model.Tenant.Name}} {{join .Model.Imports ","}}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "audit.tmpl"), []byte(`{{if .Model.Audit}}package {{.Model.Package}}{{end}}`), 0644))
+	templates, err := loadTemplates(dir)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	files := genfile.NewWriter(genfile.Stdout, &out)
+	g, err := newGenerator(&packages.Package{Name: "test", Types: pkg, Fset: fset}, ORMGenOptions{StructName: "Row", TableName: "row"}, files, templates)
+	require.NoError(t, err)
+	require.NoError(t, g.executeTemplate("keys", g.generatedFileName("keys")))
+	require.NoError(t, g.executeTemplate("audit", g.generatedFileName("audit")))
+
+	require.Equal(t, `// row_keys_gen.go
+package test
+
+// GetByID(id string,) GetByNameAndDeletedAt(name string,deletedAt int64,)
+// IDIn []string id:in, NameEq *string name:eq, DeletedAtEq *int64 deleted_at:eq, DeletedAtGte *int64 deleted_at:gte, StartedAtGte *time.Time started_at:gte, StartedAtLte *time.Time started_at:lte,
+// version deleted_at tenant_id "time"
+`, out.String())
+}
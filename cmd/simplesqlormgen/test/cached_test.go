@@ -0,0 +1,185 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCachedTable tests the cached tables generated with the cache directive option.
+func TestCachedTable(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+	migrations := append(
+		ClusterTableMigrations(simplesqlDb.Dialect()),
+		ProjectTableMigrations(simplesqlDb.Dialect())...,
+	)
+	require.NoError(t, simplesqlDb.ApplyMigrations(migrations))
+
+	ctx := context.Background()
+	cache := simplesql.NewLRUCache(100, time.Minute)
+	uncached := NewClusterTable(simplesqlDb)
+	table := NewCachedClusterTable(uncached, cache)
+
+	t.Run("Get", func(t *testing.T) {
+		_, err := table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		_, err = table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+		require.NoError(t, table.Insert(ctx, db, ClusterRow{ID: "cluster0", Version: 1, Name: "name0", ClusterManagerID: "cm0", State: "running"}))
+		for i := 0; i < 2; i++ {
+			row, err := table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+			require.NoError(t, err)
+			require.Equal(t, "running", row.State)
+			row, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster0")})
+			require.NoError(t, err)
+			require.Equal(t, "running", row.State)
+		}
+		require.Equal(t, simplesql.CacheStats{Hits: 2, NegativeHits: 1, Misses: 3, Invalidations: 1}, table.Stats())
+
+		// Writes bypassing the cached table are not seen.
+		require.NoError(t, uncached.Update(ctx, db, ClusterTableUpdateKey{ID: "cluster0", ClusterManagerID: "cm0", Version: 1}, ClusterTableUpdateFields{State: StringPtr("stopped")}))
+		row, err := table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+		require.NoError(t, err)
+		require.Equal(t, "running", row.State)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := table.Update(ctx, db, ClusterTableUpdateKey{ID: "cluster0", ClusterManagerID: "cm0", Version: 2}, ClusterTableUpdateFields{State: StringPtr("deleting")})
+		require.NoError(t, err)
+
+		row, err := table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+		require.NoError(t, err)
+		require.Equal(t, "deleting", row.State)
+		require.Equal(t, uint64(3), row.Version)
+		row, err = table.GetByNameAndDeletedAt(ctx, "name0", 0)
+		require.NoError(t, err)
+		require.Equal(t, "deleting", row.State)
+
+		// A failed Update does not keep the row from being cached.
+		err = table.Update(ctx, db, ClusterTableUpdateKey{ID: "cluster0", ClusterManagerID: "cm0", Version: 5}, ClusterTableUpdateFields{State: StringPtr("stopped")})
+		require.Error(t, err)
+		misses := table.Stats().Misses
+		for i := 0; i < 2; i++ {
+			row, err = table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+			require.NoError(t, err)
+			require.Equal(t, uint64(3), row.Version)
+		}
+		require.Equal(t, misses+1, table.Stats().Misses)
+	})
+
+	t.Run("UpdateWhere", func(t *testing.T) {
+		n, err := table.UpdateWhere(ctx, db, ClusterTableSelectFilters{IDIn: []string{"cluster0"}}, ClusterTableUpdateFields{State: StringPtr("stopped")})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		row, err := table.GetByNameAndDeletedAt(ctx, "name0", 0)
+		require.NoError(t, err)
+		require.Equal(t, "stopped", row.State)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, table.Delete(ctx, db, ClusterTableUpdateKey{ID: "cluster0", ClusterManagerID: "cm0", Version: 4}))
+		_, err := table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		_, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster0")})
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	})
+
+	t.Run("Tenants", func(t *testing.T) {
+		projects := NewCachedProjectTable(NewProjectTable(simplesqlDb), cache)
+		tenant1 := simplesql.WithTenant(ctx, "tenant1")
+		tenant2 := simplesql.WithTenant(ctx, "tenant2")
+		require.NoError(t, projects.Insert(tenant1, db, ProjectRow{ID: "project1", Name: "name1"}))
+
+		row, err := projects.GetByID(tenant1, "project1")
+		require.NoError(t, err)
+		require.Equal(t, "tenant1", row.TenantID)
+		_, err = projects.GetByID(tenant2, "project1")
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	})
+}
+
+// TestCachedTableTransactions tests that the rows read while a transaction writing them is open
+// are not served once it commits.
+func TestCachedTableTransactions(t *testing.T) {
+	ctx := context.Background()
+	// The reads run on other connections than the transaction, and see the rows before it.
+	simplesqlDb, err := simplesql.Open(ctx, simplesql.Config{
+		Dialect: simplesql.DialectSQLite,
+		Path:    filepath.Join(t.TempDir(), "test.db"),
+		WAL:     true,
+	}, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+	require.NoError(t, err)
+	defer simplesqlDb.DB.Close()
+	require.NoError(t, simplesqlDb.ApplyMigrations(ProjectTableMigrations(simplesqlDb.Dialect())))
+
+	tenant := simplesql.WithTenant(ctx, "tenant1")
+	projects := NewCachedProjectTable(NewProjectTable(simplesqlDb), simplesql.NewLRUCache(100, time.Minute))
+	require.NoError(t, projects.Insert(tenant, simplesqlDb.DB, ProjectRow{ID: "project1", Name: "name1"}))
+
+	getConcurrently := func() (ProjectRow, error) {
+		var row ProjectRow
+		var err error
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			row, err = projects.GetByID(tenant, "project1")
+		}()
+		<-done
+		return row, err
+	}
+
+	t.Run("Update", func(t *testing.T) {
+		tx, err := simplesqlDb.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		err = projects.Update(tenant, tx, ProjectTableUpdateKey{ID: "project1"}, ProjectTableUpdateFields{Name: StringPtr("name2")})
+		require.NoError(t, err)
+
+		row, err := getConcurrently()
+		require.NoError(t, err)
+		require.Equal(t, "name1", row.Name)
+		require.NoError(t, tx.Commit())
+
+		row, err = projects.GetByID(tenant, "project1")
+		require.NoError(t, err)
+		require.Equal(t, "name2", row.Name)
+	})
+
+	t.Run("DeleteWhere", func(t *testing.T) {
+		tx, err := simplesqlDb.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		n, err := projects.DeleteWhere(tenant, tx, ProjectTableSelectFilters{IDIn: []string{"project1"}})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		_, err = getConcurrently()
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		_, err = projects.GetByID(tenant, "project1")
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		require.NoError(t, projects.Insert(tenant, simplesqlDb.DB, ProjectRow{ID: "project1", Name: "name1"}))
+		tx, err := simplesqlDb.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		_, err = projects.DeleteWhere(tenant, tx, ProjectTableSelectFilters{IDIn: []string{"project1"}})
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		row, err := projects.GetByID(tenant, "project1")
+		require.NoError(t, err)
+		require.Equal(t, "name1", row.Name)
+	})
+}
@@ -0,0 +1,133 @@
+// Code generated by github.com/msanath/gondolf/simplesqlorm. DO NOT EDIT.
+
+package test
+
+import (
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+// ClusterTableMigrations returns the migrations which create and evolve the cluster table.
+func ClusterTableMigrations(dialect simplesql.Dialect) []simplesql.Migration {
+	switch dialect {
+	case simplesql.DialectMySQL:
+		return []simplesql.Migration{
+			{
+				Version: 100,
+				Up: `
+					CREATE TABLE cluster (
+						id VARCHAR(255) NOT NULL,
+						version BIGINT NOT NULL,
+						created_at BIGINT NOT NULL,
+						last_updated_at BIGINT NOT NULL,
+						deleted_at BIGINT NOT NULL DEFAULT 0,
+						name VARCHAR(255) NOT NULL,
+						cluster_manager_id VARCHAR(255) NOT NULL,
+						state VARCHAR(255) NOT NULL,
+						message TEXT NOT NULL,
+						PRIMARY KEY (id, cluster_manager_id)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS cluster;
+				`,
+			},
+			{
+				Version: 101,
+				Up: `
+					CREATE UNIQUE INDEX uniq_cluster_name ON cluster (deleted_at, name);
+				`,
+				Down: `
+					DROP INDEX uniq_cluster_name ON cluster;
+				`,
+			},
+			{
+				Version: 102,
+				Up: `
+					CREATE TABLE cluster_history (
+						seq BIGINT NOT NULL AUTO_INCREMENT,
+						id VARCHAR(255) NOT NULL,
+						cluster_manager_id VARCHAR(255) NOT NULL,
+						operation VARCHAR(16) NOT NULL,
+						actor VARCHAR(255) NOT NULL,
+						changed_at DATETIME(6) NOT NULL,
+						before_image JSON,
+						after_image JSON,
+						PRIMARY KEY (seq)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS cluster_history;
+				`,
+			},
+			{
+				Version: 103,
+				Up: `
+					CREATE INDEX idx_cluster_history_key ON cluster_history (id, cluster_manager_id);
+				`,
+				Down: `
+					DROP INDEX idx_cluster_history_key ON cluster_history;
+				`,
+			},
+		}
+	case simplesql.DialectSQLite:
+		return []simplesql.Migration{
+			{
+				Version: 100,
+				Up: `
+					CREATE TABLE cluster (
+						id TEXT NOT NULL,
+						version INTEGER NOT NULL,
+						created_at INTEGER NOT NULL,
+						last_updated_at INTEGER NOT NULL,
+						deleted_at INTEGER NOT NULL DEFAULT 0,
+						name TEXT NOT NULL,
+						cluster_manager_id TEXT NOT NULL,
+						state TEXT NOT NULL,
+						message TEXT NOT NULL,
+						PRIMARY KEY (id, cluster_manager_id)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS cluster;
+				`,
+			},
+			{
+				Version: 101,
+				Up: `
+					CREATE UNIQUE INDEX uniq_cluster_name ON cluster (deleted_at, name);
+				`,
+				Down: `
+					DROP INDEX IF EXISTS uniq_cluster_name;
+				`,
+			},
+			{
+				Version: 102,
+				Up: `
+					CREATE TABLE cluster_history (
+						seq INTEGER PRIMARY KEY AUTOINCREMENT,
+						id TEXT NOT NULL,
+						cluster_manager_id TEXT NOT NULL,
+						operation TEXT NOT NULL,
+						actor TEXT NOT NULL,
+						changed_at DATETIME NOT NULL,
+						before_image TEXT,
+						after_image TEXT
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS cluster_history;
+				`,
+			},
+			{
+				Version: 103,
+				Up: `
+					CREATE INDEX idx_cluster_history_key ON cluster_history (id, cluster_manager_id);
+				`,
+				Down: `
+					DROP INDEX IF EXISTS idx_cluster_history_key;
+				`,
+			},
+		}
+	}
+	return nil
+}
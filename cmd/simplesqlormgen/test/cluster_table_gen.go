@@ -11,6 +11,31 @@ import (
 
 const clusterTableName = "cluster"
 
+// Columns of the cluster table.
+const (
+	ClusterTableColumnID               = "id"
+	ClusterTableColumnVersion          = "version"
+	ClusterTableColumnCreatedAt        = "created_at"
+	ClusterTableColumnLastUpdatedAt    = "last_updated_at"
+	ClusterTableColumnDeletedAt        = "deleted_at"
+	ClusterTableColumnName             = "name"
+	ClusterTableColumnClusterManagerID = "cluster_manager_id"
+	ClusterTableColumnState            = "state"
+	ClusterTableColumnMessage          = "message"
+)
+
+var clusterTableColumns = []string{
+	ClusterTableColumnID,
+	ClusterTableColumnVersion,
+	ClusterTableColumnCreatedAt,
+	ClusterTableColumnLastUpdatedAt,
+	ClusterTableColumnDeletedAt,
+	ClusterTableColumnName,
+	ClusterTableColumnClusterManagerID,
+	ClusterTableColumnState,
+	ClusterTableColumnMessage,
+}
+
 type ClusterTableGetKeys struct {
 	ID        *string `db:"id"`
 	DeletedAt int64   `db:"deleted_at"`
@@ -25,10 +50,9 @@ type ClusterTableUpdateKey struct {
 }
 
 type ClusterTableUpdateFields struct {
-	LastUpdatedAt *int64  `db:"last_updated_at"`
-	DeletedAt     *int64  `db:"deleted_at"`
-	State         *string `db:"state"`
-	Message       *string `db:"message"`
+	DeletedAt *int64  `db:"deleted_at"`
+	State     *string `db:"state"`
+	Message   *string `db:"message"`
 }
 
 type ClusterTableSelectFilters struct {
@@ -45,12 +69,43 @@ type ClusterTableSelectFilters struct {
 	Limit              uint32   `db:"limit"`
 }
 
+type clusterTableKeyByIDAndClusterManagerID struct {
+	ID               string `db:"id"`
+	ClusterManagerID string `db:"cluster_manager_id"`
+}
+
+type clusterTableKeyByNameAndDeletedAt struct {
+	Name      string `db:"name"`
+	DeletedAt int64  `db:"deleted_at"`
+}
+
+// ClusterTableAPI is implemented by ClusterTable and by the in-memory ClusterMemoryTable.
+type ClusterTableAPI interface {
+	Insert(ctx context.Context, execer sqlx.ExecerContext, row ClusterRow) error
+	Get(ctx context.Context, keys ClusterTableGetKeys) (ClusterRow, error)
+	GetByIDAndClusterManagerID(ctx context.Context, id string, clusterManagerID string) (ClusterRow, error)
+	GetByNameAndDeletedAt(ctx context.Context, name string, deletedAt int64) (ClusterRow, error)
+	Update(ctx context.Context, execer sqlx.ExecerContext, updateKey ClusterTableUpdateKey, updateFields ClusterTableUpdateFields) error
+	Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey ClusterTableUpdateKey) error
+	List(ctx context.Context, filters ClusterTableSelectFilters) ([]ClusterRow, error)
+	UpdateWhere(
+		ctx context.Context, execer sqlx.ExecerContext, filters ClusterTableSelectFilters, updateFields ClusterTableUpdateFields, opts ...simplesql.WhereOption,
+	) (int64, error)
+	DeleteWhere(ctx context.Context, execer sqlx.ExecerContext, filters ClusterTableSelectFilters, opts ...simplesql.WhereOption) (int64, error)
+	// Columns returns the columns of the table, in the order of the fields of the row.
+	Columns() []string
+}
+
+var _ ClusterTableAPI = (*ClusterTable)(nil)
+
 type ClusterTable struct {
 	simplesql.Database
 	tableName string
 }
 
 func NewClusterTable(db simplesql.Database) *ClusterTable {
+	// simplesql derives the columns it maintains, such as the automatic timestamps, from the row.
+	db.RegisterTable(clusterTableName, ClusterRow{})
 	return &ClusterTable{
 		Database:  db,
 		tableName: clusterTableName,
@@ -61,6 +116,7 @@ func (s *ClusterTable) Insert(ctx context.Context, execer sqlx.ExecerContext, ro
 	return s.Database.Insert(ctx, execer, s.tableName, row)
 }
 
+// Get returns a row matching the set keys. The GetBy methods of the unique keys match a single row.
 func (s *ClusterTable) Get(ctx context.Context, keys ClusterTableGetKeys) (ClusterRow, error) {
 	var row ClusterRow
 	err := s.Database.Get(ctx, s.tableName, keys, &row)
@@ -70,6 +126,26 @@ func (s *ClusterTable) Get(ctx context.Context, keys ClusterTableGetKeys) (Clust
 	return row, nil
 }
 
+// GetByIDAndClusterManagerID returns the row of the unique key (id, cluster_manager_id).
+func (s *ClusterTable) GetByIDAndClusterManagerID(ctx context.Context, id string, clusterManagerID string) (ClusterRow, error) {
+	var row ClusterRow
+	err := s.Database.Get(ctx, s.tableName, clusterTableKeyByIDAndClusterManagerID{ID: id, ClusterManagerID: clusterManagerID}, &row)
+	if err != nil {
+		return ClusterRow{}, err
+	}
+	return row, nil
+}
+
+// GetByNameAndDeletedAt returns the row of the unique key (name, deleted_at).
+func (s *ClusterTable) GetByNameAndDeletedAt(ctx context.Context, name string, deletedAt int64) (ClusterRow, error) {
+	var row ClusterRow
+	err := s.Database.Get(ctx, s.tableName, clusterTableKeyByNameAndDeletedAt{Name: name, DeletedAt: deletedAt}, &row)
+	if err != nil {
+		return ClusterRow{}, err
+	}
+	return row, nil
+}
+
 func (s *ClusterTable) Update(
 	ctx context.Context, execer sqlx.ExecerContext, updateKey ClusterTableUpdateKey, updateFields ClusterTableUpdateFields,
 ) error {
@@ -77,7 +153,7 @@ func (s *ClusterTable) Update(
 }
 
 func (s *ClusterTable) Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey ClusterTableUpdateKey) error {
-	return s.Database.Delete(ctx, s.tableName, updateKey)
+	return s.Database.Delete(ctx, execer, s.tableName, updateKey)
 }
 
 func (s *ClusterTable) List(ctx context.Context, filters ClusterTableSelectFilters) ([]ClusterRow, error) {
@@ -88,3 +164,43 @@ func (s *ClusterTable) List(ctx context.Context, filters ClusterTableSelectFilte
 	}
 	return rows, nil
 }
+
+// UpdateWhere updates the rows matching the filters and returns their number.
+// See simplesql.Database.UpdateWhere.
+func (s *ClusterTable) UpdateWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters ClusterTableSelectFilters, updateFields ClusterTableUpdateFields, opts ...simplesql.WhereOption,
+) (int64, error) {
+	opts = append([]simplesql.WhereOption{simplesql.BumpVersion()}, opts...)
+	return s.Database.UpdateWhere(ctx, execer, s.tableName, filters, updateFields, opts...)
+}
+
+// DeleteWhere deletes the rows matching the filters and returns their number.
+// See simplesql.Database.DeleteWhere.
+func (s *ClusterTable) DeleteWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters ClusterTableSelectFilters, opts ...simplesql.WhereOption,
+) (int64, error) {
+	return s.Database.DeleteWhere(ctx, execer, s.tableName, filters, opts...)
+}
+
+func (s *ClusterTable) Columns() []string {
+	return append([]string(nil), clusterTableColumns...)
+}
+
+func (s *ClusterTable) VerifySchema(ctx context.Context) (simplesql.SchemaDiff, error) {
+	return s.Database.VerifySchema(ctx, s.tableName, ClusterRow{})
+}
+
+type ClusterTableHistoryKey struct {
+	ID               *string `db:"id"`
+	ClusterManagerID *string `db:"cluster_manager_id"`
+}
+
+// History returns the changes of the rows matching the key, oldest first.
+// The table must be audited with simplesql.WithAudit(clusterTableName).
+func (s *ClusterTable) History(ctx context.Context, key ClusterTableHistoryKey) ([]simplesql.HistoryRecord[ClusterRow], error) {
+	entries, err := s.Database.History(ctx, s.tableName, key)
+	if err != nil {
+		return nil, err
+	}
+	return simplesql.DecodeHistory[ClusterRow](entries)
+}
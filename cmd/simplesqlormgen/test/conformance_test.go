@@ -0,0 +1,279 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTableConformance runs the same tests against the SQL and the in-memory tables, so that
+// the in-memory tables behave as the SQL ones.
+func TestTableConformance(t *testing.T) {
+	t.Run("SQLite", func(t *testing.T) {
+		db, err := test.NewTestSQLiteDB()
+		require.NoError(t, err)
+		defer db.Close()
+
+		clock := test.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler), simplesql.WithClock(clock))
+		migrations := append(
+			ClusterTableMigrations(simplesqlDb.Dialect()),
+			ProjectTableMigrations(simplesqlDb.Dialect())...,
+		)
+		require.NoError(t, simplesqlDb.ApplyMigrations(migrations))
+
+		testClusterTableAPI(t, NewClusterTable(simplesqlDb), db, clock)
+		testProjectTableAPI(t, NewProjectTable(simplesqlDb), db)
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		clock := test.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		testClusterTableAPI(t, NewClusterMemoryTable(simplesql.WithMemoryClock(clock)), nil, clock)
+		testProjectTableAPI(t, NewProjectMemoryTable(), nil)
+	})
+}
+
+func testClusterTableAPI(t *testing.T, table ClusterTableAPI, execer sqlx.ExecerContext, clock *test.Clock) {
+	created := clock.Now().UnixMilli()
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		state := "running"
+		if i%2 == 1 {
+			state = "stopped"
+		}
+		err := table.Insert(ctx, execer, ClusterRow{
+			ID:               fmt.Sprintf("cluster%d", i),
+			Version:          1,
+			Name:             fmt.Sprintf("name%d", i),
+			ClusterManagerID: "cm0",
+			State:            state,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("Duplicates", func(t *testing.T) {
+		err := table.Insert(ctx, execer, ClusterRow{ID: "cluster0", Version: 1, Name: "other", ClusterManagerID: "cm0"})
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+		// The name is unique among the clusters which are not deleted.
+		err = table.Insert(ctx, execer, ClusterRow{ID: "cluster9", Version: 1, Name: "name0", ClusterManagerID: "cm0"})
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		row, err := table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster1")})
+		require.NoError(t, err)
+		require.Equal(t, "name1", row.Name)
+
+		row, err = table.Get(ctx, ClusterTableGetKeys{Name: StringPtr("name2")})
+		require.NoError(t, err)
+		require.Equal(t, "cluster2", row.ID)
+
+		_, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster1"), Name: StringPtr("name2")})
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		_, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster1"), DeletedAt: 1})
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+		row, err = table.GetByIDAndClusterManagerID(ctx, "cluster1", "cm0")
+		require.NoError(t, err)
+		require.Equal(t, "name1", row.Name)
+		_, err = table.GetByIDAndClusterManagerID(ctx, "cluster1", "cm1")
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+		row, err = table.GetByNameAndDeletedAt(ctx, "name2", 0)
+		require.NoError(t, err)
+		require.Equal(t, "cluster2", row.ID)
+		_, err = table.GetByNameAndDeletedAt(ctx, "name2", 1)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		key := ClusterTableUpdateKey{ID: "cluster0", ClusterManagerID: "cm0", Version: 1}
+		err := table.Update(ctx, execer, key, ClusterTableUpdateFields{Message: StringPtr("hello")})
+		require.NoError(t, err)
+
+		row, err := table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster0")})
+		require.NoError(t, err)
+		require.Equal(t, "hello", row.Message)
+		require.Equal(t, "running", row.State)
+		require.Equal(t, uint64(2), row.Version)
+
+		// The version of the key is stale.
+		err = table.Update(ctx, execer, key, ClusterTableUpdateFields{Message: StringPtr("stale")})
+		require.ErrorIs(t, err, simplesql.ErrVersionConflict)
+
+		err = table.Update(ctx, execer, ClusterTableUpdateKey{ID: "cluster1", ClusterManagerID: "cm0", Version: 1}, ClusterTableUpdateFields{
+			DeletedAt: Int64Ptr(0),
+			State:     StringPtr("running"),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		rows, err := table.List(ctx, ClusterTableSelectFilters{})
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+
+		rows, err = table.List(ctx, ClusterTableSelectFilters{StateIn: []string{"running"}, IDIn: []string{"cluster0", "cluster1", "cluster3"}})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"cluster0", "cluster1"}, clusterIDs(rows))
+
+		rows, err = table.List(ctx, ClusterTableSelectFilters{StateNotIn: []string{"running"}})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"cluster3"}, clusterIDs(rows))
+
+		rows, err = table.List(ctx, ClusterTableSelectFilters{VersionEq: Uint64Ptr(2)})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"cluster0", "cluster1"}, clusterIDs(rows))
+
+		rows, err = table.List(ctx, ClusterTableSelectFilters{VersionGte: Uint64Ptr(1), VersionLte: Uint64Ptr(1), StateIn: []string{}})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"cluster2", "cluster3", "cluster4"}, clusterIDs(rows))
+
+		rows, err = table.List(ctx, ClusterTableSelectFilters{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+	})
+
+	t.Run("Update where", func(t *testing.T) {
+		n, err := table.UpdateWhere(ctx, execer, ClusterTableSelectFilters{StateIn: []string{"stopped"}}, ClusterTableUpdateFields{
+			State: StringPtr("deleting"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		// The op-lock version is incremented.
+		row, err := table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster3")})
+		require.NoError(t, err)
+		require.Equal(t, "deleting", row.State)
+		require.Equal(t, uint64(2), row.Version)
+
+		_, err = table.UpdateWhere(ctx, execer, ClusterTableSelectFilters{}, ClusterTableUpdateFields{State: StringPtr("deleting")})
+		require.ErrorIs(t, err, simplesql.ErrEmptyFilter)
+
+		n, err = table.UpdateWhere(ctx, execer, ClusterTableSelectFilters{IDIn: []string{"cluster0", "cluster2"}}, ClusterTableUpdateFields{
+			Message: StringPtr("renamed"),
+			State:   StringPtr("renamed"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		// The key of a stale version deletes nothing.
+		err := table.Delete(ctx, execer, ClusterTableUpdateKey{ID: "cluster3", ClusterManagerID: "cm0", Version: 1})
+		require.NoError(t, err)
+		_, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster3")})
+		require.NoError(t, err)
+
+		err = table.Delete(ctx, execer, ClusterTableUpdateKey{ID: "cluster3", ClusterManagerID: "cm0", Version: 2})
+		require.NoError(t, err)
+		_, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster3")})
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+		n, err := table.DeleteWhere(ctx, execer, ClusterTableSelectFilters{StateIn: []string{"renamed"}})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		rows, err := table.List(ctx, ClusterTableSelectFilters{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"cluster1", "cluster4"}, clusterIDs(rows))
+	})
+
+	t.Run("Timestamps", func(t *testing.T) {
+		row, err := table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster4")})
+		require.NoError(t, err)
+		require.Equal(t, created, row.CreatedAt)
+		require.Equal(t, created, row.LastUpdatedAt)
+
+		// Updates setting no field fail, although the timestamps are stamped.
+		err = table.Update(ctx, execer, ClusterTableUpdateKey{ID: "cluster4", ClusterManagerID: "cm0", Version: 1}, ClusterTableUpdateFields{})
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+		_, err = table.UpdateWhere(ctx, execer, ClusterTableSelectFilters{IDIn: []string{"cluster4"}}, ClusterTableUpdateFields{})
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+
+		clock.Advance(time.Minute)
+		err = table.Update(ctx, execer, ClusterTableUpdateKey{ID: "cluster4", ClusterManagerID: "cm0", Version: 1}, ClusterTableUpdateFields{State: StringPtr("stopped")})
+		require.NoError(t, err)
+		row, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster4")})
+		require.NoError(t, err)
+		require.Equal(t, created, row.CreatedAt)
+		require.Equal(t, clock.Now().UnixMilli(), row.LastUpdatedAt)
+		require.Equal(t, uint64(2), row.Version)
+
+		clock.Advance(time.Minute)
+		_, err = table.UpdateWhere(ctx, execer, ClusterTableSelectFilters{IDIn: []string{"cluster4"}}, ClusterTableUpdateFields{State: StringPtr("running")})
+		require.NoError(t, err)
+		row, err = table.Get(ctx, ClusterTableGetKeys{ID: StringPtr("cluster4")})
+		require.NoError(t, err)
+		require.Equal(t, clock.Now().UnixMilli(), row.LastUpdatedAt)
+		require.Equal(t, uint64(3), row.Version)
+	})
+}
+
+func testProjectTableAPI(t *testing.T, table ProjectTableAPI, execer sqlx.ExecerContext) {
+	tenantA := simplesql.WithTenant(context.Background(), "tenantA")
+	tenantB := simplesql.WithTenant(context.Background(), "tenantB")
+
+	require.NoError(t, table.Insert(tenantA, execer, ProjectRow{ID: "project0", Name: "apollo"}))
+	require.NoError(t, table.Insert(tenantB, execer, ProjectRow{ID: "project1", Name: "apollo"}))
+	err := table.Insert(tenantA, execer, ProjectRow{ID: "project2", Name: "apollo"})
+	require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+	err = table.Insert(tenantA, execer, ProjectRow{TenantID: "tenantB", ID: "project2", Name: "gemini"})
+	require.ErrorIs(t, err, simplesql.ErrTenantMismatch)
+	err = table.Insert(context.Background(), execer, ProjectRow{ID: "project2", Name: "gemini"})
+	require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+
+	project, err := table.Get(tenantA, ProjectTableGetKeys{ID: StringPtr("project0")})
+	require.NoError(t, err)
+	require.Equal(t, "tenantA", project.TenantID)
+	_, err = table.Get(tenantB, ProjectTableGetKeys{ID: StringPtr("project0")})
+	require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	// The tenant alone does not select a row.
+	_, err = table.Get(tenantA, ProjectTableGetKeys{})
+	require.ErrorIs(t, err, simplesql.ErrEmptyKey)
+
+	project, err = table.GetByName(tenantB, "apollo")
+	require.NoError(t, err)
+	require.Equal(t, "project1", project.ID)
+	_, err = table.GetByID(tenantB, "project0")
+	require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+	projects, err := table.List(tenantB, ProjectTableSelectFilters{NameEq: StringPtr("apollo")})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, "project1", projects[0].ID)
+
+	err = table.Update(tenantB, execer, ProjectTableUpdateKey{ID: "project0"}, ProjectTableUpdateFields{Name: StringPtr("gemini")})
+	require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	n, err := table.DeleteWhere(tenantB, execer, ProjectTableSelectFilters{IDIn: []string{"project0"}})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), n)
+	_, err = table.Get(tenantA, ProjectTableGetKeys{ID: StringPtr("project0")})
+	require.NoError(t, err)
+
+	// The primary key is per tenant.
+	require.NoError(t, table.Insert(tenantB, execer, ProjectRow{ID: "project0", Name: "gemini"}))
+	err = table.Insert(tenantA, execer, ProjectRow{ID: "project0", Name: "gemini"})
+	require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+	project, err = table.GetByID(tenantB, "project0")
+	require.NoError(t, err)
+	require.Equal(t, "gemini", project.Name)
+}
+
+func clusterIDs(rows []ClusterRow) []string {
+	ids := []string{}
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+func Uint64Ptr(i uint64) *uint64 {
+	return &i
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmbeddedColumns tests the volume table, whose row embeds AuditColumns and nests Quota with
+// the limit_ column prefix.
+func TestEmbeddedColumns(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	clock := test.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler), simplesql.WithClock(clock))
+	require.NoError(t, simplesqlDb.ApplyMigrations(VolumeTableMigrations(simplesqlDb.Dialect())))
+
+	sqlTable := NewVolumeTable(simplesqlDb)
+	diff, err := sqlTable.VerifySchema(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, diff.Err())
+	require.Equal(t, []string{
+		"id", "name", "created_by", "created_at", "updated_at", "limit_cpu", "limit_memory", "limit_tier",
+	}, sqlTable.Columns())
+
+	for name, table := range map[string]VolumeTableAPI{
+		"SQLite": sqlTable,
+		"Memory": NewVolumeMemoryTable(simplesql.WithMemoryClock(clock)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			require.NoError(t, table.Insert(ctx, db, VolumeRow{
+				ID:           "vol1",
+				Name:         "data",
+				AuditColumns: AuditColumns{CreatedBy: "alice"},
+				Limits:       Quota{CPU: 1, Memory: 512, Tier: string(VolumeLimitsTierStandard)},
+			}))
+			require.NoError(t, table.Insert(ctx, db, VolumeRow{
+				ID:           "vol2",
+				Name:         "logs",
+				AuditColumns: AuditColumns{CreatedBy: "bob"},
+				Limits:       Quota{CPU: 2, Memory: 2048, Tier: string(VolumeLimitsTierPremium)},
+			}))
+			err := table.Insert(ctx, db, VolumeRow{ID: "vol3", Name: "tmp", Limits: Quota{Tier: "gold"}})
+			require.EqualError(t, err, "invalid value 'gold' of column limit_tier: check constraint violation")
+
+			cpu := int64(4)
+			require.NoError(t, table.Update(ctx, db, VolumeTableUpdateKey{ID: "vol1"}, VolumeTableUpdateFields{LimitsCPU: &cpu}))
+
+			row, err := table.GetByName(ctx, "data")
+			require.NoError(t, err)
+			require.Equal(t, VolumeRow{
+				ID:   "vol1",
+				Name: "data",
+				AuditColumns: AuditColumns{
+					CreatedBy: "alice",
+					CreatedAt: clock.Now().UnixMilli(),
+					UpdatedAt: clock.Now().UnixMilli(),
+				},
+				Limits: Quota{CPU: 4, Memory: 512, Tier: string(VolumeLimitsTierStandard)},
+			}, row)
+
+			memory := int64(1024)
+			rows, err := table.List(ctx, VolumeTableSelectFilters{LimitsMemoryGte: &memory})
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			require.Equal(t, "vol2", rows[0].ID)
+
+			createdBy := "alice"
+			tier := VolumeLimitsTierPremium
+			n, err := table.UpdateWhere(ctx, db, VolumeTableSelectFilters{CreatedByEq: &createdBy}, VolumeTableUpdateFields{LimitsTier: &tier})
+			require.NoError(t, err)
+			require.Equal(t, int64(1), n)
+			rows, err = table.List(ctx, VolumeTableSelectFilters{LimitsTierEq: &tier})
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnumColumn tests the enum type generated for the state column of the node table.
+func TestEnumColumn(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+	require.NoError(t, simplesqlDb.ApplyMigrations(NodeTableMigrations(simplesqlDb.Dialect())))
+
+	require.Equal(t, []string{"id", "name", "zone", "state"}, NewNodeTable(simplesqlDb).Columns())
+	require.Equal(t, []NodeState{NodeStatePending, NodeStateReady, NodeStateDraining}, NodeStateValues())
+	require.NoError(t, NodeStateReady.Validate())
+
+	for name, table := range map[string]NodeTableAPI{"SQLite": NewNodeTable(simplesqlDb), "Memory": NewNodeMemoryTable()} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			require.NoError(t, table.Insert(ctx, db, NodeRow{ID: "node1", Name: "node1", State: string(NodeStatePending)}))
+			require.NoError(t, table.Insert(ctx, db, NodeRow{ID: "node2", Name: "node2", State: string(NodeStateReady)}))
+
+			err := table.Insert(ctx, db, NodeRow{ID: "node3", Name: "node3", State: "unknown"})
+			require.ErrorIs(t, err, simplesql.ErrCheck)
+			require.EqualError(t, err, "invalid value 'unknown' of column state: check constraint violation")
+			var simplesqlErr *simplesql.Error
+			require.ErrorAs(t, err, &simplesqlErr)
+			require.Equal(t, []string{NodeTableColumnState}, simplesqlErr.Columns)
+
+			state := NodeStateDraining
+			require.NoError(t, table.Update(ctx, db, NodeTableUpdateKey{ID: "node1"}, NodeTableUpdateFields{State: &state}))
+			invalid := NodeState("")
+			err = table.Update(ctx, db, NodeTableUpdateKey{ID: "node1"}, NodeTableUpdateFields{State: &invalid})
+			require.ErrorIs(t, err, simplesql.ErrCheck)
+
+			rows, err := table.List(ctx, NodeTableSelectFilters{StateIn: []NodeState{NodeStateDraining, NodeStateReady}})
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+			rows, err = table.List(ctx, NodeTableSelectFilters{StateEq: &state})
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			require.Equal(t, "node1", rows[0].ID)
+			_, err = table.List(ctx, NodeTableSelectFilters{StateNotIn: []NodeState{"Ready"}})
+			require.ErrorIs(t, err, simplesql.ErrCheck)
+
+			_, err = table.UpdateWhere(ctx, db, NodeTableSelectFilters{StateIn: []NodeState{NodeStateReady}}, NodeTableUpdateFields{State: &invalid})
+			require.ErrorIs(t, err, simplesql.ErrCheck)
+			_, err = table.DeleteWhere(ctx, db, NodeTableSelectFilters{StateEq: &invalid})
+			require.ErrorIs(t, err, simplesql.ErrCheck)
+
+			n, err := table.DeleteWhere(ctx, db, NodeTableSelectFilters{StateIn: []NodeState{NodeStatePending, NodeStateReady, NodeStateDraining}})
+			require.NoError(t, err)
+			require.Equal(t, int64(2), n)
+		})
+	}
+}
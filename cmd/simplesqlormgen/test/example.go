@@ -1,21 +1,54 @@
 package test
 
-//go:generate ../../../bin/simplesqlormgen --struct-name ClusterRow --table-name=cluster
+//go:generate ../../../bin/simplesqlormgen --scan --template-dir templates
+
+//simplesqlorm:table=cluster migrations migration-version=100 audit cache
 type ClusterRow struct {
 	ID            string `db:"id" orm:"op=get key=primary filter=In"`
 	Version       uint64 `db:"version" orm:"op_lock=true filter=Gte,Lte,Eq"`
-	CreatedAt     int64  `db:"created_at"`
-	LastUpdatedAt int64  `db:"last_updated_at" orm:"op=update"`
-	DeletedAt     int64  `db:"deleted_at" orm:"soft_delete=true"`
+	CreatedAt     int64  `db:"created_at" orm:"auto=create_time"`
+	LastUpdatedAt int64  `db:"last_updated_at" orm:"auto=update_time"`
+	DeletedAt     int64  `db:"deleted_at" orm:"soft_delete=true unique=name"`
 
-	Name             string `db:"name" orm:"op=get filter=In"`
+	Name             string `db:"name" orm:"op=get filter=In unique=name"`
 	ClusterManagerID string `db:"cluster_manager_id" orm:"key=primary filter=In"`
 	State            string `db:"state" orm:"op=update filter=In,NotIn"`
-	Message          string `db:"message" orm:"op=update"`
+	Message          string `db:"message" orm:"op=update" sqltype:"TEXT"`
 }
 
-//go:generate ../../../bin/simplesqlormgen --struct-name NodeRow --table-name=node
+//simplesqlorm:table=node migrations migration-version=200
 type NodeRow struct {
+	ID    string  `db:"id" orm:"op=get key=primary filter=In"`
+	Name  string  `db:"name" orm:"op=update filter=In"`
+	Zone  *string `db:"zone" orm:"op=update filter=Eq"`
+	State string  `db:"state" orm:"op=update filter=In,NotIn,Eq enum=pending,ready,draining"`
+}
+
+//simplesqlorm:table=project migrations migration-version=300 cache
+type ProjectRow struct {
+	TenantID string `db:"tenant_id" orm:"tenant"`
+	ID       string `db:"id" orm:"op=get key=primary filter=In"`
+	Name     string `db:"name" orm:"op=update filter=Eq unique=name"`
+}
+
+// AuditColumns are the columns shared by the rows of the tables embedding them.
+type AuditColumns struct {
+	CreatedBy string `db:"created_by" orm:"filter=Eq"`
+	CreatedAt int64  `db:"created_at" orm:"auto=create_time"`
+	UpdatedAt int64  `db:"updated_at" orm:"auto=update_time"`
+}
+
+// Quota is nested into rows with a column prefix, e.g. `db:"limit_"`.
+type Quota struct {
+	CPU    int64  `db:"cpu" orm:"op=update"`
+	Memory int64  `db:"memory" orm:"op=update filter=Gte"`
+	Tier   string `db:"tier" orm:"op=update filter=Eq enum=standard,premium"`
+}
+
+//simplesqlorm:table=volume migrations migration-version=400 cache
+type VolumeRow struct {
 	ID   string `db:"id" orm:"op=get key=primary filter=In"`
-	Name string `db:"name" orm:"op=update filter=In"`
+	Name string `db:"name" orm:"op=update unique=name"`
+	AuditColumns
+	Limits Quota `db:"limit_"`
 }
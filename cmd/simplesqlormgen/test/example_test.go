@@ -37,7 +37,9 @@ var clusterTableMigrations = []simplesql.Migration{
 		Up: `
 			CREATE TABLE node (
 				id VARCHAR(255) NOT NULL PRIMARY KEY,
-				name VARCHAR(255) NOT NULL
+				name VARCHAR(255) NOT NULL,
+				zone VARCHAR(255),
+				state VARCHAR(255) NOT NULL
 			);`,
 		Down: `
 			DROP TABLE IF EXISTS node;
@@ -56,6 +58,16 @@ func TestSimpleSqlDB(t *testing.T) {
 
 	clusterTable := NewClusterTable(simplesqlDb)
 
+	t.Run("Verify schema", func(t *testing.T) {
+		diff, err := clusterTable.VerifySchema(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, diff.Err())
+
+		diff, err = NewNodeTable(simplesqlDb).VerifySchema(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, diff.Err())
+	})
+
 	t.Run("Insert", func(t *testing.T) {
 
 		for i := 0; i < 5; i++ {
@@ -191,10 +203,39 @@ func TestSimpleSqlDB(t *testing.T) {
 		require.NotContains(t, clusterNames, "cluster1")
 	})
 
+	t.Run("Bulk update", func(t *testing.T) {
+		filters := ClusterTableSelectFilters{StateIn: []string{"active"}, DeletedAtEq: Int64Ptr(0)}
+		before, err := clusterTable.List(context.Background(), filters)
+		require.NoError(t, err)
+		require.NotEmpty(t, before)
+
+		n, err := clusterTable.UpdateWhere(context.Background(), db, filters, ClusterTableUpdateFields{
+			Message: StringPtr("maintenance"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(len(before)), n)
+
+		// The version of op-locked rows is bumped.
+		after, err := clusterTable.List(context.Background(), filters)
+		require.NoError(t, err)
+		versions := map[string]uint64{}
+		for _, cluster := range before {
+			versions[cluster.ID] = cluster.Version
+		}
+		for _, cluster := range after {
+			require.Equal(t, "maintenance", cluster.Message)
+			require.Equal(t, versions[cluster.ID]+1, cluster.Version)
+		}
+
+		_, err = clusterTable.DeleteWhere(context.Background(), db, ClusterTableSelectFilters{})
+		require.ErrorIs(t, err, simplesql.ErrEmptyFilter)
+	})
+
 	t.Run("Test update without version", func(t *testing.T) {
 		err := NewNodeTable(simplesqlDb).Insert(context.Background(), db, NodeRow{
-			ID:   "node1",
-			Name: "node1",
+			ID:    "node1",
+			Name:  "node1",
+			State: string(NodeStatePending),
 		})
 		require.NoError(t, err)
 
@@ -209,6 +250,29 @@ func TestSimpleSqlDB(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "node2", node.Name)
 	})
+
+	t.Run("Test nullable column", func(t *testing.T) {
+		nodeTable := NewNodeTable(simplesqlDb)
+		err := nodeTable.Update(context.Background(), db, NodeTableUpdateKey{ID: "node1"}, NodeTableUpdateFields{
+			Zone: simplesql.NewNullable("us-west"),
+		})
+		require.NoError(t, err)
+
+		nodes, err := nodeTable.List(context.Background(), NodeTableSelectFilters{ZoneEq: simplesql.NewNullable("us-west")})
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		require.Equal(t, "us-west", *nodes[0].Zone)
+
+		err = nodeTable.Update(context.Background(), db, NodeTableUpdateKey{ID: "node1"}, NodeTableUpdateFields{
+			Zone: simplesql.Null[string](),
+		})
+		require.NoError(t, err)
+
+		nodes, err = nodeTable.List(context.Background(), NodeTableSelectFilters{ZoneEq: simplesql.Null[string]()})
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		require.Nil(t, nodes[0].Zone)
+	})
 }
 
 func StringPtr(s string) *string {
@@ -218,3 +282,104 @@ func StringPtr(s string) *string {
 func Int64Ptr(i int64) *int64 {
 	return &i
 }
+
+func TestGeneratedMigrations(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithAudit(clusterTableName))
+	migrations := append(
+		ClusterTableMigrations(simplesqlDb.Dialect()),
+		NodeTableMigrations(simplesqlDb.Dialect())...,
+	)
+	err = simplesqlDb.ApplyMigrations(migrations)
+	require.NoError(t, err)
+
+	diff, err := NewClusterTable(simplesqlDb).VerifySchema(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, diff.Err())
+
+	diff, err = NewNodeTable(simplesqlDb).VerifySchema(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, diff.Err())
+
+	clusterTable := NewClusterTable(simplesqlDb)
+	row := ClusterRow{ID: "cluster0", Version: 1, Name: "cluster0", ClusterManagerID: "cluster_manager0"}
+	err = clusterTable.Insert(context.Background(), db, row)
+	require.NoError(t, err)
+
+	// The unique index on (name, deleted_at) rejects a second live cluster with the same name.
+	row.ID = "cluster1"
+	err = clusterTable.Insert(context.Background(), db, row)
+	require.Error(t, err)
+
+	ctx := simplesql.WithActor(context.Background(), "operator")
+	err = clusterTable.Update(ctx, db, ClusterTableUpdateKey{ID: "cluster0", ClusterManagerID: "cluster_manager0", Version: 1}, ClusterTableUpdateFields{
+		State: StringPtr("Ready"),
+	})
+	require.NoError(t, err)
+
+	history, err := clusterTable.History(context.Background(), ClusterTableHistoryKey{ID: StringPtr("cluster0")})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, simplesql.OperationInsert, history[0].Operation)
+	require.Nil(t, history[0].Before)
+	require.Equal(t, row.Name, history[0].After.Name)
+	require.Equal(t, simplesql.OperationUpdate, history[1].Operation)
+	require.Equal(t, "operator", history[1].Actor)
+	require.Equal(t, history[0].After, history[1].Before)
+	require.Equal(t, "Ready", history[1].After.State)
+	require.Equal(t, uint64(2), history[1].After.Version)
+
+	// Rows inserted with another primary key have their own history.
+	history, err = clusterTable.History(context.Background(), ClusterTableHistoryKey{ID: StringPtr("cluster1")})
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	// An empty key would read the history of every row.
+	_, err = clusterTable.History(context.Background(), ClusterTableHistoryKey{})
+	require.ErrorIs(t, err, simplesql.ErrEmptyKey)
+}
+
+func TestGeneratedTenantScope(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+	err = simplesqlDb.ApplyMigrations(ProjectTableMigrations(simplesqlDb.Dialect()))
+	require.NoError(t, err)
+
+	projectTable := NewProjectTable(simplesqlDb)
+	tenantA := simplesql.WithTenant(context.Background(), "tenantA")
+	tenantB := simplesql.WithTenant(context.Background(), "tenantB")
+
+	err = projectTable.Insert(tenantA, db, ProjectRow{ID: "project0", Name: "apollo"})
+	require.NoError(t, err)
+	err = projectTable.Insert(tenantB, db, ProjectRow{ID: "project1", Name: "apollo"})
+	require.NoError(t, err)
+	err = projectTable.Insert(tenantA, db, ProjectRow{ID: "project2", Name: "apollo"})
+	require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+	project, err := projectTable.Get(tenantA, ProjectTableGetKeys{ID: StringPtr("project0")})
+	require.NoError(t, err)
+	require.Equal(t, "tenantA", project.TenantID)
+	_, err = projectTable.Get(tenantB, ProjectTableGetKeys{ID: StringPtr("project0")})
+	require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	_, err = projectTable.Get(context.Background(), ProjectTableGetKeys{ID: StringPtr("project0")})
+	require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+
+	projects, err := projectTable.List(tenantB, ProjectTableSelectFilters{NameEq: StringPtr("apollo")})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, "project1", projects[0].ID)
+
+	err = projectTable.Update(tenantB, db, ProjectTableUpdateKey{ID: "project0"}, ProjectTableUpdateFields{Name: StringPtr("gemini")})
+	require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+	err = projectTable.Delete(tenantB, db, ProjectTableUpdateKey{ID: "project0"})
+	require.NoError(t, err)
+	_, err = projectTable.Get(tenantA, ProjectTableGetKeys{ID: StringPtr("project0")})
+	require.NoError(t, err)
+}
@@ -0,0 +1,82 @@
+// Code generated by github.com/msanath/gondolf/simplesqlorm. DO NOT EDIT.
+
+package test
+
+import (
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+// NodeTableMigrations returns the migrations which create and evolve the node table.
+func NodeTableMigrations(dialect simplesql.Dialect) []simplesql.Migration {
+	switch dialect {
+	case simplesql.DialectMySQL:
+		return []simplesql.Migration{
+			{
+				Version: 200,
+				Up: `
+					CREATE TABLE node (
+						id VARCHAR(255) NOT NULL,
+						name VARCHAR(255) NOT NULL,
+						PRIMARY KEY (id)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS node;
+				`,
+			},
+			{
+				Version: 201,
+				Up: `
+					ALTER TABLE node ADD COLUMN zone VARCHAR(255);
+				`,
+				Down: `
+					ALTER TABLE node DROP COLUMN zone;
+				`,
+			},
+			{
+				Version: 202,
+				Up: `
+					ALTER TABLE node ADD COLUMN state VARCHAR(255) NOT NULL DEFAULT '';
+				`,
+				Down: `
+					ALTER TABLE node DROP COLUMN state;
+				`,
+			},
+		}
+	case simplesql.DialectSQLite:
+		return []simplesql.Migration{
+			{
+				Version: 200,
+				Up: `
+					CREATE TABLE node (
+						id TEXT NOT NULL,
+						name TEXT NOT NULL,
+						PRIMARY KEY (id)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS node;
+				`,
+			},
+			{
+				Version: 201,
+				Up: `
+					ALTER TABLE node ADD COLUMN zone TEXT;
+				`,
+				Down: `
+					ALTER TABLE node DROP COLUMN zone;
+				`,
+			},
+			{
+				Version: 202,
+				Up: `
+					ALTER TABLE node ADD COLUMN state TEXT NOT NULL DEFAULT '';
+				`,
+				Down: `
+					ALTER TABLE node DROP COLUMN state;
+				`,
+			},
+		}
+	}
+	return nil
+}
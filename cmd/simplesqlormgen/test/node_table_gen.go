@@ -4,6 +4,7 @@ package test
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/msanath/gondolf/pkg/simplesql"
@@ -11,6 +12,49 @@ import (
 
 const nodeTableName = "node"
 
+// Columns of the node table.
+const (
+	NodeTableColumnID    = "id"
+	NodeTableColumnName  = "name"
+	NodeTableColumnZone  = "zone"
+	NodeTableColumnState = "state"
+)
+
+var nodeTableColumns = []string{
+	NodeTableColumnID,
+	NodeTableColumnName,
+	NodeTableColumnZone,
+	NodeTableColumnState,
+}
+
+// NodeState is a value of the state column of the node table.
+type NodeState string
+
+const (
+	NodeStatePending  NodeState = "pending"
+	NodeStateReady    NodeState = "ready"
+	NodeStateDraining NodeState = "draining"
+)
+
+// NodeStateValues returns the values of NodeState.
+func NodeStateValues() []NodeState {
+	return []NodeState{NodeStatePending, NodeStateReady, NodeStateDraining}
+}
+
+// Validate returns an error matching simplesql.ErrCheck if the value is not one of NodeStateValues.
+func (v NodeState) Validate() error {
+	switch v {
+	case NodeStatePending, NodeStateReady, NodeStateDraining:
+		return nil
+	}
+	return &simplesql.Error{
+		Kind:    simplesql.KindCheck,
+		Table:   nodeTableName,
+		Columns: []string{NodeTableColumnState},
+		Err:     fmt.Errorf("invalid value '%s' of column state", string(v)),
+	}
+}
+
 type NodeTableGetKeys struct {
 	ID *string `db:"id"`
 }
@@ -20,21 +64,87 @@ type NodeTableUpdateKey struct {
 }
 
 type NodeTableUpdateFields struct {
-	Name *string `db:"name"`
+	Name  *string                    `db:"name"`
+	Zone  simplesql.Nullable[string] `db:"zone"`
+	State *NodeState                 `db:"state"`
 }
 
 type NodeTableSelectFilters struct {
-	IDIn   []string `db:"id:in"`
-	NameIn []string `db:"name:in"`
-	Limit  uint32   `db:"limit"`
+	IDIn       []string                   `db:"id:in"`
+	NameIn     []string                   `db:"name:in"`
+	ZoneEq     simplesql.Nullable[string] `db:"zone:eq"`
+	StateIn    []NodeState                `db:"state:in"`
+	StateNotIn []NodeState                `db:"state:not_in"`
+	StateEq    *NodeState                 `db:"state:eq"`
+	Limit      uint32                     `db:"limit"`
+}
+
+type nodeTableKeyByID struct {
+	ID string `db:"id"`
+}
+
+// validateNodeRow rejects the invalid enum values of the row before they reach SQL.
+func validateNodeRow(row NodeRow) error {
+	if err := NodeState(row.State).Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f NodeTableUpdateFields) validate() error {
+	if f.State != nil {
+		if err := f.State.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f NodeTableSelectFilters) validate() error {
+	for _, v := range f.StateIn {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, v := range f.StateNotIn {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	if f.StateEq != nil {
+		if err := f.StateEq.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NodeTableAPI is implemented by NodeTable and by the in-memory NodeMemoryTable.
+type NodeTableAPI interface {
+	Insert(ctx context.Context, execer sqlx.ExecerContext, row NodeRow) error
+	Get(ctx context.Context, keys NodeTableGetKeys) (NodeRow, error)
+	GetByID(ctx context.Context, id string) (NodeRow, error)
+	Update(ctx context.Context, execer sqlx.ExecerContext, updateKey NodeTableUpdateKey, updateFields NodeTableUpdateFields) error
+	Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey NodeTableUpdateKey) error
+	List(ctx context.Context, filters NodeTableSelectFilters) ([]NodeRow, error)
+	UpdateWhere(
+		ctx context.Context, execer sqlx.ExecerContext, filters NodeTableSelectFilters, updateFields NodeTableUpdateFields, opts ...simplesql.WhereOption,
+	) (int64, error)
+	DeleteWhere(ctx context.Context, execer sqlx.ExecerContext, filters NodeTableSelectFilters, opts ...simplesql.WhereOption) (int64, error)
+	// Columns returns the columns of the table, in the order of the fields of the row.
+	Columns() []string
 }
 
+var _ NodeTableAPI = (*NodeTable)(nil)
+
 type NodeTable struct {
 	simplesql.Database
 	tableName string
 }
 
 func NewNodeTable(db simplesql.Database) *NodeTable {
+	// simplesql derives the columns it maintains, such as the automatic timestamps, from the row.
+	db.RegisterTable(nodeTableName, NodeRow{})
 	return &NodeTable{
 		Database:  db,
 		tableName: nodeTableName,
@@ -42,9 +152,13 @@ func NewNodeTable(db simplesql.Database) *NodeTable {
 }
 
 func (s *NodeTable) Insert(ctx context.Context, execer sqlx.ExecerContext, row NodeRow) error {
+	if err := validateNodeRow(row); err != nil {
+		return err
+	}
 	return s.Database.Insert(ctx, execer, s.tableName, row)
 }
 
+// Get returns a row matching the set keys. The GetBy methods of the unique keys match a single row.
 func (s *NodeTable) Get(ctx context.Context, keys NodeTableGetKeys) (NodeRow, error) {
 	var row NodeRow
 	err := s.Database.Get(ctx, s.tableName, keys, &row)
@@ -54,17 +168,33 @@ func (s *NodeTable) Get(ctx context.Context, keys NodeTableGetKeys) (NodeRow, er
 	return row, nil
 }
 
+// GetByID returns the row of the unique key (id).
+func (s *NodeTable) GetByID(ctx context.Context, id string) (NodeRow, error) {
+	var row NodeRow
+	err := s.Database.Get(ctx, s.tableName, nodeTableKeyByID{ID: id}, &row)
+	if err != nil {
+		return NodeRow{}, err
+	}
+	return row, nil
+}
+
 func (s *NodeTable) Update(
 	ctx context.Context, execer sqlx.ExecerContext, updateKey NodeTableUpdateKey, updateFields NodeTableUpdateFields,
 ) error {
+	if err := updateFields.validate(); err != nil {
+		return err
+	}
 	return s.Database.Update(ctx, execer, s.tableName, updateKey, updateFields)
 }
 
 func (s *NodeTable) Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey NodeTableUpdateKey) error {
-	return s.Database.Delete(ctx, s.tableName, updateKey)
+	return s.Database.Delete(ctx, execer, s.tableName, updateKey)
 }
 
 func (s *NodeTable) List(ctx context.Context, filters NodeTableSelectFilters) ([]NodeRow, error) {
+	if err := filters.validate(); err != nil {
+		return nil, err
+	}
 	var rows []NodeRow
 	err := s.Database.List(ctx, s.tableName, filters, &rows)
 	if err != nil {
@@ -72,3 +202,36 @@ func (s *NodeTable) List(ctx context.Context, filters NodeTableSelectFilters) ([
 	}
 	return rows, nil
 }
+
+// UpdateWhere updates the rows matching the filters and returns their number.
+// See simplesql.Database.UpdateWhere.
+func (s *NodeTable) UpdateWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters NodeTableSelectFilters, updateFields NodeTableUpdateFields, opts ...simplesql.WhereOption,
+) (int64, error) {
+	if err := filters.validate(); err != nil {
+		return 0, err
+	}
+	if err := updateFields.validate(); err != nil {
+		return 0, err
+	}
+	return s.Database.UpdateWhere(ctx, execer, s.tableName, filters, updateFields, opts...)
+}
+
+// DeleteWhere deletes the rows matching the filters and returns their number.
+// See simplesql.Database.DeleteWhere.
+func (s *NodeTable) DeleteWhere(
+	ctx context.Context, execer sqlx.ExecerContext, filters NodeTableSelectFilters, opts ...simplesql.WhereOption,
+) (int64, error) {
+	if err := filters.validate(); err != nil {
+		return 0, err
+	}
+	return s.Database.DeleteWhere(ctx, execer, s.tableName, filters, opts...)
+}
+
+func (s *NodeTable) Columns() []string {
+	return append([]string(nil), nodeTableColumns...)
+}
+
+func (s *NodeTable) VerifySchema(ctx context.Context) (simplesql.SchemaDiff, error) {
+	return s.Database.VerifySchema(ctx, s.tableName, NodeRow{})
+}
@@ -0,0 +1,66 @@
+// Code generated by github.com/msanath/gondolf/simplesqlorm. DO NOT EDIT.
+
+package test
+
+import (
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+// ProjectTableMigrations returns the migrations which create and evolve the project table.
+func ProjectTableMigrations(dialect simplesql.Dialect) []simplesql.Migration {
+	switch dialect {
+	case simplesql.DialectMySQL:
+		return []simplesql.Migration{
+			{
+				Version: 300,
+				Up: `
+					CREATE TABLE project (
+						tenant_id VARCHAR(255) NOT NULL,
+						id VARCHAR(255) NOT NULL,
+						name VARCHAR(255) NOT NULL,
+						PRIMARY KEY (tenant_id, id)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS project;
+				`,
+			},
+			{
+				Version: 301,
+				Up: `
+					CREATE UNIQUE INDEX uniq_project_name ON project (tenant_id, name);
+				`,
+				Down: `
+					DROP INDEX uniq_project_name ON project;
+				`,
+			},
+		}
+	case simplesql.DialectSQLite:
+		return []simplesql.Migration{
+			{
+				Version: 300,
+				Up: `
+					CREATE TABLE project (
+						tenant_id TEXT NOT NULL,
+						id TEXT NOT NULL,
+						name TEXT NOT NULL,
+						PRIMARY KEY (tenant_id, id)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS project;
+				`,
+			},
+			{
+				Version: 301,
+				Up: `
+					CREATE UNIQUE INDEX uniq_project_name ON project (tenant_id, name);
+				`,
+				Down: `
+					DROP INDEX IF EXISTS uniq_project_name;
+				`,
+			},
+		}
+	}
+	return nil
+}
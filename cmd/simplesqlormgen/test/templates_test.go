@@ -0,0 +1,30 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/msanath/gondolf/pkg/ctxslog"
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoggedTable tests the table generated by templates/logged.tmpl.
+func TestLoggedTable(t *testing.T) {
+	var logs bytes.Buffer
+	ctx := ctxslog.NewContext(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)))
+
+	table := NewClusterLoggedTable(NewClusterMemoryTable())
+	require.NoError(t, table.Insert(ctx, nil, ClusterRow{ID: "cluster0", Version: 1, Name: "name0", ClusterManagerID: "cm0"}))
+
+	row, err := table.GetByIDAndClusterManagerID(ctx, "cluster0", "cm0")
+	require.NoError(t, err)
+	require.Equal(t, "name0", row.Name)
+	require.Contains(t, logs.String(), `msg="get cluster" id=cluster0 cluster_manager_id=cm0 error=<nil>`)
+
+	_, err = table.GetByNameAndDeletedAt(ctx, "name1", 0)
+	require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	require.Contains(t, logs.String(), `msg="get cluster" name=name1 deleted_at=0 error=`)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// uniqueKey is a key matching at most one row, read by a GetBy method.
+type uniqueKey struct {
+	// Name is the name of the unique index, empty for the primary key.
+	Name string
+	// Method is the name of the GetBy method, e.g. GetByNameAndDeletedAt.
+	Method string
+	// KeyType is the unexported struct of the key passed to simplesql.
+	KeyType string
+	// Columns are the comma separated columns of the key.
+	Columns string
+	// Params are the parameters of the method.
+	Params string
+	// Fields are the fields of KeyType.
+	Fields string
+	// Args set the fields of KeyType from the parameters.
+	Args string
+	// ArgNames are the names of the parameters.
+	ArgNames string
+}
+
+// uniqueKeys returns the primary key and the unique indexes of the row, in the order of their
+// first field. The columns of a key are in field order with the soft delete column last, which
+// qualifies the others. The tenant column is set from the context and is not a parameter.
+func uniqueKeys(rowFields []rowField, ormTags []ormTag, camelCaseTableName string, qualifier types.Qualifier) []uniqueKey {
+	var names []string
+	groups := map[string][]int{}
+	tenantColumn := ""
+	for i, field := range rowFields {
+		tag := ormTags[i]
+		if tag.Tenant {
+			tenantColumn = field.Column
+			continue
+		}
+		// Index names are not empty, so the primary key cannot collide with a unique index.
+		keyNames := tag.Unique
+		if tag.PrimaryKey {
+			keyNames = append([]string{""}, keyNames...)
+		}
+		for _, name := range keyNames {
+			if _, ok := groups[name]; !ok {
+				names = append(names, name)
+			}
+			groups[name] = append(groups[name], i)
+		}
+	}
+
+	var keys []uniqueKey
+	seen := map[string]bool{}
+	for _, name := range names {
+		fields := groups[name]
+		slices.SortStableFunc(fields, func(a, b int) int {
+			return boolCompare(ormTags[a].SoftDelete, ormTags[b].SoftDelete)
+		})
+
+		var fieldNames, columns, params, structFields, args, argNames []string
+		for _, i := range fields {
+			field := rowFields[i]
+			// Keys match values, never NULL, so the parameters are not nullable.
+			elem := columnFieldTypes(field, ormTags[i], camelCaseTableName, qualifier).elem
+			param := paramName(field.Name)
+			fieldNames = append(fieldNames, field.Name)
+			columns = append(columns, field.Column)
+			params = append(params, fmt.Sprintf("%s %s", param, elem))
+			structFields = append(structFields, fmt.Sprintf("%s %s `db:\"%s\"`", field.Name, elem, field.Column))
+			args = append(args, fmt.Sprintf("%s: %s", field.Name, param))
+			argNames = append(argNames, param)
+		}
+		method := "GetBy" + strings.Join(fieldNames, "And")
+		// A unique index over the columns of the primary key adds no method.
+		if seen[method] {
+			continue
+		}
+		seen[method] = true
+
+		if tenantColumn != "" {
+			structFields = append([]string{fmt.Sprintf("_ struct{} `db:\"%s\" orm:\"tenant\"`", tenantColumn)}, structFields...)
+		}
+		keys = append(keys, uniqueKey{
+			Name:     name,
+			Method:   method,
+			KeyType:  lowerFirst(camelCaseTableName) + "TableKeyBy" + strings.Join(fieldNames, "And"),
+			Columns:  strings.Join(columns, ", "),
+			Params:   strings.Join(params, ", "),
+			Fields:   strings.Join(structFields, "\n"),
+			Args:     strings.Join(args, ", "),
+			ArgNames: strings.Join(argNames, ", "),
+		})
+	}
+	return keys
+}
+
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// paramName returns the parameter name of a field name: ID is id, ClusterManagerID is
+// clusterManagerID and URLPath is urlPath. Keywords get a Value suffix.
+func paramName(fieldName string) string {
+	runes := []rune(fieldName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// The last upper case letter of an initialism followed by a word starts the word.
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	if upper == 0 {
+		upper = 1
+	}
+	name := strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+	if token.IsKeyword(name) {
+		name += "Value"
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
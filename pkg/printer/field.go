@@ -1,7 +1,21 @@
 package printer
 
+import "encoding"
+
+// UnsetValue is displayed for the empty values and the nil pointers.
+const UnsetValue = "<unset>"
+
 type DisplayField struct {
 	DisplayName string
 	ColumnTag   string
 	Value       func() string
 }
+
+// TextValue returns the text of m, or the error marshalling it.
+func TextValue(m encoding.TextMarshaler) string {
+	text, err := m.MarshalText()
+	if err != nil {
+		return err.Error()
+	}
+	return string(text)
+}
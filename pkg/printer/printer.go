@@ -94,7 +94,7 @@ func (p *plainText) PrintDisplayField(field DisplayField) {
 
 func (p *plainText) PrintKeyValue(key, value string) {
 	if value == "" {
-		value = "<unset>"
+		value = UnsetValue
 	}
 	// if the value contains newlines, print it in a new line with an indent
 	if strings.Contains(value, "\n") {
@@ -119,7 +119,7 @@ func (p *plainText) PrintDisplayFieldWithIndent(field DisplayField) {
 
 func (p *plainText) PrintKeyValueWithIndent(key, value string) {
 	if value == "" {
-		value = "<unset>"
+		value = UnsetValue
 	}
 	// if the value contains newlines, print it in a new line with an indent
 	if strings.Contains(value, "\n") {
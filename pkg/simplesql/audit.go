@@ -0,0 +1,154 @@
+package simplesql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HistoryEntry is a row of a history table. Before and After are the JSON images of the row,
+// keyed by column name. Before is nil for inserts and After is nil for deletes.
+type HistoryEntry struct {
+	Seq       int64           `db:"seq"`
+	Operation Operation       `db:"operation"`
+	Actor     string          `db:"actor"`
+	ChangedAt time.Time       `db:"changed_at"`
+	Before    json.RawMessage `db:"before_image" orm:"json"`
+	After     json.RawMessage `db:"after_image" orm:"json"`
+}
+
+// HistoryRecord is a HistoryEntry with the images decoded into row structs.
+type HistoryRecord[T any] struct {
+	Seq       int64
+	Operation Operation
+	Actor     string
+	ChangedAt time.Time
+	Before    *T
+	After     *T
+}
+
+// HistoryTableName returns the name of the history table of an audited table.
+func HistoryTableName(tableName string) string {
+	return tableName + "_history"
+}
+
+// WithAudit records every Insert, Update and Delete of the table in its history table,
+// <table>_history, within the same transaction as the mutation. The history table holds:
+//
+//	seq           auto incremented primary key
+//	<primary key> a column for each primary key column of the table
+//	operation     insert, update or delete
+//	actor         the actor carried by the context, see WithActor
+//	changed_at    the time of the mutation
+//	before_image  JSON image of the row before the mutation, NULL for inserts
+//	after_image   JSON image of the row after the mutation, NULL for deletes
+//
+// Mutations of audited tables must be run with a *sqlx.DB, in which case a transaction is
+// started for them, or with an execer which can also query, such as *sqlx.Tx.
+func WithAudit(tableName string) Option {
+	return func(d *Database) {
+		d.trackChanges().audited[tableName] = true
+	}
+}
+
+// recordHistory writes a history entry for each changed row.
+func (d *Database) recordHistory(
+	ctx context.Context, tx changeExecer, tableName string, operation Operation, primaryKey []string, changes []rowChange, changedAt time.Time,
+) error {
+	columns := append(append([]string{}, primaryKey...), "operation", "actor", "changed_at", "before_image", "after_image")
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := d.DB.Rebind(fmt.Sprintf(`
+		INSERT INTO %s
+		(%s)
+		VALUES (%s)
+	`, HistoryTableName(tableName), strings.Join(columns, ", "), placeholders))
+
+	actor := ActorFromContext(ctx)
+	for _, change := range changes {
+		params := []interface{}{}
+		for _, column := range primaryKey {
+			params = append(params, change.keyImage()[column])
+		}
+
+		before, err := marshalImage(change.before)
+		if err != nil {
+			return err
+		}
+		after, err := marshalImage(change.after)
+		if err != nil {
+			return err
+		}
+		params = append(params, string(operation), actor, changedAt, before, after)
+
+		if _, err := tx.ExecContext(ctx, query, params...); err != nil {
+			return fmt.Errorf("failed to record history of table '%s': %w", tableName, d.handleErr(HistoryTableName(tableName), err))
+		}
+	}
+	return nil
+}
+
+// History returns the history of the rows of an audited table matching the key, oldest first.
+// The key is a struct whose fields select primary key columns, like the keys of Get, and must set
+// at least one of them. The history of tenant scoped tables is scoped to the tenant of the
+// context, on the tenant column of the history table, which the generated history tables hold
+// as part of the primary key.
+func (d *Database) History(ctx context.Context, tableName string, key interface{}) ([]HistoryEntry, error) {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	// A key setting no column would read the history of every row.
+	where, params, err := keyWhere(key)
+	if err != nil {
+		return nil, err
+	}
+	if where == "" {
+		return nil, fmt.Errorf("refusing to read the history of table '%s': %w", tableName, ErrEmptyKey)
+	}
+	tenantWhere, tenantParams, err := d.tenantWhere(ctx, tableName, key)
+	if err != nil {
+		return nil, err
+	}
+	where += tenantWhere
+	params = append(params, tenantParams...)
+	columnNames, _, err := getColumnNamesAndPlaceholders(HistoryEntry{})
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT %s%s FROM %s WHERE 1=1%s ORDER BY seq`, d.selectHint(ctx), columnNames, HistoryTableName(tableName), where)
+
+	var entries []HistoryEntry
+	if err := d.selectRows(ctx, &entries, d.DB.Rebind(query), params...); err != nil {
+		return nil, d.handleErr(HistoryTableName(tableName), err)
+	}
+	return entries, nil
+}
+
+// DecodeHistory decodes the images of history entries into row structs of type T.
+func DecodeHistory[T any](entries []HistoryEntry) ([]HistoryRecord[T], error) {
+	records := make([]HistoryRecord[T], 0, len(entries))
+	for _, entry := range entries {
+		record := HistoryRecord[T]{
+			Seq:       entry.Seq,
+			Operation: entry.Operation,
+			Actor:     entry.Actor,
+			ChangedAt: entry.ChangedAt,
+		}
+		for _, image := range []struct {
+			raw  json.RawMessage
+			dest **T
+		}{{entry.Before, &record.Before}, {entry.After, &record.After}} {
+			if image.raw == nil {
+				continue
+			}
+			row := new(T)
+			if err := DecodeImage(image.raw, row); err != nil {
+				return nil, err
+			}
+			*image.dest = row
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,195 @@
+package simplesql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+var ledgerTableMigrations = []simplesql.Migration{
+	{
+		Version: 1,
+		Up: `
+			CREATE TABLE ledger (
+				account_id VARCHAR(255) NOT NULL,
+				entry_id VARCHAR(255) NOT NULL,
+				version BIGINT NOT NULL,
+				amount BIGINT NOT NULL,
+				memo VARCHAR(255),
+				PRIMARY KEY (account_id, entry_id)
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS ledger;
+			`,
+	},
+	{
+		Version: 2,
+		Up: `
+			CREATE TABLE ledger_history (
+				seq INTEGER PRIMARY KEY AUTOINCREMENT,
+				account_id VARCHAR(255) NOT NULL,
+				entry_id VARCHAR(255) NOT NULL,
+				operation VARCHAR(16) NOT NULL,
+				actor VARCHAR(255) NOT NULL,
+				changed_at DATETIME NOT NULL,
+				before_image TEXT,
+				after_image TEXT
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS ledger_history;
+			`,
+	},
+}
+
+type LedgerRow struct {
+	AccountID string                     `db:"account_id"`
+	EntryID   string                     `db:"entry_id"`
+	Version   uint64                     `db:"version"`
+	Amount    int64                      `db:"amount"`
+	Memo      simplesql.Nullable[string] `db:"memo"`
+}
+
+type LedgerTableKeys struct {
+	AccountID string `db:"account_id"`
+	EntryID   string `db:"entry_id"`
+}
+
+type LedgerTableUpdateKey struct {
+	AccountID string `db:"account_id"`
+	EntryID   string `db:"entry_id"`
+	Version   uint64 `db:"version"`
+}
+
+type LedgerTableUpdateFields struct {
+	Amount *int64                     `db:"amount"`
+	Memo   simplesql.Nullable[string] `db:"memo"`
+}
+
+type LedgerHistoryKey struct {
+	AccountID string `db:"account_id"`
+}
+
+func TestAudit(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler), simplesql.WithAudit("ledger"))
+	err = simplesqlDb.ApplyMigrations(ledgerTableMigrations)
+	require.NoError(t, err)
+
+	ctx := simplesql.WithActor(context.Background(), "alice")
+	keys := LedgerTableKeys{AccountID: "acct0", EntryID: "entry0"}
+
+	t.Run("Insert", func(t *testing.T) {
+		err := simplesqlDb.Insert(ctx, db, "ledger", LedgerRow{AccountID: "acct0", EntryID: "entry0", Version: 1, Amount: 100})
+		require.NoError(t, err)
+
+		err = simplesqlDb.Insert(ctx, db, "ledger", LedgerRow{AccountID: "acct1", EntryID: "entry0", Version: 1, Amount: 5})
+		require.NoError(t, err)
+
+		// A failed insert leaves no history behind.
+		err = simplesqlDb.Insert(ctx, db, "ledger", LedgerRow{AccountID: "acct0", EntryID: "entry0", Version: 1, Amount: 1})
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+		entries, err := simplesqlDb.History(context.Background(), "ledger", keys)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, simplesql.OperationInsert, entries[0].Operation)
+		require.Equal(t, "alice", entries[0].Actor)
+		require.Nil(t, entries[0].Before)
+		require.JSONEq(t, `{"account_id":"acct0","entry_id":"entry0","version":1,"amount":100,"memo":null}`, string(entries[0].After))
+	})
+
+	t.Run("Update in transaction", func(t *testing.T) {
+		amount := int64(150)
+
+		// A rolled back update records no history.
+		tx, err := db.Beginx()
+		require.NoError(t, err)
+		err = simplesqlDb.Update(ctx, tx, "ledger", LedgerTableUpdateKey{AccountID: "acct0", EntryID: "entry0", Version: 1}, LedgerTableUpdateFields{
+			Amount: &amount,
+		})
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		entries, err := simplesqlDb.History(context.Background(), "ledger", keys)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		tx, err = db.Beginx()
+		require.NoError(t, err)
+		err = simplesqlDb.Update(simplesql.WithActor(ctx, "bob"), tx, "ledger", LedgerTableUpdateKey{AccountID: "acct0", EntryID: "entry0", Version: 1}, LedgerTableUpdateFields{
+			Amount: &amount,
+			Memo:   simplesql.NewNullable("refund"),
+		})
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		// A version conflict records no history.
+		err = simplesqlDb.Update(ctx, db, "ledger", LedgerTableUpdateKey{AccountID: "acct0", EntryID: "entry0", Version: 1}, LedgerTableUpdateFields{
+			Amount: &amount,
+		})
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+		entries, err = simplesqlDb.History(context.Background(), "ledger", keys)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, simplesql.OperationUpdate, entries[1].Operation)
+		require.Equal(t, "bob", entries[1].Actor)
+		require.JSONEq(t, string(entries[0].After), string(entries[1].Before))
+		require.JSONEq(t, `{"account_id":"acct0","entry_id":"entry0","version":2,"amount":150,"memo":"refund"}`, string(entries[1].After))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		// A rolled back delete keeps both the row and its history.
+		tx, err := db.Beginx()
+		require.NoError(t, err)
+		require.NoError(t, simplesqlDb.Delete(ctx, tx, "ledger", keys))
+		require.NoError(t, tx.Rollback())
+
+		var row LedgerRow
+		require.NoError(t, simplesqlDb.Get(ctx, "ledger", keys, &row))
+		entries, err := simplesqlDb.History(context.Background(), "ledger", keys)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		err = simplesqlDb.Delete(ctx, db, "ledger", keys)
+		require.NoError(t, err)
+
+		entries, err = simplesqlDb.History(context.Background(), "ledger", keys)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, simplesql.OperationDelete, entries[2].Operation)
+		require.JSONEq(t, string(entries[1].After), string(entries[2].Before))
+		require.Nil(t, entries[2].After)
+	})
+
+	t.Run("Decode history", func(t *testing.T) {
+		entries, err := simplesqlDb.History(context.Background(), "ledger", LedgerHistoryKey{AccountID: "acct0"})
+		require.NoError(t, err)
+
+		records, err := simplesql.DecodeHistory[LedgerRow](entries)
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		require.Equal(t, &LedgerRow{AccountID: "acct0", EntryID: "entry0", Version: 1, Amount: 100, Memo: simplesql.Null[string]()}, records[0].After)
+		require.Equal(t, &LedgerRow{AccountID: "acct0", EntryID: "entry0", Version: 2, Amount: 150, Memo: simplesql.NewNullable("refund")}, records[1].After)
+		require.Equal(t, records[1].After, records[2].Before)
+		require.Nil(t, records[2].After)
+		require.False(t, records[2].ChangedAt.Before(records[0].ChangedAt))
+	})
+
+	t.Run("Empty key", func(t *testing.T) {
+		// Unset pointer fields select no column, as in the generated history keys.
+		_, err := simplesqlDb.History(context.Background(), "ledger", struct {
+			AccountID *string `db:"account_id"`
+		}{})
+		require.ErrorIs(t, err, simplesql.ErrEmptyKey)
+	})
+}
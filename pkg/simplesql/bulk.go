@@ -0,0 +1,186 @@
+package simplesql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrEmptyFilter = errors.New("filters match every row")
+
+type whereOptions struct {
+	allRows     bool
+	bumpVersion bool
+}
+
+// WhereOption configures UpdateWhere and DeleteWhere.
+type WhereOption func(*whereOptions)
+
+// AllRows lets UpdateWhere and DeleteWhere run with filters which set no condition, affecting
+// every row of the table, or of the tenant for tenant scoped tables.
+func AllRows() WhereOption {
+	return func(o *whereOptions) {
+		o.allRows = true
+	}
+}
+
+// BumpVersion increments the version of the updated rows, as Update does for tables with
+// optimistic locking. Rows read before the UpdateWhere then fail to update with ErrVersionConflict.
+func BumpVersion() WhereOption {
+	return func(o *whereOptions) {
+		o.bumpVersion = true
+	}
+}
+
+// UpdateWhere sets the set fields of the fields struct on every row matching the filters, a
+// List filters struct, and returns the number of updated rows. Filters which set no condition
+// fail with ErrEmptyFilter unless AllRows is given. The Limit and OrderBy filters are not
+// supported.
+func (d *Database) UpdateWhere(
+	ctx context.Context, execer sqlx.ExecerContext, tableName string, filters interface{}, fields interface{}, opts ...WhereOption,
+) (int64, error) {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	options := whereOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	updates, params, err := d.updateAssignments(tableName, fields)
+	if err != nil {
+		return 0, err
+	}
+	if options.bumpVersion {
+		updates = append([]string{"version = version + 1"}, updates...)
+	}
+
+	where, whereParams, err := d.bulkWhere(ctx, tableName, filters, options, fields)
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range whereParams {
+		params[name] = value
+	}
+	query := fmt.Sprintf(`UPDATE %s SET %s WHERE 1=1%s`, tableName, strings.Join(updates, ", "), where)
+	return d.execWhere(ctx, execer, tableName, OperationUpdate, query, where, params)
+}
+
+// DeleteWhere deletes every row matching the filters, a List filters struct, and returns the
+// number of deleted rows. Filters are handled as for UpdateWhere.
+func (d *Database) DeleteWhere(
+	ctx context.Context, execer sqlx.ExecerContext, tableName string, filters interface{}, opts ...WhereOption,
+) (int64, error) {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	options := whereOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	where, params, err := d.bulkWhere(ctx, tableName, filters, options)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE 1=1%s`, tableName, where)
+	return d.execWhere(ctx, execer, tableName, OperationDelete, query, where, params)
+}
+
+// bulkWhere returns the conditions of the filters of UpdateWhere and DeleteWhere, including
+// the tenant condition, with their named parameters.
+func (d *Database) bulkWhere(
+	ctx context.Context, tableName string, filters interface{}, options whereOptions, structs ...interface{},
+) (string, map[string]interface{}, error) {
+	where, params, err := bulkFilters(tableName, filters, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tenantColumn, tenantID, scoped, err := d.tenantScope(ctx, tableName, append([]interface{}{filters}, structs...)...)
+	if err != nil {
+		return "", nil, err
+	}
+	if scoped {
+		where += fmt.Sprintf(" AND %s = :tenant_scope", tenantColumn)
+		params["tenant_scope"] = tenantID
+	}
+	return where, params, nil
+}
+
+// bulkFilters returns the conditions of the filters of UpdateWhere and DeleteWhere with their
+// named parameters, refusing the filters they do not support and empty filters.
+func bulkFilters(tableName string, filters interface{}, options whereOptions) (string, map[string]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(filters))
+	if limit := v.FieldByName("Limit"); limit.IsValid() && !limit.IsZero() {
+		return "", nil, fmt.Errorf("limit is not supported by bulk updates and deletes: %w", ErrInternal)
+	}
+	if orderBy := v.FieldByName("OrderBy"); orderBy.IsValid() && !orderBy.IsZero() {
+		return "", nil, fmt.Errorf("order by is not supported by bulk updates and deletes: %w", ErrInternal)
+	}
+
+	where, params, err := filterWhere(v)
+	if err != nil {
+		return "", nil, err
+	}
+	if where == "" && !options.allRows {
+		return "", nil, fmt.Errorf("refusing to change every row of table '%s': %w", tableName, ErrEmptyFilter)
+	}
+	return where, params, nil
+}
+
+// execWhere runs the bulk statement and returns the number of affected rows. The changes of
+// tracked tables are recorded for the rows matching the where conditions.
+func (d *Database) execWhere(
+	ctx context.Context, execer sqlx.ExecerContext, tableName string, operation Operation,
+	query string, where string, params map[string]interface{},
+) (int64, error) {
+	query, args, err := expandNamed(query, params)
+	if err != nil {
+		return 0, err
+	}
+	query = d.DB.Rebind(query)
+
+	var affected int64
+	exec := func(execer sqlx.ExecerContext) error {
+		res, err := execer.ExecContext(ctx, query, args...)
+		if err != nil {
+			return d.handleErr(tableName, err)
+		}
+		affected, err = res.RowsAffected()
+		return d.handleErr(tableName, err)
+	}
+
+	if d.isTracked(tableName) {
+		where, whereArgs, err := expandNamed(where, params)
+		if err != nil {
+			return 0, err
+		}
+		err = d.withChangeTx(ctx, execer, func(tx changeExecer) error {
+			return d.trackMutation(ctx, tx, tableName, operation, where, whereArgs, func() error {
+				return exec(tx)
+			})
+		})
+		return affected, err
+	}
+	err = exec(execer)
+	return affected, err
+}
+
+// expandNamed binds the named parameters of the query to positional ones, expanding the slices
+// of IN clauses. The query is not rebound to the driver.
+func expandNamed(query string, params map[string]interface{}) (string, []interface{}, error) {
+	query, args, err := sqlx.Named(query, params)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to bind named parameters: %s: %w", err.Error(), ErrInternal)
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to expand IN clause: %s: %w", err.Error(), ErrInternal)
+	}
+	return query, args, nil
+}
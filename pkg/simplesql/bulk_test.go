@@ -0,0 +1,136 @@
+package simplesql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+type LedgerTableSelectFilters struct {
+	AccountIDIn []string `db:"account_id:in"`
+	AmountLt    *int64   `db:"amount:lt"`
+}
+
+func TestBulk(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler), simplesql.WithAudit("ledger"))
+	migrations := append([]simplesql.Migration{}, clusterTableMigrations...)
+	for _, migration := range ledgerTableMigrations {
+		migration.Version += 10
+		migrations = append(migrations, migration)
+	}
+	err = simplesqlDb.ApplyMigrations(migrations)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i, state := range []string{"running", "running", "stopped", "failed"} {
+		err := simplesqlDb.Insert(ctx, db, "cluster", ClusterRow{
+			ID:               fmt.Sprintf("cluster%d", i),
+			Version:          1,
+			Name:             fmt.Sprintf("name%d", i),
+			ClusterManagerID: "cm0",
+			State:            state,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("Update", func(t *testing.T) {
+		n, err := simplesqlDb.UpdateWhere(ctx, db, "cluster", ClusterTableSelectFilters{
+			StateNotIn: []string{"running"},
+		}, ClusterTableUpdateFields{State: StringPtr("deleting")}, simplesql.BumpVersion())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		var rows []ClusterRow
+		require.NoError(t, simplesqlDb.List(ctx, "cluster", ClusterTableSelectFilters{StateIn: []string{"deleting"}}, &rows))
+		require.Len(t, rows, 2)
+		for _, row := range rows {
+			require.Equal(t, uint64(2), row.Version)
+		}
+
+		// A row read before the bulk update is stale.
+		err = simplesqlDb.Update(ctx, db, "cluster", ClusterTableUpdateKey{ID: "cluster2", Version: 1, ClusterManagerID: "cm0"}, ClusterTableUpdateFields{
+			Message: StringPtr("stale"),
+		})
+		require.ErrorIs(t, err, simplesql.ErrVersionConflict)
+
+		// Without BumpVersion the version is left alone.
+		n, err = simplesqlDb.UpdateWhere(ctx, db, "cluster", ClusterTableSelectFilters{
+			IDIn: []string{"cluster0"},
+		}, ClusterTableUpdateFields{Message: StringPtr("hello")})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		var row ClusterRow
+		require.NoError(t, simplesqlDb.Get(ctx, "cluster", ClusterTableGetKeys{ID: StringPtr("cluster0")}, &row))
+		require.Equal(t, "hello", row.Message)
+		require.Equal(t, uint64(1), row.Version)
+
+		n, err = simplesqlDb.UpdateWhere(ctx, db, "cluster", ClusterTableSelectFilters{
+			IDIn: []string{"missing"},
+		}, ClusterTableUpdateFields{Message: StringPtr("hello")})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), n)
+	})
+
+	t.Run("Empty filter", func(t *testing.T) {
+		_, err := simplesqlDb.UpdateWhere(ctx, db, "cluster", ClusterTableSelectFilters{}, ClusterTableUpdateFields{Message: StringPtr("all")})
+		require.ErrorIs(t, err, simplesql.ErrEmptyFilter)
+		_, err = simplesqlDb.DeleteWhere(ctx, db, "cluster", ClusterTableSelectFilters{IDIn: []string{}})
+		require.ErrorIs(t, err, simplesql.ErrEmptyFilter)
+		_, err = simplesqlDb.DeleteWhere(ctx, db, "cluster", ClusterTableSelectFilters{IDIn: []string{"cluster0"}, Limit: 1})
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+
+		n, err := simplesqlDb.UpdateWhere(ctx, db, "cluster", ClusterTableSelectFilters{}, ClusterTableUpdateFields{Message: StringPtr("all")}, simplesql.AllRows())
+		require.NoError(t, err)
+		require.Equal(t, int64(4), n)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		n, err := simplesqlDb.DeleteWhere(ctx, db, "cluster", ClusterTableSelectFilters{StateIn: []string{"deleting"}})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		var rows []ClusterRow
+		require.NoError(t, simplesqlDb.List(ctx, "cluster", ClusterTableSelectFilters{}, &rows))
+		require.Len(t, rows, 2)
+	})
+
+	t.Run("Audited", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			err := simplesqlDb.Insert(ctx, db, "ledger", LedgerRow{AccountID: fmt.Sprintf("acct%d", i), EntryID: "entry0", Version: 1, Amount: int64(i)})
+			require.NoError(t, err)
+		}
+
+		n, err := simplesqlDb.UpdateWhere(ctx, db, "ledger", LedgerTableSelectFilters{AmountLt: Int64Ptr(2)}, LedgerTableUpdateFields{
+			Memo: simplesql.NewNullable("small"),
+		}, simplesql.BumpVersion())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+		n, err = simplesqlDb.DeleteWhere(ctx, db, "ledger", LedgerTableSelectFilters{AccountIDIn: []string{"acct0", "acct2"}})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		// Each affected row has its own history.
+		entries, err := simplesqlDb.History(ctx, "ledger", LedgerHistoryKey{AccountID: "acct0"})
+		require.NoError(t, err)
+		records, err := simplesql.DecodeHistory[LedgerRow](entries)
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		require.Equal(t, simplesql.OperationUpdate, records[1].Operation)
+		require.Equal(t, uint64(2), records[1].After.Version)
+		require.Equal(t, simplesql.NewNullable("small"), records[1].After.Memo)
+		require.Equal(t, simplesql.OperationDelete, records[2].Operation)
+
+		entries, err = simplesqlDb.History(ctx, "ledger", LedgerHistoryKey{AccountID: "acct2"})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+	})
+}
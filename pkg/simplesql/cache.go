@@ -0,0 +1,405 @@
+package simplesql
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Cache is the store of a TableCache. Implementations must be safe for concurrent use and may
+// drop entries at any time, e.g. LRUCache.
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{})
+	Delete(key string)
+}
+
+// LRUCache is an in-process Cache holding up to a number of entries, evicting the least recently
+// used ones, for up to a time to live.
+type LRUCache struct {
+	size  int
+	ttl   time.Duration
+	clock Clock
+
+	mu        sync.Mutex
+	entries   *list.List
+	elements  map[string]*list.Element
+	evictions uint64
+}
+
+type lruEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+type lruCacheOptions struct {
+	clock Clock
+}
+
+// LRUCacheOption configures an LRUCache.
+type LRUCacheOption func(*lruCacheOptions)
+
+// WithLRUClock replaces the system clock of the time to live of an LRUCache.
+func WithLRUClock(clock Clock) LRUCacheOption {
+	return func(o *lruCacheOptions) {
+		o.clock = clock
+	}
+}
+
+// NewLRUCache returns a cache of up to size entries, each expiring after ttl. A zero ttl never
+// expires entries.
+func NewLRUCache(size int, ttl time.Duration, opts ...LRUCacheOption) *LRUCache {
+	options := lruCacheOptions{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &LRUCache{
+		size:     size,
+		ttl:      ttl,
+		clock:    options.clock,
+		entries:  list.New(),
+		elements: map[string]*list.Element{},
+	}
+}
+
+func (c *LRUCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if c.ttl > 0 && !c.clock.Now().Before(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+	c.entries.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *LRUCache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.clock.Now().Add(c.ttl)
+	if element, ok := c.elements[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.entries.MoveToFront(element)
+		return
+	}
+	c.elements[key] = c.entries.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.entries.Len() > c.size {
+		c.remove(c.entries.Back())
+		c.evictions++
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.elements[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of entries, expired ones included until they are read or evicted.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// Evictions returns the number of entries evicted to make room for others.
+func (c *LRUCache) Evictions() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictions
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.elements, element.Value.(*lruEntry).key)
+}
+
+// CacheStats are the statistics of the reads of a TableCache.
+type CacheStats struct {
+	// Hits are the reads of a cached row, NegativeHits of a cached ErrRecordNotFound.
+	Hits         uint64
+	NegativeHits uint64
+	// Misses are the reads of the table.
+	Misses uint64
+	// Invalidations are the writes through the cached table.
+	Invalidations uint64
+}
+
+// TableCacheConfig describes the rows of the table of a TableCache.
+type TableCacheConfig[T any] struct {
+	// PrimaryKey returns the values of the primary key columns of the row.
+	PrimaryKey func(row T) []interface{}
+	// Version returns the op_lock version of the row, nil if the table has none.
+	Version func(row T) uint64
+}
+
+// TableCache caches the rows of a table read by key, and ErrRecordNotFound, for the generated
+// cached tables. A row is cached once, under its primary key, and each key it was read by points
+// to it, so that writing the row through the cached table invalidates every key at once:
+//
+//   - Update and Delete invalidate the row of their key. Rows of op-locked tables read with a
+//     version older than that of an Update through the table are not cached again.
+//   - Insert, Update and Delete invalidate the cached ErrRecordNotFound, as the row may now
+//     match keys which matched none.
+//   - UpdateWhere and DeleteWhere invalidate every cached row.
+//
+// Reads racing with a write are not cached. Writes in a transaction are invalidated again once it
+// commits, when it is a CommitNotifier such as Tx, as reads until then return the rows before the
+// write. Writes in other transactions, made by other processes, or through the table without the
+// cache, are only seen once the entries expire from the Cache. Keys are scoped to the tenant of
+// the context.
+type TableCache[T any] struct {
+	tableName string
+	cache     Cache
+	config    TableCacheConfig[T]
+
+	mu sync.Mutex
+	// seq is incremented by every invalidation. Entries record the seq at which their read
+	// started, and are stale if an invalidation covering them came later.
+	seq uint64
+	// generation is the seq of the last invalidation of every row, notFoundGeneration of the
+	// last invalidation of the cached ErrRecordNotFound.
+	generation         uint64
+	notFoundGeneration uint64
+	stats              CacheStats
+}
+
+// cachedLookup is the entry of a key a row was read by.
+type cachedLookup struct {
+	seq        uint64
+	primaryKey string
+	// err is the ErrRecordNotFound returned when the key matched no row.
+	err error
+}
+
+// cachedRow is the entry of a row, or the tombstone of an invalidated row.
+type cachedRow[T any] struct {
+	row       T
+	tombstone bool
+	// invalidated is the seq of the last invalidation of the row. Lookups read before are stale.
+	invalidated uint64
+	// minVersion is the version following the last Update of the row.
+	minVersion uint64
+}
+
+// NewTableCache returns the cache of the rows of the table, stored in cache. A cache can be
+// shared by tables.
+func NewTableCache[T any](tableName string, cache Cache, config TableCacheConfig[T]) *TableCache[T] {
+	return &TableCache[T]{tableName: tableName, cache: cache, config: config}
+}
+
+// Get returns the row of the key, a struct of key fields, from the cache, or reads it with read
+// and caches it, or its ErrRecordNotFound.
+func (c *TableCache[T]) Get(ctx context.Context, key interface{}, read func() (T, error)) (T, error) {
+	tenantID, _ := TenantFromContext(ctx)
+	lookupKey := c.cacheKey(tenantID, "key", keyString(key))
+
+	c.mu.Lock()
+	row, err, ok := c.cached(tenantID, lookupKey)
+	if ok {
+		c.mu.Unlock()
+		return row, err
+	}
+	c.stats.Misses++
+	start := c.seq
+	c.mu.Unlock()
+
+	row, err = read()
+	if err != nil && !errors.Is(err, ErrRecordNotFound) {
+		return row, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if start < c.generation {
+		return row, err
+	}
+	if err != nil {
+		if start >= c.notFoundGeneration {
+			c.cache.Set(lookupKey, cachedLookup{seq: start, err: err})
+		}
+		return row, err
+	}
+	c.store(tenantID, lookupKey, row, start)
+	return row, nil
+}
+
+// cached returns the cached row of the lookup key, or its ErrRecordNotFound, if up to date.
+func (c *TableCache[T]) cached(tenantID, lookupKey string) (T, error, bool) {
+	var zero T
+	value, ok := c.cache.Get(lookupKey)
+	if !ok {
+		return zero, nil, false
+	}
+	lookup := value.(cachedLookup)
+	if lookup.seq < c.generation {
+		return zero, nil, false
+	}
+	if lookup.err != nil {
+		if lookup.seq < c.notFoundGeneration {
+			return zero, nil, false
+		}
+		c.stats.NegativeHits++
+		return zero, lookup.err, true
+	}
+	value, ok = c.cache.Get(c.cacheKey(tenantID, "row", lookup.primaryKey))
+	if !ok {
+		return zero, nil, false
+	}
+	entry := value.(cachedRow[T])
+	if entry.tombstone || lookup.seq < entry.invalidated {
+		return zero, nil, false
+	}
+	c.stats.Hits++
+	return entry.row, nil, true
+}
+
+// store caches the row read by the lookup key from the seq start, unless it was invalidated since.
+func (c *TableCache[T]) store(tenantID, lookupKey string, row T, start uint64) {
+	primaryKey := keyString(c.config.PrimaryKey(row)...)
+	rowKey := c.cacheKey(tenantID, "row", primaryKey)
+	// Without a previous entry, older lookups of the row cannot be told to be up to date.
+	entry := cachedRow[T]{row: row, invalidated: start}
+	if value, ok := c.cache.Get(rowKey); ok {
+		previous := value.(cachedRow[T])
+		if previous.invalidated > start {
+			return
+		}
+		if c.config.Version != nil && c.config.Version(row) < previous.minVersion {
+			return
+		}
+		entry.invalidated = previous.invalidated
+		entry.minVersion = previous.minVersion
+	}
+	c.cache.Set(rowKey, entry)
+	c.cache.Set(lookupKey, cachedLookup{seq: start, primaryKey: primaryKey})
+}
+
+// Invalidate invalidates the row of the primary key and the cached ErrRecordNotFound, after an
+// Update or a Delete with the execer. minVersion is the version of the row following a successful
+// Update of an op-locked table, zero otherwise. When the execer is a CommitNotifier, minVersion
+// only applies once it commits, as the Update is rolled back otherwise.
+func (c *TableCache[T]) Invalidate(ctx context.Context, execer sqlx.ExecerContext, primaryKey []interface{}, minVersion uint64) {
+	tenantID, _ := TenantFromContext(ctx)
+	if tx, ok := execer.(CommitNotifier); ok {
+		c.invalidate(tenantID, primaryKey, 0)
+		tx.OnCommit(func() {
+			c.invalidate(tenantID, primaryKey, minVersion)
+		})
+		return
+	}
+	c.invalidate(tenantID, primaryKey, minVersion)
+}
+
+func (c *TableCache[T]) invalidate(tenantID string, primaryKey []interface{}, minVersion uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	c.stats.Invalidations++
+	c.notFoundGeneration = c.seq
+	rowKey := c.cacheKey(tenantID, "row", keyString(primaryKey...))
+	// The versions of the previous Updates still apply.
+	if value, ok := c.cache.Get(rowKey); ok {
+		minVersion = max(minVersion, value.(cachedRow[T]).minVersion)
+	}
+	c.cache.Set(rowKey, cachedRow[T]{
+		tombstone:   true,
+		invalidated: c.seq,
+		minVersion:  minVersion,
+	})
+}
+
+// InvalidateNotFound invalidates the cached ErrRecordNotFound, after an Insert with the execer.
+func (c *TableCache[T]) InvalidateNotFound(execer sqlx.ExecerContext) {
+	c.afterCommit(execer, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.seq++
+		c.stats.Invalidations++
+		c.notFoundGeneration = c.seq
+	})
+}
+
+// InvalidateAll invalidates every cached row, after an UpdateWhere or a DeleteWhere with the
+// execer.
+func (c *TableCache[T]) InvalidateAll(execer sqlx.ExecerContext) {
+	c.afterCommit(execer, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.seq++
+		c.stats.Invalidations++
+		c.generation = c.seq
+	})
+}
+
+// afterCommit runs the invalidation now, and again once the execer commits if it is a
+// CommitNotifier, since the reads made until then cache the rows before the write.
+func (c *TableCache[T]) afterCommit(execer sqlx.ExecerContext, invalidate func()) {
+	invalidate()
+	if tx, ok := execer.(CommitNotifier); ok {
+		tx.OnCommit(invalidate)
+	}
+}
+
+// Stats returns the statistics of the reads of the cache.
+func (c *TableCache[T]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *TableCache[T]) cacheKey(tenantID, kind, key string) string {
+	return fmt.Sprintf("%s/%q/%s/%s", c.tableName, tenantID, kind, key)
+}
+
+// keyString renders values, or the fields of a key struct, as a cache key. Nil pointers are
+// rendered as nil and blank fields are skipped.
+func keyString(values ...interface{}) string {
+	var b strings.Builder
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Struct || v.Type() == timeType {
+			writeKeyValue(&b, v)
+			continue
+		}
+		fmt.Fprintf(&b, "%s{", v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Name == "_" {
+				continue
+			}
+			fmt.Fprintf(&b, "%s:", field.Name)
+			writeKeyValue(&b, v.Field(i))
+		}
+		b.WriteString("}")
+	}
+	return b.String()
+}
+
+func writeKeyValue(b *strings.Builder, v reflect.Value) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			b.WriteString("nil;")
+			return
+		}
+		v = v.Elem()
+	}
+	fmt.Fprintf(b, "%#v;", v.Interface())
+}
@@ -0,0 +1,250 @@
+package simplesql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+func TestLRUCache(t *testing.T) {
+	clock := test.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := simplesql.NewLRUCache(2, time.Minute, simplesql.WithLRUClock(clock))
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	// b is the least recently used.
+	cache.Set("c", 3)
+	_, ok = cache.Get("b")
+	require.False(t, ok)
+	require.Equal(t, uint64(1), cache.Evictions())
+	require.Equal(t, 2, cache.Len())
+
+	clock.Advance(30 * time.Second)
+	cache.Set("a", 4)
+	clock.Advance(30 * time.Second)
+	_, ok = cache.Get("c")
+	require.False(t, ok)
+	value, ok := cache.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 4, value)
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 0, cache.Len())
+}
+
+type cachedRow struct {
+	ID      string `db:"id"`
+	Name    string `db:"name"`
+	Version uint64 `db:"version"`
+}
+
+type cachedRowKey struct {
+	_    struct{} `db:"tenant_id" orm:"tenant"`
+	ID   *string  `db:"id"`
+	Name *string  `db:"name"`
+}
+
+// pendingTx is a transaction of which the writes are seen once commit is called.
+type pendingTx struct {
+	sqlx.ExecerContext
+	onCommit []func()
+}
+
+func (tx *pendingTx) OnCommit(fn func()) {
+	tx.onCommit = append(tx.onCommit, fn)
+}
+
+func (tx *pendingTx) commit() {
+	for _, fn := range tx.onCommit {
+		fn()
+	}
+}
+
+func TestTableCache(t *testing.T) {
+	ctx := context.Background()
+	rows := map[string]cachedRow{"row1": {ID: "row1", Name: "name1", Version: 1}}
+	reads := 0
+	read := func(key cachedRowKey) func() (cachedRow, error) {
+		return func() (cachedRow, error) {
+			reads++
+			for _, row := range rows {
+				if (key.ID == nil || *key.ID == row.ID) && (key.Name == nil || *key.Name == row.Name) {
+					return row, nil
+				}
+			}
+			return cachedRow{}, fmt.Errorf("table 'row': %w", simplesql.ErrRecordNotFound)
+		}
+	}
+	get := func(cache *simplesql.TableCache[cachedRow], ctx context.Context, key cachedRowKey) (cachedRow, error) {
+		return cache.Get(ctx, key, read(key))
+	}
+
+	cache := simplesql.NewTableCache("row", simplesql.NewLRUCache(100, 0), simplesql.TableCacheConfig[cachedRow]{
+		PrimaryKey: func(row cachedRow) []interface{} { return []interface{}{row.ID} },
+		Version:    func(row cachedRow) uint64 { return row.Version },
+	})
+	byID := cachedRowKey{ID: StringPtr("row1")}
+	byName := cachedRowKey{Name: StringPtr("name1")}
+
+	t.Run("Hits", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			row, err := get(cache, ctx, byID)
+			require.NoError(t, err)
+			require.Equal(t, "name1", row.Name)
+			_, err = get(cache, ctx, byName)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, reads)
+		require.Equal(t, simplesql.CacheStats{Hits: 4, Misses: 2}, cache.Stats())
+	})
+
+	t.Run("Tenants", func(t *testing.T) {
+		_, err := get(cache, simplesql.WithTenant(ctx, "tenant1"), byID)
+		require.NoError(t, err)
+		require.Equal(t, 3, reads)
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		rows["row1"] = cachedRow{ID: "row1", Name: "name2", Version: 2}
+		cache.Invalidate(ctx, nil, []interface{}{"row1"}, 2)
+
+		row, err := get(cache, ctx, byID)
+		require.NoError(t, err)
+		require.Equal(t, "name2", row.Name)
+
+		// The key of the old name must not point to the renamed row.
+		_, err = get(cache, ctx, byName)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		require.Equal(t, 5, reads)
+	})
+
+	t.Run("Negative", func(t *testing.T) {
+		_, err := get(cache, ctx, byName)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		require.EqualError(t, err, "table 'row': record not found")
+		require.Equal(t, 5, reads)
+		require.Equal(t, uint64(1), cache.Stats().NegativeHits)
+
+		rows["row2"] = cachedRow{ID: "row2", Name: "name1", Version: 1}
+		cache.InvalidateNotFound(nil)
+		row, err := get(cache, ctx, byName)
+		require.NoError(t, err)
+		require.Equal(t, "row2", row.ID)
+		require.Equal(t, 6, reads)
+	})
+
+	t.Run("Stale versions", func(t *testing.T) {
+		// An Update to version 3 through another replica of the table is not yet visible.
+		cache.Invalidate(ctx, nil, []interface{}{"row1"}, 3)
+		row, err := get(cache, ctx, byID)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), row.Version)
+		_, err = get(cache, ctx, byID)
+		require.NoError(t, err)
+		require.Equal(t, 8, reads)
+
+		rows["row1"] = cachedRow{ID: "row1", Name: "name2", Version: 3}
+		_, err = get(cache, ctx, byID)
+		require.NoError(t, err)
+		_, err = get(cache, ctx, byID)
+		require.NoError(t, err)
+		require.Equal(t, 9, reads)
+	})
+
+	t.Run("Racing reads", func(t *testing.T) {
+		// A read started before an invalidation is not cached.
+		key := cachedRowKey{ID: StringPtr("row2")}
+		_, err := cache.Get(ctx, key, func() (cachedRow, error) {
+			cache.InvalidateAll(nil)
+			return read(key)()
+		})
+		require.NoError(t, err)
+		_, err = get(cache, ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, 11, reads)
+	})
+
+	t.Run("Invalidate all", func(t *testing.T) {
+		cache.InvalidateAll(nil)
+		_, err := get(cache, ctx, byID)
+		require.NoError(t, err)
+		require.Equal(t, 12, reads)
+	})
+
+	t.Run("Transactions", func(t *testing.T) {
+		// The reads made before the commit return, and may cache, the rows before the writes.
+		key := cachedRowKey{ID: StringPtr("row2")}
+		tx := &pendingTx{}
+		cache.Invalidate(ctx, tx, []interface{}{"row2"}, 0)
+		row, err := get(cache, ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, "name1", row.Name)
+
+		rows["row2"] = cachedRow{ID: "row2", Name: "name3", Version: 1}
+		tx.commit()
+		row, err = get(cache, ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, "name3", row.Name)
+
+		tx = &pendingTx{}
+		cache.Invalidate(ctx, tx, []interface{}{"row2"}, 0)
+		_, err = get(cache, ctx, key)
+		require.NoError(t, err)
+		delete(rows, "row2")
+		tx.commit()
+		_, err = get(cache, ctx, key)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+		tx = &pendingTx{}
+		cache.InvalidateNotFound(tx)
+		_, err = get(cache, ctx, key)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		rows["row2"] = cachedRow{ID: "row2", Name: "name4", Version: 1}
+		tx.commit()
+		row, err = get(cache, ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, "name4", row.Name)
+
+		tx = &pendingTx{}
+		cache.InvalidateAll(tx)
+		_, err = get(cache, ctx, key)
+		require.NoError(t, err)
+		rows["row2"] = cachedRow{ID: "row2", Name: "name5", Version: 1}
+		tx.commit()
+		row, err = get(cache, ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, "name5", row.Name)
+
+		// The version of an Update rolled back must not keep the row from being cached.
+		tx = &pendingTx{}
+		cache.Invalidate(ctx, tx, []interface{}{"row2"}, 2)
+		start := reads
+		for i := 0; i < 3; i++ {
+			row, err = get(cache, ctx, key)
+			require.NoError(t, err)
+			require.Equal(t, uint64(1), row.Version)
+		}
+		require.Equal(t, start+1, reads)
+
+		tx = &pendingTx{}
+		cache.Invalidate(ctx, tx, []interface{}{"row2"}, 2)
+		tx.commit()
+		for i := 0; i < 2; i++ {
+			_, err = get(cache, ctx, key)
+			require.NoError(t, err)
+		}
+		require.Equal(t, start+3, reads)
+	})
+}
@@ -0,0 +1,339 @@
+package simplesql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Operation is the kind of mutation recorded in a history table or in the outbox.
+type Operation string
+
+const (
+	OperationInsert Operation = "insert"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the actor recorded in the history of audited tables
+// and in the outbox.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by the context, or an empty string.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// changeTracker holds the tables whose changes are recorded, see WithAudit and WithOutbox,
+// and caches their primary key columns.
+type changeTracker struct {
+	audited  map[string]bool
+	outboxed map[string]bool
+
+	mu          sync.Mutex
+	primaryKeys map[string][]string
+}
+
+// trackChanges returns the change tracker of the database, creating it if needed.
+func (d *Database) trackChanges() *changeTracker {
+	if d.tracker == nil {
+		d.tracker = &changeTracker{
+			audited:     map[string]bool{},
+			outboxed:    map[string]bool{},
+			primaryKeys: map[string][]string{},
+		}
+	}
+	return d.tracker
+}
+
+func (d *Database) isTracked(tableName string) bool {
+	return d.tracker != nil && (d.tracker.audited[tableName] || d.tracker.outboxed[tableName])
+}
+
+// changeExecer runs both the mutation and the queries capturing the row images.
+type changeExecer interface {
+	sqlx.ExecerContext
+	sqlx.QueryerContext
+}
+
+// rowImage is a row keyed by column name.
+type rowImage map[string]interface{}
+
+// rowChange is the change of a single row. before is nil for inserts and after is nil for deletes.
+type rowChange struct {
+	before rowImage
+	after  rowImage
+}
+
+// keyImage returns the image holding the primary key of the changed row.
+func (c rowChange) keyImage() rowImage {
+	if c.after != nil {
+		return c.after
+	}
+	return c.before
+}
+
+// withChangeTx runs fn in the transaction of the execer. A *sqlx.DB is not a transaction, so
+// one is started and committed if fn succeeds.
+func (d *Database) withChangeTx(ctx context.Context, execer sqlx.ExecerContext, fn func(tx changeExecer) error) error {
+	if db, ok := execer.(*sqlx.DB); ok {
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return d.handleErr("", err)
+		}
+		if err := fn(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return d.handleErr("", tx.Commit())
+	}
+	tx, ok := execer.(changeExecer)
+	if !ok {
+		return fmt.Errorf("audited and outboxed tables need an execer which can also query, such as *sqlx.Tx: %w", ErrInternal)
+	}
+	return fn(tx)
+}
+
+// primaryKey returns the primary key columns of the table, reading them from the database once.
+func (d *Database) primaryKey(ctx context.Context, q sqlx.QueryerContext, tableName string) ([]string, error) {
+	d.tracker.mu.Lock()
+	defer d.tracker.mu.Unlock()
+	if columns, ok := d.tracker.primaryKeys[tableName]; ok {
+		return columns, nil
+	}
+
+	var query string
+	switch d.Dialect() {
+	case DialectSQLite:
+		query = `SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`
+	case DialectMySQL:
+		query = `
+			SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+			ORDER BY ORDINAL_POSITION
+		`
+	default:
+		return nil, fmt.Errorf("change tracking is not supported for driver '%s': %w", d.DB.DriverName(), ErrInternal)
+	}
+
+	var columns []string
+	if err := sqlx.SelectContext(ctx, q, &columns, query, tableName); err != nil {
+		return nil, fmt.Errorf("failed to read primary key of table '%s': %s: %w", tableName, err.Error(), ErrInternal)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("tracked table '%s' has no primary key: %w", tableName, ErrInternal)
+	}
+	d.tracker.primaryKeys[tableName] = columns
+	return columns, nil
+}
+
+// selectImages returns the images of the rows of the table matching the where conditions.
+func (d *Database) selectImages(
+	ctx context.Context, q sqlx.QueryerContext, tableName string, where string, params []interface{},
+) ([]rowImage, error) {
+	query := d.DB.Rebind(fmt.Sprintf(`SELECT * FROM %s WHERE 1=1%s`, tableName, where))
+	rows, err := q.QueryxContext(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	var images []rowImage
+	for rows.Next() {
+		image := rowImage{}
+		if err := rows.MapScan(image); err != nil {
+			return nil, err
+		}
+		for _, columnType := range columnTypes {
+			// Drivers return text as bytes, only binary columns are kept as bytes.
+			typeName := strings.ToUpper(columnType.DatabaseTypeName())
+			if b, ok := image[columnType.Name()].([]byte); ok && !strings.Contains(typeName, "BLOB") && !strings.Contains(typeName, "BINARY") {
+				image[columnType.Name()] = string(b)
+			}
+			if t, ok := image[columnType.Name()].(time.Time); ok {
+				image[columnType.Name()] = t.UTC()
+			}
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+// primaryKeyWhere returns the conditions matching the row of the image by primary key.
+func primaryKeyWhere(primaryKey []string, image rowImage) (string, []interface{}) {
+	where := ""
+	params := []interface{}{}
+	for _, column := range primaryKey {
+		where += fmt.Sprintf(" AND %s = ?", column)
+		params = append(params, image[column])
+	}
+	return where, params
+}
+
+func marshalImage(image rowImage) (interface{}, error) {
+	if image == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal row image: %s: %w", err.Error(), ErrInternal)
+	}
+	return string(b), nil
+}
+
+// trackInsert records the insert of the row whose columns are params.
+func (d *Database) trackInsert(ctx context.Context, tx changeExecer, tableName string, params map[string]interface{}) error {
+	primaryKey, err := d.primaryKey(ctx, tx, tableName)
+	if err != nil {
+		return err
+	}
+	where, whereParams := primaryKeyWhere(primaryKey, rowImage(params))
+	after, err := d.selectImages(ctx, tx, tableName, where, whereParams)
+	if err != nil {
+		return d.handleErr(tableName, err)
+	}
+
+	changes := make([]rowChange, 0, len(after))
+	for _, image := range after {
+		changes = append(changes, rowChange{after: image})
+	}
+	return d.recordChanges(ctx, tx, tableName, OperationInsert, primaryKey, changes)
+}
+
+// trackMutation captures the rows matching the where conditions of the mutation, runs the mutation
+// and records the change of each row. Rows are read back by primary key after an update since the
+// conditions may include columns the update modifies, such as the version.
+func (d *Database) trackMutation(
+	ctx context.Context, tx changeExecer, tableName string, operation Operation, where string, params []interface{}, mutate func() error,
+) error {
+	primaryKey, err := d.primaryKey(ctx, tx, tableName)
+	if err != nil {
+		return err
+	}
+	before, err := d.selectImages(ctx, tx, tableName, where, params)
+	if err != nil {
+		return d.handleErr(tableName, err)
+	}
+
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	changes := make([]rowChange, 0, len(before))
+	for _, image := range before {
+		change := rowChange{before: image}
+		if operation == OperationUpdate {
+			where, params := primaryKeyWhere(primaryKey, image)
+			images, err := d.selectImages(ctx, tx, tableName, where, params)
+			if err != nil {
+				return d.handleErr(tableName, err)
+			}
+			if len(images) != 1 {
+				return fmt.Errorf("updated row of table '%s' not found: %w", tableName, ErrInternal)
+			}
+			change.after = images[0]
+		}
+		changes = append(changes, change)
+	}
+	return d.recordChanges(ctx, tx, tableName, operation, primaryKey, changes)
+}
+
+// recordChanges writes the changes to the history table and to the outbox, as configured for the table.
+func (d *Database) recordChanges(
+	ctx context.Context, tx changeExecer, tableName string, operation Operation, primaryKey []string, changes []rowChange,
+) error {
+	changedAt := d.clock.Now().UTC()
+	if d.tracker.audited[tableName] {
+		if err := d.recordHistory(ctx, tx, tableName, operation, primaryKey, changes, changedAt); err != nil {
+			return err
+		}
+	}
+	if d.tracker.outboxed[tableName] {
+		if err := d.recordOutbox(ctx, tx, tableName, operation, primaryKey, changes, changedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeImage decodes a JSON row image into dest, a pointer to a row struct, matching the
+// columns of the image with the db tags of the struct.
+func DecodeImage(image json.RawMessage, dest interface{}) error {
+	var columns map[string]json.RawMessage
+	if err := json.Unmarshal(image, &columns); err != nil {
+		return fmt.Errorf("failed to decode row image: %s: %w", err.Error(), ErrInternal)
+	}
+
+	v := reflect.ValueOf(dest).Elem()
+	fields, err := dbFields(v.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		column := field.Tag.Get("db")
+		raw, ok := columns[column]
+		if !ok {
+			continue
+		}
+		if err := decodeColumn(field, v.FieldByIndex(field.Index).Addr(), raw); err != nil {
+			return fmt.Errorf("failed to decode column %s of row image: %s: %w", column, err.Error(), ErrInternal)
+		}
+	}
+	normalizeTimes(v, fields)
+	return nil
+}
+
+// decodeColumn decodes the JSON value of a column into the field target points to.
+func decodeColumn(field reflect.StructField, target reflect.Value, raw json.RawMessage) error {
+	isNull := string(raw) == "null"
+	_, isUnmarshaler := target.Interface().(json.Unmarshaler)
+	scanner, isScanner := target.Interface().(sql.Scanner)
+
+	switch {
+	case isJSONField(field) && !isNull:
+		// The image holds the JSON document of the column as a string.
+		var doc string
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return err
+		}
+		return json.Unmarshal([]byte(doc), target.Interface())
+	case isUnmarshaler:
+		return json.Unmarshal(raw, target.Interface())
+	case isScanner:
+		decoder := json.NewDecoder(strings.NewReader(string(raw)))
+		decoder.UseNumber()
+		var src interface{}
+		if err := decoder.Decode(&src); err != nil {
+			return err
+		}
+		if n, ok := src.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				src = i
+			} else if src, err = n.Float64(); err != nil {
+				return err
+			}
+		}
+		return scanner.Scan(src)
+	case isNull:
+		target.Elem().Set(reflect.Zero(target.Elem().Type()))
+		return nil
+	}
+	return json.Unmarshal(raw, target.Interface())
+}
@@ -0,0 +1,86 @@
+package simplesql
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Clock is the source of the time of automatic timestamps and of change records.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock replaces the system clock, e.g. with a test clock for deterministic timestamps.
+func WithClock(clock Clock) Option {
+	return func(d *Database) {
+		d.clock = clock
+	}
+}
+
+// Automatic timestamps are columns tagged `orm:"auto=create_time"` or `orm:"auto=update_time"`.
+// Insert stamps both in the row with the time of the clock, and Update and UpdateWhere stamp the
+// update_time columns of the row struct registered for the table, see RegisterTable. Tables whose
+// row is not registered are stamped only if the fields struct declares the columns, usually by a
+// blank field such as
+//
+//	_ time.Time `db:"last_updated_at" orm:"auto=update_time"`
+//
+// The columns are time.Time, *time.Time and Nullable[time.Time] fields, or integer fields holding
+// Unix milliseconds.
+const (
+	autoCreateTime = "auto=create_time"
+	autoUpdateTime = "auto=update_time"
+)
+
+// autoFields returns the fields of the struct type stamped with the auto option.
+func autoFields(t reflect.Type, option string) ([]reflect.StructField, error) {
+	fields, err := dbFields(t)
+	if err != nil {
+		return nil, err
+	}
+	var auto []reflect.StructField
+	for _, field := range fields {
+		if hasORMOption(field, option) {
+			auto = append(auto, field)
+		}
+	}
+	return auto, nil
+}
+
+// timestampValue returns the time as a value of the type of an automatic timestamp field.
+func timestampValue(t time.Time, typ reflect.Type) (reflect.Value, error) {
+	t = t.UTC()
+	v := reflect.New(typ).Elem()
+	if err := setTimestamp(v, t); err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// setTimestamp sets an automatic timestamp field to the time.
+func setTimestamp(v reflect.Value, t time.Time) error {
+	switch {
+	case v.Type() == timeType:
+		v.Set(reflect.ValueOf(t))
+	case v.Kind() == reflect.Ptr && v.Type().Elem() == timeType:
+		v.Set(reflect.ValueOf(&t))
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		v.SetInt(t.UnixMilli())
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		v.SetUint(uint64(t.UnixMilli()))
+	default:
+		n, ok := asNullable(v)
+		if !ok || n.elemType() != timeType {
+			return fmt.Errorf("automatic timestamp of type %s is not a time or an integer: %w", v.Type(), ErrInternal)
+		}
+		v.Set(reflect.ValueOf(NewNullable(t)))
+	}
+	return nil
+}
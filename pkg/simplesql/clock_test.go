@@ -0,0 +1,116 @@
+package simplesql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+var noteTableMigrations = []simplesql.Migration{
+	{
+		Version: 1,
+		Up: `
+			CREATE TABLE note (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				body TEXT NOT NULL,
+				created_at DATETIME NOT NULL,
+				updated_at DATETIME
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS note;
+			`,
+	},
+}
+
+type NoteRow struct {
+	ID        string                        `db:"id"`
+	Body      string                        `db:"body"`
+	CreatedAt time.Time                     `db:"created_at" orm:"auto=create_time"`
+	UpdatedAt simplesql.Nullable[time.Time] `db:"updated_at" orm:"auto=update_time"`
+}
+
+type NoteTableKeys struct {
+	ID string `db:"id"`
+}
+
+type NoteTableUpdateFields struct {
+	_    time.Time `db:"updated_at" orm:"auto=update_time"`
+	Body *string   `db:"body"`
+}
+
+// NoteBodyFields does not declare the update timestamp, which the registered row does.
+type NoteBodyFields struct {
+	Body      *string    `db:"body"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+func TestClock(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	clock := test.NewClock(start)
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithClock(clock))
+	require.NoError(t, simplesqlDb.ApplyMigrations(noteTableMigrations))
+
+	ctx := context.Background()
+	// Timestamps set by the caller are replaced.
+	err = simplesqlDb.Insert(ctx, db, "note", NoteRow{ID: "note0", Body: "hello", CreatedAt: start.Add(-time.Hour)})
+	require.NoError(t, err)
+
+	var row NoteRow
+	require.NoError(t, simplesqlDb.Get(ctx, "note", NoteTableKeys{ID: "note0"}, &row))
+	require.Equal(t, start.UTC(), row.CreatedAt)
+	require.Equal(t, simplesql.NewNullable(start.UTC()), row.UpdatedAt)
+
+	clock.Advance(time.Hour)
+	err = simplesqlDb.Update(ctx, db, "note", NoteTableKeys{ID: "note0"}, NoteTableUpdateFields{Body: StringPtr("bye")})
+	require.NoError(t, err)
+	require.NoError(t, simplesqlDb.Get(ctx, "note", NoteTableKeys{ID: "note0"}, &row))
+	require.Equal(t, start.UTC(), row.CreatedAt)
+	require.Equal(t, simplesql.NewNullable(clock.Now().UTC()), row.UpdatedAt)
+
+	// The update timestamp of a registered table is stamped whatever the fields struct declares.
+	registered := simplesql.NewDatabase(db, simplesql.WithClock(clock), simplesql.WithTable("note", NoteRow{}))
+	clock.Advance(time.Hour)
+	err = registered.Update(ctx, db, "note", NoteTableKeys{ID: "note0"}, NoteBodyFields{Body: StringPtr("again")})
+	require.NoError(t, err)
+	require.NoError(t, registered.Get(ctx, "note", NoteTableKeys{ID: "note0"}, &row))
+	require.Equal(t, "again", row.Body)
+	require.Equal(t, simplesql.NewNullable(clock.Now().UTC()), row.UpdatedAt)
+
+	clock.Advance(time.Hour)
+	_, err = registered.UpdateWhere(ctx, db, "note", struct {
+		IDIn []string `db:"id:in"`
+	}{IDIn: []string{"note0"}}, NoteBodyFields{Body: StringPtr("once more"), UpdatedAt: &start})
+	require.NoError(t, err)
+	// Setting only the stamped timestamps is no update.
+	err = registered.Update(ctx, db, "note", NoteTableKeys{ID: "note0"}, NoteBodyFields{UpdatedAt: &start})
+	require.ErrorIs(t, err, simplesql.ErrInternal)
+	require.NoError(t, registered.Get(ctx, "note", NoteTableKeys{ID: "note0"}, &row))
+	require.Equal(t, simplesql.NewNullable(clock.Now().UTC()), row.UpdatedAt)
+
+	// The memory table stamps the same columns.
+	memory := simplesql.NewMemoryTable[NoteRow]("note", simplesql.WithMemoryClock(clock))
+	created := clock.Now().UTC()
+	require.NoError(t, memory.Insert(ctx, NoteRow{ID: "note0"}))
+	clock.Advance(time.Hour)
+	require.NoError(t, memory.Update(ctx, NoteTableKeys{ID: "note0"}, NoteTableUpdateFields{Body: StringPtr("bye")}))
+	row, err = memory.Get(ctx, NoteTableKeys{ID: "note0"})
+	require.NoError(t, err)
+	require.Equal(t, created, row.CreatedAt)
+	require.Equal(t, simplesql.NewNullable(clock.Now().UTC()), row.UpdatedAt)
+
+	clock.Advance(time.Hour)
+	require.NoError(t, memory.Update(ctx, NoteTableKeys{ID: "note0"}, NoteBodyFields{UpdatedAt: &start}))
+	row, err = memory.Get(ctx, NoteTableKeys{ID: "note0"})
+	require.NoError(t, err)
+	require.Equal(t, simplesql.NewNullable(clock.Now().UTC()), row.UpdatedAt)
+}
@@ -0,0 +1,298 @@
+package simplesql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// dbFields returns the fields of a struct type which map to a column through a db tag.
+//
+// The fields of embedded structs without a db tag are flattened into the struct, as are those of
+// struct fields tagged with a column prefix ending in an underscore, e.g. `db:"audit_"`, whose
+// columns are prefixed with it. The returned fields hold the full index path and prefixed db tag,
+// and are shared by the callers: they must not be modified. An error is returned if two fields map
+// to the same column.
+func dbFields(t reflect.Type) ([]reflect.StructField, error) {
+	if cached, ok := fieldsCache.Load(t); ok {
+		c := cached.(cachedFields)
+		return c.fields, c.err
+	}
+
+	fields := flattenFields(t, nil, "", "")
+	var err error
+	fieldsByColumn := map[string]reflect.StructField{}
+	for _, field := range fields {
+		column := field.Tag.Get("db")
+		if other, ok := fieldsByColumn[column]; ok {
+			err = fmt.Errorf("fields %s and %s of %s map to the same column %s: %w", other.Name, field.Name, t, column, ErrInternal)
+			break
+		}
+		fieldsByColumn[column] = field
+	}
+	fieldsCache.Store(t, cachedFields{fields: fields, err: err})
+	return fields, err
+}
+
+// fieldsCache holds the cachedFields of the struct types, flattened once.
+var fieldsCache sync.Map
+
+type cachedFields struct {
+	fields []reflect.StructField
+	err    error
+}
+
+func flattenFields(t reflect.Type, index []int, namePrefix, columnPrefix string) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		dbTag := field.Tag.Get("db")
+		if dbTag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		switch {
+		case dbTag == "" && field.Anonymous && isNestedStruct(field.Type):
+			fields = append(fields, flattenFields(field.Type, fieldIndex, namePrefix, columnPrefix)...)
+			continue
+		case strings.HasSuffix(dbTag, "_") && field.IsExported() && isNestedStruct(field.Type):
+			fields = append(fields, flattenFields(field.Type, fieldIndex, namePrefix+field.Name, columnPrefix+dbTag)...)
+			continue
+		case dbTag == "":
+			continue
+		}
+		field.Index = fieldIndex
+		field.Name = namePrefix + field.Name
+		if columnPrefix != "" {
+			field.Tag = reflect.StructTag(strings.Replace(string(field.Tag), `db:"`+dbTag+`"`, `db:"`+columnPrefix+dbTag+`"`, 1))
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// isNestedStruct returns true if the fields of a struct type are columns, rather than the struct
+// being the value of a column like time.Time, Nullable or a sql.Scanner.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType &&
+		!t.Implements(valuerType) && !reflect.PointerTo(t).Implements(scannerType)
+}
+
+// hasORMOption returns true if the orm tag of the field contains the bare option, e.g. `orm:"json"`.
+func hasORMOption(field reflect.StructField, option string) bool {
+	for _, part := range strings.Fields(field.Tag.Get("orm")) {
+		if part == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONField returns true if the field is stored as a JSON document.
+func isJSONField(field reflect.StructField) bool {
+	return hasORMOption(field, "json")
+}
+
+// columnValue converts the value of a field into the value bound to a query.
+// JSON fields are marshalled and times are normalised to UTC so that they compare
+// consistently across MySQL and SQLite. sql.Scanner/driver.Valuer types are passed through.
+func columnValue(field reflect.StructField, value reflect.Value) (interface{}, error) {
+	if isJSONField(field) {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil, nil
+		}
+		b, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal column %s: %s: %w", field.Tag.Get("db"), err.Error(), ErrInternal)
+		}
+		return string(b), nil
+	}
+	return normalizeValue(value), nil
+}
+
+// normalizeValue returns the value to be bound for v, converting times to UTC.
+// A non-nil pointer whose element is not a driver.Valuer is dereferenced, except when the
+// pointer itself is the driver.Valuer (a Value method with a pointer receiver).
+func normalizeValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Implements(valuerType) && !v.Elem().Type().Implements(valuerType) {
+			return v.Interface()
+		}
+		return normalizeValue(v.Elem())
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.UTC()
+	}
+	return v.Interface()
+}
+
+// normalizeSlice returns the values of a slice used in an IN clause, converting times to UTC.
+func normalizeSlice(v reflect.Value) interface{} {
+	if v.Type().Elem() != timeType {
+		return v.Interface()
+	}
+	values := make([]time.Time, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface().(time.Time).UTC()
+	}
+	return values
+}
+
+// structParams returns the named parameters for every column of a struct, keyed by column name.
+func structParams(row interface{}) (map[string]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(row))
+	fields, err := dbFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{}
+	for _, field := range fields {
+		value, err := columnValue(field, v.FieldByIndex(field.Index))
+		if err != nil {
+			return nil, err
+		}
+		params[field.Tag.Get("db")] = value
+	}
+	return params, nil
+}
+
+// jsonColumn scans a JSON document into the field it points to.
+type jsonColumn struct {
+	dest reflect.Value
+}
+
+func (j jsonColumn) Scan(src interface{}) error {
+	var b []byte
+	switch s := src.(type) {
+	case nil:
+		j.dest.Elem().Set(reflect.Zero(j.dest.Elem().Type()))
+		return nil
+	case []byte:
+		b = s
+	case string:
+		b = []byte(s)
+	default:
+		return fmt.Errorf("cannot scan %T into a JSON column", src)
+	}
+	return json.Unmarshal(b, j.dest.Interface())
+}
+
+// scanStruct scans the current row into dest, which must be an addressable struct value.
+func scanStruct(rows *sqlx.Rows, dest reflect.Value) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields, err := dbFields(dest.Type())
+	if err != nil {
+		return err
+	}
+	fieldsByColumn := map[string]reflect.StructField{}
+	for _, field := range fields {
+		fieldsByColumn[field.Tag.Get("db")] = field
+	}
+
+	targets := make([]interface{}, len(columns))
+	for i, column := range columns {
+		field, ok := fieldsByColumn[column]
+		if !ok {
+			return fmt.Errorf("missing destination name %s in %s: %w", column, dest.Type().Name(), ErrInternal)
+		}
+		target := dest.FieldByIndex(field.Index).Addr()
+		if isJSONField(field) {
+			targets[i] = jsonColumn{dest: target}
+		} else {
+			targets[i] = target.Interface()
+		}
+	}
+
+	if err := rows.Scan(targets...); err != nil {
+		return err
+	}
+	normalizeTimes(dest, fields)
+	return nil
+}
+
+// normalizeTimes converts the time columns scanned into the struct, of the fields, to UTC.
+func normalizeTimes(dest reflect.Value, fields []reflect.StructField) {
+	for _, field := range fields {
+		value := dest.FieldByIndex(field.Index)
+		switch t := value.Addr().Interface().(type) {
+		case *time.Time:
+			*t = t.UTC()
+		case **time.Time:
+			if *t != nil {
+				utc := (*t).UTC()
+				*t = &utc
+			}
+		case *sql.NullTime:
+			t.Time = t.Time.UTC()
+		}
+	}
+}
+
+// getRow runs the query and scans the first row into dest, a pointer to a struct.
+func (d *Database) getRow(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows, err := d.DB.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := scanStruct(rows, reflect.ValueOf(dest).Elem()); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// selectRows runs the query and scans every row into dest, a pointer to a slice of structs.
+func (d *Database) selectRows(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows, err := d.DB.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	slice := reflect.ValueOf(dest).Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := scanStruct(rows, elem.Elem()); err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	return rows.Err()
+}
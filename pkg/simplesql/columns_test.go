@@ -0,0 +1,342 @@
+package simplesql_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+var jobTableMigrations = []simplesql.Migration{
+	{
+		Version: 1,
+		Up: `
+			CREATE TABLE job (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				scheduled_at DATETIME NOT NULL,
+				finished_at DATETIME,
+				labels TEXT NOT NULL,
+				spec TEXT,
+				priority VARCHAR(255) NOT NULL,
+				zone VARCHAR(255)
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS job;
+			`,
+	},
+}
+
+type JobSpec struct {
+	Image    string `json:"image"`
+	Replicas int    `json:"replicas"`
+}
+
+// Priority is stored as "p<level>" through a value receiver Valuer.
+type Priority struct {
+	Level int
+}
+
+func (p Priority) Value() (driver.Value, error) {
+	return fmt.Sprintf("p%d", p.Level), nil
+}
+
+func (p *Priority) Scan(src interface{}) error {
+	_, err := fmt.Sscanf(asString(src), "p%d", &p.Level)
+	return err
+}
+
+// Zone implements driver.Valuer with a pointer receiver.
+type Zone struct {
+	Name string
+}
+
+func (z *Zone) Value() (driver.Value, error) {
+	return "zone-" + z.Name, nil
+}
+
+func (z *Zone) Scan(src interface{}) error {
+	z.Name = strings.TrimPrefix(asString(src), "zone-")
+	return nil
+}
+
+func asString(src interface{}) string {
+	if b, ok := src.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(src)
+}
+
+type JobRow struct {
+	ID          string            `db:"id"`
+	ScheduledAt time.Time         `db:"scheduled_at"`
+	FinishedAt  *time.Time        `db:"finished_at"`
+	Labels      map[string]string `db:"labels" orm:"json"`
+	Spec        *JobSpec          `db:"spec" orm:"json"`
+	Priority    Priority          `db:"priority"`
+	Zone        *Zone             `db:"zone"`
+}
+
+type JobTableKeys struct {
+	ID string `db:"id"`
+}
+
+type JobTableUpdateFields struct {
+	FinishedAt *time.Time `db:"finished_at"`
+	Spec       *JobSpec   `db:"spec" orm:"json"`
+	Priority   *Priority  `db:"priority"`
+	Zone       *Zone      `db:"zone"`
+}
+
+type JobTableSelectFilters struct {
+	ScheduledAtGte time.Time  `db:"scheduled_at:gte"`
+	PriorityIn     []Priority `db:"priority:in"`
+	Limit          uint32     `db:"limit"`
+}
+
+func TestColumnTypes(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+	err = simplesqlDb.ApplyMigrations(jobTableMigrations)
+	require.NoError(t, err)
+
+	pst := time.FixedZone("PST", -8*60*60)
+	scheduledAt := time.Date(2024, 11, 5, 10, 30, 0, 0, pst)
+
+	t.Run("Schema", func(t *testing.T) {
+		diff, err := simplesqlDb.VerifySchema(context.Background(), "job", JobRow{})
+		require.NoError(t, err)
+		require.NoError(t, diff.Err())
+	})
+
+	t.Run("Insert", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			err := simplesqlDb.Insert(context.Background(), db, "job", JobRow{
+				ID:          fmt.Sprintf("job%d", i),
+				ScheduledAt: scheduledAt.Add(time.Duration(i) * time.Hour),
+				Labels:      map[string]string{"team": "storage"},
+				Spec:        &JobSpec{Image: "busybox", Replicas: i},
+				Priority:    Priority{Level: i},
+			})
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		var job JobRow
+		err := simplesqlDb.Get(context.Background(), "job", JobTableKeys{ID: "job1"}, &job)
+		require.NoError(t, err)
+		require.Equal(t, time.UTC, job.ScheduledAt.Location())
+		require.True(t, scheduledAt.Add(time.Hour).Equal(job.ScheduledAt))
+		require.Nil(t, job.FinishedAt)
+		require.Equal(t, map[string]string{"team": "storage"}, job.Labels)
+		require.Equal(t, &JobSpec{Image: "busybox", Replicas: 1}, job.Spec)
+		require.Equal(t, Priority{Level: 1}, job.Priority)
+		require.Nil(t, job.Zone)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		finishedAt := scheduledAt.Add(2 * time.Hour)
+		err := simplesqlDb.Update(context.Background(), db, "job", JobTableKeys{ID: "job1"}, JobTableUpdateFields{
+			FinishedAt: &finishedAt,
+			Spec:       &JobSpec{Image: "alpine", Replicas: 3},
+			Priority:   &Priority{Level: 7},
+			Zone:       &Zone{Name: "us-west"},
+		})
+		require.NoError(t, err)
+
+		var job JobRow
+		err = simplesqlDb.Get(context.Background(), "job", JobTableKeys{ID: "job1"}, &job)
+		require.NoError(t, err)
+		require.NotNil(t, job.FinishedAt)
+		require.Equal(t, finishedAt.UTC(), *job.FinishedAt)
+		require.Equal(t, &JobSpec{Image: "alpine", Replicas: 3}, job.Spec)
+		require.Equal(t, Priority{Level: 7}, job.Priority)
+		require.Equal(t, &Zone{Name: "us-west"}, job.Zone)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var jobs []JobRow
+		err := simplesqlDb.List(context.Background(), "job", JobTableSelectFilters{
+			ScheduledAtGte: scheduledAt.Add(time.Hour),
+		}, &jobs)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+
+		jobs = nil
+		err = simplesqlDb.List(context.Background(), "job", JobTableSelectFilters{
+			PriorityIn: []Priority{{Level: 0}, {Level: 7}},
+		}, &jobs)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+	})
+}
+
+// AuditColumns are the columns shared by the rows of every table.
+type AuditColumns struct {
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at" orm:"auto=create_time"`
+}
+
+type QuotaColumns struct {
+	CPU    int64 `db:"cpu"`
+	Memory int64 `db:"memory"`
+}
+
+type VolumeRow struct {
+	ID string `db:"id"`
+	AuditColumns
+	Limits QuotaColumns `db:"limit_"`
+}
+
+type VolumeTableKeys struct {
+	ID string `db:"id"`
+}
+
+type VolumeTableUpdateFields struct {
+	LimitsCPU *int64 `db:"limit_cpu"`
+}
+
+type VolumeTableSelectFilters struct {
+	CreatedByEq     *string `db:"created_by:eq"`
+	LimitsMemoryGte *int64  `db:"limit_memory:gte"`
+}
+
+// VolumeOwnerFields, VolumeOwnerFilters and VolumeIDKey are embedded by the fields, filters and
+// keys structs of the volume table.
+type VolumeOwnerFields struct {
+	CreatedBy *string `db:"created_by"`
+}
+
+type VolumeOwnerFilters struct {
+	CreatedByEq *string `db:"created_by:eq"`
+}
+
+type VolumeIDKey struct {
+	ID string `db:"id"`
+}
+
+type VolumeTableOwnerUpdateFields struct {
+	VolumeOwnerFields
+	LimitsCPU *int64 `db:"limit_cpu"`
+}
+
+type VolumeTableOwnerSelectFilters struct {
+	VolumeOwnerFilters
+}
+
+type VolumeTableIDKeys struct {
+	VolumeIDKey
+}
+
+func TestEmbeddedColumns(t *testing.T) {
+	ctx := context.Background()
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	clock := test.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler), simplesql.WithClock(clock))
+	err = simplesqlDb.ApplyMigrations([]simplesql.Migration{{
+		Version: 1,
+		Up: `
+			CREATE TABLE volume (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				created_by VARCHAR(255) NOT NULL,
+				created_at DATETIME NOT NULL,
+				limit_cpu BIGINT NOT NULL,
+				limit_memory BIGINT NOT NULL
+			);
+		`,
+	}})
+	require.NoError(t, err)
+
+	diff, err := simplesqlDb.VerifySchema(ctx, "volume", VolumeRow{})
+	require.NoError(t, err)
+	require.NoError(t, diff.Err())
+
+	memoryTable := simplesql.NewMemoryTable[VolumeRow]("volume", simplesql.WithMemoryClock(clock))
+	for i, row := range []VolumeRow{
+		{ID: "vol1", AuditColumns: AuditColumns{CreatedBy: "alice"}, Limits: QuotaColumns{CPU: 1, Memory: 512}},
+		{ID: "vol2", AuditColumns: AuditColumns{CreatedBy: "bob"}, Limits: QuotaColumns{CPU: 2, Memory: 1024}},
+	} {
+		require.NoError(t, simplesqlDb.Insert(ctx, db, "volume", row), i)
+		require.NoError(t, memoryTable.Insert(ctx, row), i)
+	}
+
+	cpu := int64(4)
+	require.NoError(t, simplesqlDb.Update(ctx, db, "volume", VolumeTableKeys{ID: "vol1"}, VolumeTableUpdateFields{LimitsCPU: &cpu}))
+	require.NoError(t, memoryTable.Update(ctx, VolumeTableKeys{ID: "vol1"}, VolumeTableUpdateFields{LimitsCPU: &cpu}))
+
+	expected := VolumeRow{
+		ID:           "vol1",
+		AuditColumns: AuditColumns{CreatedBy: "alice", CreatedAt: clock.Now()},
+		Limits:       QuotaColumns{CPU: 4, Memory: 512},
+	}
+	var row VolumeRow
+	require.NoError(t, simplesqlDb.Get(ctx, "volume", VolumeTableKeys{ID: "vol1"}, &row))
+	require.Equal(t, expected, row)
+	row, err = memoryTable.Get(ctx, VolumeTableKeys{ID: "vol1"})
+	require.NoError(t, err)
+	require.Equal(t, expected, row)
+
+	memory := int64(1024)
+	var rows []VolumeRow
+	require.NoError(t, simplesqlDb.List(ctx, "volume", VolumeTableSelectFilters{LimitsMemoryGte: &memory}, &rows))
+	require.Len(t, rows, 1)
+	require.Equal(t, "bob", rows[0].CreatedBy)
+	rows, err = memoryTable.List(ctx, VolumeTableSelectFilters{CreatedByEq: StringPtr("bob")})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int64(1024), rows[0].Limits.Memory)
+
+	t.Run("Embedded keys, fields and filters", func(t *testing.T) {
+		key := VolumeTableIDKeys{VolumeIDKey{ID: "vol2"}}
+		fields := VolumeTableOwnerUpdateFields{VolumeOwnerFields: VolumeOwnerFields{CreatedBy: StringPtr("carol")}, LimitsCPU: &cpu}
+		require.NoError(t, simplesqlDb.Update(ctx, db, "volume", key, fields))
+		require.NoError(t, memoryTable.Update(ctx, key, fields))
+
+		var row VolumeRow
+		require.NoError(t, simplesqlDb.Get(ctx, "volume", key, &row))
+		memoryRow, err := memoryTable.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, "carol", row.CreatedBy)
+		require.Equal(t, int64(4), row.Limits.CPU)
+		require.Equal(t, row, memoryRow)
+
+		filters := VolumeTableOwnerSelectFilters{VolumeOwnerFilters{CreatedByEq: StringPtr("carol")}}
+		var rows []VolumeRow
+		require.NoError(t, simplesqlDb.List(ctx, "volume", filters, &rows))
+		memoryRows, err := memoryTable.List(ctx, filters)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, rows, memoryRows)
+	})
+
+	t.Run("Collisions", func(t *testing.T) {
+		type collidingRow struct {
+			AuditColumns
+			CreatedBy string `db:"created_by"`
+		}
+		err := simplesqlDb.Insert(ctx, db, "volume", collidingRow{})
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+		require.ErrorContains(t, err, "fields CreatedBy and CreatedBy of simplesql_test.collidingRow map to the same column created_by")
+		// The error is cached with the fields of the type.
+		var rows []collidingRow
+		err = simplesqlDb.List(ctx, "volume", VolumeTableSelectFilters{}, &rows)
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+
+		_, err = simplesql.NewMemoryTable[collidingRow]("volume").List(ctx, VolumeTableSelectFilters{})
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+	})
+}
@@ -3,16 +3,26 @@ package simplesql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+var ErrEmptyKey = errors.New("key sets no column")
+
 type Database struct {
 	DB         *sqlx.DB
 	errHandler ErrHandler
+	tracker    *changeTracker
+	tables     *tableRegistry
+	clock      Clock
+
+	defaultTimeout time.Duration
 }
 
 type Option func(*Database)
@@ -27,6 +37,8 @@ func NewDatabase(db *sqlx.DB, opts ...Option) Database {
 	d := Database{
 		DB:         db,
 		errHandler: defaultErrHandler,
+		tables:     newTableRegistry(),
+		clock:      systemClock{},
 	}
 	for _, opt := range opts {
 		opt(&d)
@@ -38,8 +50,51 @@ func NewDatabase(db *sqlx.DB, opts ...Option) Database {
 func (d *Database) Insert(
 	ctx context.Context, execer sqlx.ExecerContext, tableName string, row interface{},
 ) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
 	// Deduce the column names and placeholders from the struct tags
-	columnNames, placeholders := getColumnNamesAndPlaceholders(row)
+	columnNames, placeholders, err := getColumnNamesAndPlaceholders(row)
+	if err != nil {
+		return err
+	}
+
+	params, err := structParams(row)
+	if err != nil {
+		return err
+	}
+	now := d.clock.Now()
+	rowType := reflect.Indirect(reflect.ValueOf(row)).Type()
+	createFields, err := autoFields(rowType, autoCreateTime)
+	if err != nil {
+		return err
+	}
+	updateFields, err := autoFields(rowType, autoUpdateTime)
+	if err != nil {
+		return err
+	}
+	for _, field := range append(createFields, updateFields...) {
+		value, err := timestampValue(now, field.Type)
+		if err != nil {
+			return err
+		}
+		params[field.Tag.Get("db")] = normalizeValue(value)
+	}
+
+	// Rows of tenant scoped tables are inserted for the tenant of the context
+	tenantColumn, tenantID, scoped, err := d.tenantScope(ctx, tableName, row)
+	if err != nil {
+		return err
+	}
+	if scoped {
+		if value, ok := params[tenantColumn]; !ok {
+			columnNames += ", " + tenantColumn
+			placeholders += ", :" + tenantColumn
+		} else if value != nil && value != "" && value != tenantID {
+			return fmt.Errorf("row of tenant '%v' inserted for tenant '%s': %w", value, tenantID, ErrTenantMismatch)
+		}
+		params[tenantColumn] = tenantID
+	}
 
 	// Build the final query string
 	query := fmt.Sprintf(`
@@ -48,56 +103,60 @@ func (d *Database) Insert(
 		VALUES (%s)
 	`, tableName, columnNames, placeholders)
 
+	if d.isTracked(tableName) {
+		return d.withChangeTx(ctx, execer, func(tx changeExecer) error {
+			if _, err := d.bindAndExec(ctx, tx, query, params); err != nil {
+				return d.handleErr(tableName, err)
+			}
+			return d.trackInsert(ctx, tx, tableName, params)
+		})
+	}
+
 	// d.logger.Debug("InsertRow", "query", strings.ReplaceAll(query, "\n\t\t", " "))
 	// Execute the query
-	_, err := d.bindAndExec(ctx, execer, query, row)
-	return d.errHandler(err)
+	_, err = d.bindAndExec(ctx, execer, query, params)
+	return d.handleErr(tableName, err)
 }
 
 func (d *Database) Get(
 	ctx context.Context, tableName string, key interface{}, row interface{},
 ) error {
-	// Deduce the column names for the SELECT statement
-	columnNames, _ := getColumnNamesAndPlaceholders(row)
-	query := fmt.Sprintf(`SELECT %s FROM %s WHERE 1=1`, columnNames, tableName)
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
 
-	// Prepare the parameters for the WHERE clause
-	params := []interface{}{}
-	keyValue := reflect.ValueOf(key)
-	if keyValue.Kind() == reflect.Ptr {
-		keyValue = keyValue.Elem()
+	// Deduce the column names for the SELECT statement
+	columnNames, _, err := getColumnNamesAndPlaceholders(row)
+	if err != nil {
+		return err
 	}
-	keyType := keyValue.Type()
-
-	// Iterate over the struct fields to build the WHERE clause
-	for i := 0; i < keyType.NumField(); i++ {
-		field := keyType.Field(i)
-		fieldValue := keyValue.Field(i)
-
-		// Check if the field is a pointer and skip if nil
-		if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
-			continue
-		}
-
-		// Use the "db" struct tag if present, otherwise default to field name
-		columnName := field.Tag.Get("db")
-		if columnName == "" {
-			columnName = field.Name
-		}
+	query := fmt.Sprintf(`SELECT %s%s FROM %s WHERE 1=1`, d.selectHint(ctx), columnNames, tableName)
 
-		// Add condition to query and append field value to params
-		query += fmt.Sprintf(" AND %s = ?", columnName)
-		params = append(params, fieldValue.Interface())
+	// Prepare the WHERE clause and its parameters. A key setting no column would read any row.
+	where, params, err := keyWhere(key)
+	if err != nil {
+		return err
+	}
+	if where == "" {
+		return fmt.Errorf("refusing to get a row of table '%s': %w", tableName, ErrEmptyKey)
 	}
+	tenantWhere, tenantParams, err := d.tenantWhere(ctx, tableName, key, row)
+	if err != nil {
+		return err
+	}
+	query += where + tenantWhere
+	params = append(params, tenantParams...)
 
 	// Execute the query
-	err := d.DB.GetContext(ctx, row, query, params...)
-	return d.errHandler(err)
+	err = d.getRow(ctx, row, query, params...)
+	return d.handleErr(tableName, err)
 }
 
 func (d *Database) Update(
 	ctx context.Context, execer sqlx.ExecerContext, tableName string, key interface{}, fields interface{},
 ) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
 	version := uint64(0)
 	versionSet := true
 	versionField := reflect.ValueOf(key).FieldByName("Version")
@@ -108,7 +167,6 @@ func (d *Database) Update(
 	}
 
 	query := ""
-	var updates []string
 	params := map[string]interface{}{}
 
 	if versionSet {
@@ -124,32 +182,18 @@ func (d *Database) Update(
 		query = fmt.Sprintf(`UPDATE %s SET `, tableName)
 	}
 
-	// Use reflection to iterate over the fields and extract db tags and values
-	v := reflect.ValueOf(fields)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
+	updates, updateParams, err := d.updateAssignments(tableName, fields)
+	if err != nil {
+		return err
 	}
-	t := v.Type()
-
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		fieldType := t.Field(i)
-		dbTag := fieldType.Tag.Get("db")
-		attributeTag := fmt.Sprintf("update_%s", dbTag)
-
-		// Check if the field is nil (for pointers), if not, add to updates
-		if field.Kind() == reflect.Ptr && !field.IsNil() {
-			updates = append(updates, fmt.Sprintf("%s = :%s", dbTag, attributeTag))
-			params[attributeTag] = field.Elem().Interface() // Dereference pointer and add value
-		}
+	for name, value := range updateParams {
+		params[name] = value
 	}
 
-	if len(updates) > 0 {
-		if versionSet {
-			query += ", " + strings.Join(updates, ", ")
-		} else {
-			query += strings.Join(updates, ", ")
-		}
+	if versionSet {
+		query += ", " + strings.Join(updates, ", ")
+	} else {
+		query += strings.Join(updates, ", ")
 	}
 
 	query += " WHERE 1=1"
@@ -158,81 +202,114 @@ func (d *Database) Update(
 	if keyValue.Kind() == reflect.Ptr {
 		keyValue = keyValue.Elem()
 	}
-	keyType := keyValue.Type()
+	keyFields, err := dbFields(keyValue.Type())
+	if err != nil {
+		return err
+	}
 
-	for i := 0; i < keyType.NumField(); i++ {
-		field := keyType.Field(i)
-		fieldValue := keyValue.Field(i)
+	for _, field := range keyFields {
+		fieldValue := keyValue.FieldByIndex(field.Index)
 
-		// Check if the field is a pointer and skip if nil
-		if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
+		// The tenant condition is added from the context
+		if isTenantField(field) {
 			continue
 		}
 
-		// Use the "db" struct tag if present, otherwise default to field name
 		columnName := field.Tag.Get("db")
-		if columnName == "" {
-			columnName = field.Name
-		}
 
-		// Add condition to query and append field value to params
-		query += fmt.Sprintf(" AND %s = :%s", columnName, columnName)
-		params[columnName] = fieldValue.Interface()
+		// Add condition to query and append field value to params.
+		// Nil pointers and unset Nullables are skipped.
+		condition, conditionParams, ok := keyCondition(columnName, "=", ":"+columnName, fieldValue)
+		if !ok {
+			continue
+		}
+		query += " AND " + condition
+		if len(conditionParams) > 0 {
+			params[columnName] = conditionParams[0]
+		}
 	}
 
-	res, err := d.bindAndExec(ctx, execer, query, params)
+	tenantColumn, tenantID, scoped, err := d.tenantScope(ctx, tableName, key, fields)
 	if err != nil {
-		return d.errHandler(err)
+		return err
+	}
+	if scoped {
+		query += fmt.Sprintf(" AND %s = :tenant_scope", tenantColumn)
+		params["tenant_scope"] = tenantID
+	}
+
+	update := func(execer sqlx.ExecerContext) error {
+		res, err := d.bindAndExec(ctx, execer, query, params)
+		if err != nil {
+			return d.handleErr(tableName, err)
+		}
+		return d.checkOptimisticLock(tableName, res, versionSet)
 	}
-	return d.checkOptimisticLock(res)
+	if d.isTracked(tableName) {
+		return d.withChangeTx(ctx, execer, func(tx changeExecer) error {
+			where, whereParams, err := keyWhere(key)
+			if err != nil {
+				return err
+			}
+			if scoped {
+				where += fmt.Sprintf(" AND %s = ?", tenantColumn)
+				whereParams = append(whereParams, tenantID)
+			}
+			return d.trackMutation(ctx, tx, tableName, OperationUpdate, where, whereParams, func() error {
+				return update(tx)
+			})
+		})
+	}
+	return update(execer)
 }
 
 func (d *Database) Delete(
-	ctx context.Context, tableName string, key interface{},
+	ctx context.Context, execer sqlx.ExecerContext, tableName string, key interface{},
 ) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
 	query := fmt.Sprintf(`DELETE FROM %s WHERE 1=1`, tableName)
 
-	// Prepare the parameters for the WHERE clause
-	params := []interface{}{}
-	keyValue := reflect.ValueOf(key)
-	if keyValue.Kind() == reflect.Ptr {
-		keyValue = keyValue.Elem()
+	// Prepare the WHERE clause and its parameters
+	where, params, err := keyWhere(key)
+	if err != nil {
+		return err
 	}
-	keyType := keyValue.Type()
-
-	// Iterate over the struct fields to build the WHERE clause
-	for i := 0; i < keyType.NumField(); i++ {
-		field := keyType.Field(i)
-		fieldValue := keyValue.Field(i)
-
-		// Check if the field is a pointer and skip if nil
-		if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
-			continue
-		}
-
-		// Use the "db" struct tag if present, otherwise default to field name
-		columnName := field.Tag.Get("db")
-		if columnName == "" {
-			columnName = field.Name
-		}
-
-		// Add condition to query and append field value to params
-		query += fmt.Sprintf(" AND %s = ?", columnName)
-		params = append(params, fieldValue.Interface())
+	tenantWhere, tenantParams, err := d.tenantWhere(ctx, tableName, key)
+	if err != nil {
+		return err
+	}
+	where += tenantWhere
+	params = append(params, tenantParams...)
+	query += where
+
+	if d.isTracked(tableName) {
+		return d.withChangeTx(ctx, execer, func(tx changeExecer) error {
+			return d.trackMutation(ctx, tx, tableName, OperationDelete, where, params, func() error {
+				_, err := tx.ExecContext(ctx, query, params...)
+				return d.handleErr(tableName, err)
+			})
+		})
 	}
 
 	// Execute the query
-	_, err := d.DB.ExecContext(ctx, query, params...)
-	return d.errHandler(err)
+	_, err = execer.ExecContext(ctx, query, params...)
+	return d.handleErr(tableName, err)
 }
 
 func (d *Database) List(
 	ctx context.Context, tableName string, filters interface{}, result interface{},
 ) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
 	// Deduce the column names and placeholders from the struct tags
-	columnNames, _ := getColumnNamesAndPlaceholders(result)
-	query := fmt.Sprintf(`SELECT %s FROM %s WHERE 1=1`, columnNames, tableName)
-	params := map[string]interface{}{}
+	columnNames, _, err := getColumnNamesAndPlaceholders(result)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`SELECT %s%s FROM %s WHERE 1=1`, d.selectHint(ctx), columnNames, tableName)
 
 	// Use reflection to iterate over the filters struct and build query conditions
 	v := reflect.ValueOf(filters)
@@ -240,14 +317,161 @@ func (d *Database) List(
 		v = v.Elem()
 	}
 	t := v.Type()
+	where, params, err := filterWhere(v)
+	if err != nil {
+		return err
+	}
+	query += where
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		fieldType := t.Field(i)
+	tenantColumn, tenantID, scoped, err := d.tenantScope(ctx, tableName, filters, result)
+	if err != nil {
+		return err
+	}
+	if scoped {
+		query += fmt.Sprintf(" AND %s = :tenant_scope", tenantColumn)
+		params["tenant_scope"] = tenantID
+	}
+
+	// Handle ordering if it's provided
+	order, err := listOrder(v, rowElemType(result))
+	if err != nil {
+		return err
+	}
+	if len(order) > 0 {
+		terms := make([]string, len(order))
+		for i, term := range order {
+			terms[i] = term.String()
+		}
+		query += " ORDER BY " + strings.Join(terms, ", ")
+	}
+
+	// Handle limit if it's provided
+	_, ok := t.FieldByName("Limit")
+	if ok && v.FieldByName("Limit").Uint() > 0 {
+		query += " LIMIT :limit"
+		params["limit"] = v.FieldByName("Limit").Uint()
+	}
+
+	// Prepare the final query with expanded parameters and IN clauses
+	query, args, err := expandNamed(query, params)
+	if err != nil {
+		return err
+	}
+
+	// Rebind for the current SQL driver
+	query = d.DB.Rebind(query)
+
+	// Execute the query
+	err = d.selectRows(ctx, result, query, args...)
+	if err != nil {
+		return d.handleErr(tableName, err)
+	}
+
+	return nil
+}
+
+// updateAssignments returns the assignments of the set fields of an update fields struct of the
+// table, along with their named parameters. The automatic update timestamps of the row struct
+// registered for the table, and those declared by the fields struct, are set to the time of the
+// clock, whatever the fields struct sets them to. Fields structs setting no field fail with
+// ErrInternal.
+func (d *Database) updateAssignments(tableName string, fields interface{}) ([]string, map[string]interface{}, error) {
+	var updates []string
+	params := map[string]interface{}{}
+
+	// Use reflection to iterate over the fields and extract db tags and values
+	v := reflect.ValueOf(fields)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	fieldTypes, err := dbFields(v.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var stamped []reflect.StructField
+	if rowType, ok := d.rowType(tableName); ok {
+		if stamped, err = autoFields(rowType, autoUpdateTime); err != nil {
+			return nil, nil, err
+		}
+	}
+	isStamped := func(column string) bool {
+		return slices.ContainsFunc(stamped, func(f reflect.StructField) bool { return f.Tag.Get("db") == column })
+	}
+	for _, fieldType := range fieldTypes {
+		if hasORMOption(fieldType, autoUpdateTime) && !isStamped(fieldType.Tag.Get("db")) {
+			stamped = append(stamped, fieldType)
+		}
+	}
+	for _, fieldType := range fieldTypes {
+		field := v.FieldByIndex(fieldType.Index)
 		dbTag := fieldType.Tag.Get("db")
+		attributeTag := fmt.Sprintf("update_%s", dbTag)
+
+		// The tenant of a row is never updated, and the timestamps are stamped
+		if isTenantField(fieldType) || isStamped(dbTag) {
+			continue
+		}
+
+		// A set Nullable updates the column, possibly to NULL
+		if n, ok := asNullable(field); ok {
+			if n.isSet() {
+				updates = append(updates, fmt.Sprintf("%s = :%s", dbTag, attributeTag))
+				params[attributeTag] = normalizeValue(field)
+			}
+			continue
+		}
+
+		// Check if the field is nil (for pointers), if not, add to updates
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			value := normalizeValue(field) // Dereference pointer unless it is the driver.Valuer
+			if isJSONField(fieldType) {
+				var err error
+				if value, err = columnValue(fieldType, field.Elem()); err != nil {
+					return nil, nil, err
+				}
+			}
+			updates = append(updates, fmt.Sprintf("%s = :%s", dbTag, attributeTag))
+			params[attributeTag] = value
+		}
+	}
+	// The stamped timestamps alone are not an update.
+	if len(updates) == 0 {
+		return nil, nil, fmt.Errorf("no fields to update in table '%s': %w", tableName, ErrInternal)
+	}
 
-		if dbTag == "" {
-			continue // Skip fields with no db tag
+	now := d.clock.Now()
+	var stamps []string
+	for _, field := range stamped {
+		dbTag := field.Tag.Get("db")
+		attributeTag := fmt.Sprintf("update_%s", dbTag)
+		value, err := timestampValue(now, field.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		stamps = append(stamps, fmt.Sprintf("%s = :%s", dbTag, attributeTag))
+		params[attributeTag] = normalizeValue(value)
+	}
+
+	return append(stamps, updates...), params, nil
+}
+
+// filterWhere returns the conditions of a List filters struct, each prefixed with AND, along
+// with their named parameters. Fields without a db tag, such as OrderBy, and the tenant are skipped.
+func filterWhere(v reflect.Value) (string, map[string]interface{}, error) {
+	where := ""
+	params := map[string]interface{}{}
+	fieldTypes, err := dbFields(v.Type())
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, fieldType := range fieldTypes {
+		field := v.FieldByIndex(fieldType.Index)
+		dbTag := fieldType.Tag.Get("db")
+
+		if isTenantField(fieldType) {
+			continue // Skip the tenant, added from the context
 		}
 
 		// Split the tag to handle operations (e.g., eq, lt, gt)
@@ -259,63 +483,47 @@ func (d *Database) List(
 			operation = tagParts[1] // Extract the operation from the tag
 		}
 
-		// Handle slice types (IN and NOT IN clauses)
-		if field.Kind() == reflect.Slice && field.Len() > 0 {
+		// Handle slice types (IN and NOT IN clauses). Slices which are driver.Valuers, such as
+		// json.RawMessage, are single values.
+		if field.Kind() == reflect.Slice && field.Len() > 0 && !fieldType.Type.Implements(valuerType) {
 			if strings.Contains(operation, "not_in") {
-				query += fmt.Sprintf(" AND %s NOT IN (:%s)", columnName, fieldType.Name)
+				where += fmt.Sprintf(" AND %s NOT IN (:%s)", columnName, fieldType.Name)
 			} else {
-				query += fmt.Sprintf(" AND %s IN (:%s)", columnName, fieldType.Name)
+				where += fmt.Sprintf(" AND %s IN (:%s)", columnName, fieldType.Name)
 			}
-			params[fieldType.Name] = field.Interface()
+			params[fieldType.Name] = normalizeSlice(field)
 
 		} else if field.IsValid() && !isEmptyValue(field) {
 			// Handle different operations
+			operator := ""
 			switch operation {
 			case "lt":
-				query += fmt.Sprintf(" AND %s < :%s", columnName, fieldType.Name)
+				operator = "<"
 			case "gt":
-				query += fmt.Sprintf(" AND %s > :%s", columnName, fieldType.Name)
+				operator = ">"
 			case "lte":
-				query += fmt.Sprintf(" AND %s <= :%s", columnName, fieldType.Name)
+				operator = "<="
 			case "gte":
-				query += fmt.Sprintf(" AND %s >= :%s", columnName, fieldType.Name)
+				operator = ">="
 			case "eq":
-				query += fmt.Sprintf(" AND %s = :%s", columnName, fieldType.Name)
-
+				operator = "="
+			}
+			if operator == "" {
+				continue
+			}
+			// A Nullable set to NULL matches with IS NULL and takes no parameter
+			condition, conditionParams, ok := keyCondition(columnName, operator, ":"+fieldType.Name, field)
+			if !ok {
+				continue
+			}
+			where += " AND " + condition
+			if len(conditionParams) > 0 {
+				params[fieldType.Name] = conditionParams[0]
 			}
-			params[fieldType.Name] = field.Interface()
 		}
 	}
 
-	// Handle limit if it's provided
-	_, ok := t.FieldByName("Limit")
-	if ok && v.FieldByName("Limit").Uint() > 0 {
-		query += " LIMIT :limit"
-		params["limit"] = v.FieldByName("Limit").Uint()
-	}
-
-	// Prepare the final query with expanded parameters
-	query, args, err := sqlx.Named(query, params)
-	if err != nil {
-		return fmt.Errorf("failed to expand IN clause: %s, %w", err.Error(), ErrInternal)
-	}
-
-	// Expand IN clause
-	query, args, err = sqlx.In(query, args...)
-	if err != nil {
-		return fmt.Errorf("failed to expand IN clause: %s, %w", err.Error(), ErrInternal)
-	}
-
-	// Rebind for the current SQL driver
-	query = d.DB.Rebind(query)
-
-	// Execute the query
-	err = d.DB.SelectContext(ctx, result, query, args...)
-	if err != nil {
-		return d.errHandler(err)
-	}
-
-	return nil
+	return where, params, nil
 }
 
 // Helper function to check if a field is empty
@@ -335,10 +543,49 @@ func isEmptyValue(v reflect.Value) bool {
 		return v.Float() == 0
 	case reflect.Interface, reflect.Ptr:
 		return v.IsNil()
+	case reflect.Struct:
+		return v.IsZero()
 	}
 	return false
 }
 
+// keyWhere returns the conditions matching the fields of a key struct, each prefixed with AND,
+// along with their positional parameters.
+func keyWhere(key interface{}) (string, []interface{}, error) {
+	where := ""
+	params := []interface{}{}
+	keyValue := reflect.ValueOf(key)
+	if keyValue.Kind() == reflect.Ptr {
+		keyValue = keyValue.Elem()
+	}
+	fields, err := dbFields(keyValue.Type())
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Iterate over the struct fields to build the WHERE clause
+	for _, field := range fields {
+		fieldValue := keyValue.FieldByIndex(field.Index)
+
+		// The tenant condition is added from the context
+		if isTenantField(field) {
+			continue
+		}
+
+		columnName := field.Tag.Get("db")
+
+		// Add condition to query and append field value to params.
+		// Nil pointers and unset Nullables are skipped.
+		condition, conditionParams, ok := keyCondition(columnName, "=", "?", fieldValue)
+		if !ok {
+			continue
+		}
+		where += " AND " + condition
+		params = append(params, conditionParams...)
+	}
+	return where, params, nil
+}
+
 func (d *Database) bindAndExec(
 	ctx context.Context, execer sqlx.ExecerContext, query string, row interface{}) (sql.Result, error) {
 	query, args, err := sqlx.Named(query, row)
@@ -350,19 +597,25 @@ func (d *Database) bindAndExec(
 	return execer.ExecContext(ctx, query, args...)
 }
 
-func (d *Database) checkOptimisticLock(res sql.Result) error {
+// checkOptimisticLock returns an error if the update affected no row: a version conflict if the
+// key holds the version, or KindNoRowsAffected otherwise.
+func (d *Database) checkOptimisticLock(tableName string, res sql.Result, versionSet bool) error {
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return d.errHandler(err)
+		return d.handleErr(tableName, err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("no rows affected: %w", ErrInsertConflict)
+		kind := KindNoRowsAffected
+		if versionSet {
+			kind = KindVersionConflict
+		}
+		return &Error{Kind: kind, Table: tableName, Err: errors.New("no rows affected")}
 	}
 	return nil
 }
 
 // Helper function to get column names and placeholders from struct tags
-func getColumnNamesAndPlaceholders(row interface{}) (string, string) {
+func getColumnNamesAndPlaceholders(row interface{}) (string, string, error) {
 	v := reflect.ValueOf(row)
 
 	if v.Kind() == reflect.Ptr {
@@ -381,18 +634,18 @@ func getColumnNamesAndPlaceholders(row interface{}) (string, string) {
 		}
 	}
 
-	t := v.Type()
+	fields, err := dbFields(v.Type())
+	if err != nil {
+		return "", "", err
+	}
 	var columnNames []string
 	var placeholders []string
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
+	for _, field := range fields {
 		dbTag := field.Tag.Get("db")
-		if dbTag != "" {
-			columnNames = append(columnNames, dbTag)
-			placeholders = append(placeholders, ":"+dbTag)
-		}
+		columnNames = append(columnNames, dbTag)
+		placeholders = append(placeholders, ":"+dbTag)
 	}
 
-	return strings.Join(columnNames, ", "), strings.Join(placeholders, ", ")
+	return strings.Join(columnNames, ", "), strings.Join(placeholders, ", "), nil
 }
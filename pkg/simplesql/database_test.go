@@ -37,14 +37,14 @@ var clusterTableMigrations = []simplesql.Migration{
 }
 
 type ClusterRow struct {
-	ID            string `db:"id" orm:"op=get key:primary"`
-	Version       uint64 `db:"version" orm:"op_lock:true"`
+	ID            string `db:"id" orm:"op=get key=primary"`
+	Version       uint64 `db:"version" orm:"op_lock=true"`
 	CreatedAt     int64  `db:"created_at"`
 	LastUpdatedAt int64  `db:"last_updated_at" orm:"op=update"`
-	DeletedAt     int64  `db:"deleted_at" orm:"soft_delete:true"`
+	DeletedAt     int64  `db:"deleted_at" orm:"soft_delete=true"`
 
 	Name             string `db:"name" orm:"op=get filter=In"`
-	ClusterManagerID string `db:"cluster_manager_id" orm:"key:primary filter=In"`
+	ClusterManagerID string `db:"cluster_manager_id" orm:"key=primary filter=In"`
 	State            string `db:"state" orm:"op=update filter=In,NotIn"`
 	Message          string `db:"message" orm:"op=update"`
 }
@@ -115,7 +115,7 @@ func (s *ClusterTable) Update(
 }
 
 func (s *ClusterTable) Delete(ctx context.Context, execer sqlx.ExecerContext, updateKey ClusterTableUpdateKey) error {
-	return s.Database.Delete(ctx, s.tableName, updateKey)
+	return s.Database.Delete(ctx, execer, s.tableName, updateKey)
 }
 
 func (s *ClusterTable) List(ctx context.Context, filters ClusterTableSelectFilters) ([]ClusterRow, error) {
@@ -165,6 +165,14 @@ func TestSimpleSqlDB(t *testing.T) {
 		require.Equal(t, int64(0), cluster.DeletedAt)
 	})
 
+	t.Run("Get without key", func(t *testing.T) {
+		var cluster ClusterRow
+		err := simplesqlDb.Get(context.Background(), "cluster", struct {
+			ID *string `db:"id"`
+		}{}, &cluster)
+		require.ErrorIs(t, err, simplesql.ErrEmptyKey)
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		err := clusterTable.Update(
 			context.Background(), db,
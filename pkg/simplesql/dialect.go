@@ -0,0 +1,23 @@
+package simplesql
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// Dialect identifies the SQL flavour spoken by the underlying database.
+type Dialect string
+
+const (
+	DialectMySQL  Dialect = "mysql"
+	DialectSQLite Dialect = "sqlite3"
+)
+
+// dialectOf deduces the dialect from the driver the connection was opened with.
+func dialectOf(db *sqlx.DB) Dialect {
+	return Dialect(db.DriverName())
+}
+
+// Dialect returns the dialect of the underlying database.
+func (d *Database) Dialect() Dialect {
+	return dialectOf(d.DB)
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/mattn/go-sqlite3"
@@ -12,72 +14,266 @@ import (
 type ErrHandler func(error) error
 
 var (
-	ErrInsertConflict = errors.New("insert conflict")
-	ErrRecordNotFound = errors.New("record not found")
-	ErrInvalidVersion = errors.New("invalid version or version not provided")
-	ErrInternal       = errors.New("internal error")
+	ErrInsertConflict  = errors.New("insert conflict")
+	ErrRecordNotFound  = errors.New("record not found")
+	ErrInvalidVersion  = errors.New("invalid version or version not provided")
+	ErrInternal        = errors.New("internal error")
+	ErrSchemaMismatch  = errors.New("schema does not match row struct")
+	ErrMissingTenant   = errors.New("tenant missing from context")
+	ErrTenantMismatch  = errors.New("row belongs to another tenant")
+	ErrForeignKey      = errors.New("foreign key violation")
+	ErrNotNull         = errors.New("not null violation")
+	ErrCheck           = errors.New("check constraint violation")
+	ErrVersionConflict = errors.New("version conflict")
+	ErrTimeout         = errors.New("timeout")
 )
 
-// MySQLErrHandler processes MySQL errors and returns a custom StorageError
+// ErrorKind classifies the errors returned by simplesql.
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindDuplicate
+	KindForeignKey
+	KindNotNull
+	KindCheck
+	KindNotFound
+	KindVersionConflict
+	KindTimeout
+	// KindNoRowsAffected is an Update matching no row, without a version in its key.
+	KindNoRowsAffected
+	// KindConstraint is the violation of another constraint, e.g. raised by a trigger.
+	KindConstraint
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindDuplicate:
+		return "duplicate"
+	case KindForeignKey:
+		return "foreign key"
+	case KindNotNull:
+		return "not null"
+	case KindCheck:
+		return "check"
+	case KindNotFound:
+		return "not found"
+	case KindVersionConflict:
+		return "version conflict"
+	case KindTimeout:
+		return "timeout"
+	case KindNoRowsAffected:
+		return "no rows affected"
+	case KindConstraint:
+		return "constraint"
+	}
+	return "internal"
+}
+
+// sentinels returns the sentinel errors matching the kind with errors.Is. Constraint violations,
+// version conflicts and Updates affecting no row also match ErrInsertConflict, which they were
+// reported as before the kinds.
+func (k ErrorKind) sentinels() []error {
+	switch k {
+	case KindDuplicate:
+		return []error{ErrInsertConflict}
+	case KindForeignKey:
+		return []error{ErrForeignKey, ErrInsertConflict}
+	case KindNotNull:
+		return []error{ErrNotNull, ErrInsertConflict}
+	case KindCheck:
+		return []error{ErrCheck, ErrInsertConflict}
+	case KindNotFound:
+		return []error{ErrRecordNotFound}
+	case KindVersionConflict:
+		return []error{ErrVersionConflict, ErrInsertConflict}
+	case KindTimeout:
+		return []error{ErrTimeout}
+	case KindNoRowsAffected:
+		return []error{ErrRecordNotFound, ErrInsertConflict}
+	case KindConstraint:
+		return []error{ErrInsertConflict}
+	}
+	return []error{ErrInternal}
+}
+
+// Error is the error returned by the err handlers and by simplesql operations. It matches the
+// sentinel errors of its Kind with errors.Is, and unwraps to the driver error.
+type Error struct {
+	Kind ErrorKind
+	// Table is the table of the failed statement, when known.
+	Table string
+	// Constraint is the name of the violated constraint or index, when reported by the driver.
+	Constraint string
+	// Columns are the columns of the violated constraint, when reported by the driver.
+	Columns []string
+	// Code is the driver error code: the MySQL error number or the SQLite extended error code.
+	Code int
+	// Err is the underlying driver error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.sentinels()[0].Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Err.Error(), e.Kind.sentinels()[0].Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	for _, sentinel := range e.Kind.sentinels() {
+		if target == sentinel {
+			return true
+		}
+	}
+	return false
+}
+
+// handleErr converts err with the err handler and records the table of the failed statement
+// in a *Error which lacks it. Errors caused by the context are timeouts whatever the err handler.
+func (d *Database) handleErr(tableName string, err error) error {
+	if isContextErr(err) {
+		return &Error{Kind: KindTimeout, Table: tableName, Err: err}
+	}
+	err = d.errHandler(err)
+	var e *Error
+	if errors.As(err, &e) && e.Table == "" {
+		e.Table = tableName
+	}
+	return err
+}
+
+var (
+	mysqlDuplicateRe  = regexp.MustCompile("for key '([^']+)'")
+	mysqlForeignKeyRe = regexp.MustCompile("foreign key constraint fails \\(`[^`]*`\\.`([^`]+)`, CONSTRAINT `([^`]+)`")
+	mysqlColumnRe     = regexp.MustCompile("(?:Column|Field) '([^']+)'")
+	mysqlCheckRe      = regexp.MustCompile("Check constraint '([^']+)'")
+)
+
+// MySQLErrHandler processes MySQL errors and returns a *Error
 func MySQLErrHandler(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	// Check if the error is a SQLite error
 	// Record not found
 	if err == sql.ErrNoRows {
-		return ErrRecordNotFound
+		return &Error{Kind: KindNotFound, Err: err}
+	}
+	// Deadline exceeded or canceled
+	if isContextErr(err) {
+		return &Error{Kind: KindTimeout, Err: err}
 	}
 	// Check if the error is a MySQL error
 	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+		e := &Error{Kind: KindInternal, Code: int(mysqlErr.Number), Err: mysqlErr}
 		switch mysqlErr.Number {
 		case 1062:
-			// Duplicate entry (unique constraint violation)
-			fallthrough
-		case 1451:
-			// Foreign key constraint violation (cannot delete/update parent row)
-			fallthrough
-		case 1452:
-			// Foreign key constraint violation (cannot add/update child row)
-			return fmt.Errorf("%s: %w", mysqlErr.Error(), ErrInsertConflict)
-		default:
-			// For all other MySQL errors
-			return fmt.Errorf("%s: %w", mysqlErr.Error(), ErrInternal)
+			// Duplicate entry (unique constraint violation). MySQL 8 qualifies the key with the table.
+			e.Kind = KindDuplicate
+			if m := mysqlDuplicateRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+				if table, key, ok := strings.Cut(m[1], "."); ok {
+					e.Table, e.Constraint = table, key
+				} else {
+					e.Constraint = m[1]
+				}
+			}
+		case 1451, 1452:
+			// Foreign key constraint violation (cannot delete/update parent row, cannot add/update child row)
+			e.Kind = KindForeignKey
+			if m := mysqlForeignKeyRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+				e.Table, e.Constraint = m[1], m[2]
+			}
+		case 1048, 1364:
+			// Column cannot be null, field doesn't have a default value
+			e.Kind = KindNotNull
+			if m := mysqlColumnRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+				e.Columns = []string{m[1]}
+			}
+		case 3819:
+			// Check constraint is violated
+			e.Kind = KindCheck
+			if m := mysqlCheckRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+				e.Constraint = m[1]
+			}
+		case 1205, 3024:
+			// Lock wait timeout exceeded, maximum statement execution time exceeded
+			e.Kind = KindTimeout
 		}
+		return e
 	}
 	return err
 }
 
-// SQLiteErrHandler processes SQLite errors and returns a custom StorageError
+// SQLiteErrHandler processes SQLite errors and returns a *Error
 func SQLiteErrHandler(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	// Check if the error is a SQLite error
+	// Record not found
 	if err == sql.ErrNoRows {
-		// Record not found
-		return fmt.Errorf("%s: %w", err.Error(), ErrRecordNotFound)
+		return &Error{Kind: KindNotFound, Err: err}
+	}
+	// Deadline exceeded or canceled
+	if isContextErr(err) {
+		return &Error{Kind: KindTimeout, Err: err}
 	}
 
 	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		e := &Error{Kind: KindInternal, Code: int(sqliteErr.ExtendedCode), Err: sqliteErr}
 		switch sqliteErr.Code {
 		case sqlite3.ErrConstraint:
-			// Unique constraint violation
-			return fmt.Errorf("%s: %w", sqliteErr.Error(), ErrInsertConflict)
+			e.Kind = KindConstraint
+			switch sqliteErr.ExtendedCode {
+			case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+				e.Kind = KindDuplicate
+				e.Table, e.Columns = sqliteConstraintColumns(sqliteErr.Error())
+			case sqlite3.ErrConstraintForeignKey:
+				e.Kind = KindForeignKey
+			case sqlite3.ErrConstraintNotNull:
+				e.Kind = KindNotNull
+				e.Table, e.Columns = sqliteConstraintColumns(sqliteErr.Error())
+			case sqlite3.ErrConstraintCheck:
+				e.Kind = KindCheck
+				_, e.Constraint, _ = strings.Cut(sqliteErr.Error(), "constraint failed: ")
+			}
 		case sqlite3.ErrNotFound:
 			// Record not found
-			return fmt.Errorf("%s: %w", sqliteErr.Error(), ErrRecordNotFound)
-		default:
-			// For all other SQLite errors
-			return fmt.Errorf("%s: %w", sqliteErr.Error(), ErrInternal)
+			e.Kind = KindNotFound
+		case sqlite3.ErrBusy, sqlite3.ErrLocked, sqlite3.ErrInterrupt:
+			e.Kind = KindTimeout
 		}
+		return e
 	}
 
 	return err
 }
 
+// sqliteConstraintColumns parses the table and columns from a message such as
+// "UNIQUE constraint failed: cluster.name, cluster.deleted_at".
+func sqliteConstraintColumns(message string) (string, []string) {
+	_, list, ok := strings.Cut(message, "constraint failed: ")
+	if !ok {
+		return "", nil
+	}
+	table := ""
+	var columns []string
+	for _, qualified := range strings.Split(list, ", ") {
+		t, column, ok := strings.Cut(qualified, ".")
+		if !ok {
+			t, column = "", qualified
+		}
+		table = t
+		columns = append(columns, column)
+	}
+	return table, columns
+}
+
 func defaultErrHandler(err error) error {
 	return err
 }
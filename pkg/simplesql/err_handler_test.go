@@ -0,0 +1,243 @@
+package simplesql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+var teamTableMigrations = []simplesql.Migration{
+	{
+		Version: 1,
+		Up: `
+			CREATE TABLE team (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL UNIQUE
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS team;
+			`,
+	},
+	{
+		Version: 2,
+		Up: `
+			CREATE TABLE member (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				team_id VARCHAR(255) NOT NULL REFERENCES team (id),
+				version BIGINT NOT NULL,
+				age INTEGER,
+				CONSTRAINT adult CHECK (age >= 18)
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS member;
+			`,
+	},
+}
+
+type TeamRow struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
+
+type MemberRow struct {
+	ID      string  `db:"id"`
+	TeamID  *string `db:"team_id"`
+	Version uint64  `db:"version"`
+	Age     *int64  `db:"age"`
+}
+
+type MemberTableUpdateKey struct {
+	ID      string `db:"id"`
+	Version uint64 `db:"version"`
+}
+
+type MemberTableUpdateFields struct {
+	Age *int64 `db:"age"`
+}
+
+func TestSQLiteErrors(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+	err = simplesqlDb.ApplyMigrations(teamTableMigrations)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = simplesqlDb.Insert(ctx, db, "team", TeamRow{ID: "team0", Name: "storage"})
+	require.NoError(t, err)
+	err = simplesqlDb.Insert(ctx, db, "member", MemberRow{ID: "member0", TeamID: StringPtr("team0"), Version: 1})
+	require.NoError(t, err)
+
+	t.Run("Duplicate", func(t *testing.T) {
+		err := simplesqlDb.Insert(ctx, db, "team", TeamRow{ID: "team1", Name: "storage"})
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindDuplicate, e.Kind)
+		require.Equal(t, "team", e.Table)
+		require.Equal(t, []string{"name"}, e.Columns)
+		require.Equal(t, int(sqlite3.ErrConstraintUnique), e.Code)
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+		err = simplesqlDb.Insert(ctx, db, "team", TeamRow{ID: "team0", Name: "compute"})
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindDuplicate, e.Kind)
+		require.Equal(t, int(sqlite3.ErrConstraintPrimaryKey), e.Code)
+	})
+
+	t.Run("Foreign key", func(t *testing.T) {
+		err := simplesqlDb.Insert(ctx, db, "member", MemberRow{ID: "member1", TeamID: StringPtr("missing"), Version: 1})
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindForeignKey, e.Kind)
+		require.Equal(t, "member", e.Table)
+		require.Equal(t, int(sqlite3.ErrConstraintForeignKey), e.Code)
+		require.ErrorIs(t, err, simplesql.ErrForeignKey)
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+		err = simplesqlDb.Delete(ctx, db, "team", struct {
+			ID string `db:"id"`
+		}{ID: "team0"})
+		require.ErrorIs(t, err, simplesql.ErrForeignKey)
+	})
+
+	t.Run("Not null", func(t *testing.T) {
+		err := simplesqlDb.Insert(ctx, db, "member", MemberRow{ID: "member1", Version: 1})
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindNotNull, e.Kind)
+		require.Equal(t, "member", e.Table)
+		require.Equal(t, []string{"team_id"}, e.Columns)
+		require.ErrorIs(t, err, simplesql.ErrNotNull)
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		err := simplesqlDb.Update(ctx, db, "member", MemberTableUpdateKey{ID: "member0", Version: 1}, MemberTableUpdateFields{
+			Age: Int64Ptr(12),
+		})
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindCheck, e.Kind)
+		require.Equal(t, "adult", e.Constraint)
+		require.ErrorIs(t, err, simplesql.ErrCheck)
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+	})
+
+	t.Run("No rows affected", func(t *testing.T) {
+		err := simplesqlDb.Update(ctx, db, "team", struct {
+			ID string `db:"id"`
+		}{ID: "missing"}, struct {
+			Name *string `db:"name"`
+		}{Name: StringPtr("compute")})
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindNoRowsAffected, e.Kind)
+		require.Equal(t, "team", e.Table)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+	})
+
+	t.Run("Version conflict", func(t *testing.T) {
+		err := simplesqlDb.Update(ctx, db, "member", MemberTableUpdateKey{ID: "member0", Version: 7}, MemberTableUpdateFields{
+			Age: Int64Ptr(30),
+		})
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindVersionConflict, e.Kind)
+		require.Equal(t, "member", e.Table)
+		require.ErrorIs(t, err, simplesql.ErrVersionConflict)
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		var team TeamRow
+		err := simplesqlDb.Get(ctx, "team", struct {
+			ID string `db:"id"`
+		}{ID: "missing"}, &team)
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindNotFound, e.Kind)
+		require.Equal(t, "team", e.Table)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		require.NotErrorIs(t, err, simplesql.ErrInsertConflict)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestMySQLErrHandler(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		err      *mysql.MySQLError
+		expected simplesql.Error
+		sentinel error
+	}{
+		{
+			name:     "Duplicate",
+			err:      &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'storage' for key 'team.uniq_team_name'"},
+			expected: simplesql.Error{Kind: simplesql.KindDuplicate, Table: "team", Constraint: "uniq_team_name", Code: 1062},
+			sentinel: simplesql.ErrInsertConflict,
+		},
+		{
+			name:     "Parent row",
+			err:      &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails (`test_db`.`member`, CONSTRAINT `fk_member_team` FOREIGN KEY (`team_id`) REFERENCES `team` (`id`))"},
+			expected: simplesql.Error{Kind: simplesql.KindForeignKey, Table: "member", Constraint: "fk_member_team", Code: 1451},
+			sentinel: simplesql.ErrForeignKey,
+		},
+		{
+			name:     "Child row",
+			err:      &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`test_db`.`member`, CONSTRAINT `fk_member_team` FOREIGN KEY (`team_id`) REFERENCES `team` (`id`))"},
+			expected: simplesql.Error{Kind: simplesql.KindForeignKey, Table: "member", Constraint: "fk_member_team", Code: 1452},
+			sentinel: simplesql.ErrForeignKey,
+		},
+		{
+			name:     "Not null",
+			err:      &mysql.MySQLError{Number: 1048, Message: "Column 'team_id' cannot be null"},
+			expected: simplesql.Error{Kind: simplesql.KindNotNull, Columns: []string{"team_id"}, Code: 1048},
+			sentinel: simplesql.ErrNotNull,
+		},
+		{
+			name:     "Check",
+			err:      &mysql.MySQLError{Number: 3819, Message: "Check constraint 'adult' is violated."},
+			expected: simplesql.Error{Kind: simplesql.KindCheck, Constraint: "adult", Code: 3819},
+			sentinel: simplesql.ErrCheck,
+		},
+		{
+			name:     "Timeout",
+			err:      &mysql.MySQLError{Number: 3024, Message: "Query execution was interrupted, maximum statement execution time exceeded"},
+			expected: simplesql.Error{Kind: simplesql.KindTimeout, Code: 3024},
+			sentinel: simplesql.ErrTimeout,
+		},
+		{
+			name:     "Internal",
+			err:      &mysql.MySQLError{Number: 1146, Message: "Table 'test_db.team' doesn't exist"},
+			expected: simplesql.Error{Kind: simplesql.KindInternal, Code: 1146},
+			sentinel: simplesql.ErrInternal,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := simplesql.MySQLErrHandler(tc.err)
+			var e *simplesql.Error
+			require.ErrorAs(t, err, &e)
+			tc.expected.Err = tc.err
+			require.Equal(t, &tc.expected, e)
+			require.ErrorIs(t, err, tc.sentinel)
+
+			var mysqlErr *mysql.MySQLError
+			require.True(t, errors.As(err, &mysqlErr))
+		})
+	}
+
+	require.ErrorIs(t, simplesql.MySQLErrHandler(sql.ErrNoRows), simplesql.ErrRecordNotFound)
+}
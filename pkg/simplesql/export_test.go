@@ -0,0 +1,8 @@
+package simplesql
+
+import "context"
+
+// SelectHint exposes selectHint to the tests of the package.
+func (d *Database) SelectHint(ctx context.Context) string {
+	return d.selectHint(ctx)
+}
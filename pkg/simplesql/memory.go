@@ -0,0 +1,616 @@
+package simplesql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryTable is an in-memory table of rows of type T for tests. It takes the same key, fields
+// and filters structs as the operations of Database and behaves like them: keys and filters
+// match rows the same way, Update checks the version of op-locked rows, List honours OrderBy and
+// Limit, and rows are scoped to the tenant of the context. The primary key and the unique keys
+// are read from the orm tags of T, key=primary and unique=<name>, and enforced on Insert and
+// Update. Errors are *Error, as returned by the err handlers.
+//
+// Changes are applied immediately, there are no transactions. Other constraints, such as foreign
+// keys and NOT NULL, are not enforced.
+type MemoryTable[T any] struct {
+	tableName    string
+	fields       []reflect.StructField
+	columns      map[string][]int
+	primaryKey   []string
+	uniqueKeys   [][]string
+	tenantColumn string
+	clock        Clock
+	// err is the error of the fields of T, returned by every operation.
+	err error
+
+	mu   sync.RWMutex
+	rows []T
+}
+
+type memoryTableOptions struct {
+	clock Clock
+}
+
+// MemoryTableOption configures a MemoryTable.
+type MemoryTableOption func(*memoryTableOptions)
+
+// WithMemoryClock replaces the system clock of the automatic timestamps of a MemoryTable.
+func WithMemoryClock(clock Clock) MemoryTableOption {
+	return func(o *memoryTableOptions) {
+		o.clock = clock
+	}
+}
+
+func NewMemoryTable[T any](tableName string, opts ...MemoryTableOption) *MemoryTable[T] {
+	options := memoryTableOptions{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	m := &MemoryTable[T]{
+		tableName: tableName,
+		columns:   map[string][]int{},
+		clock:     options.clock,
+	}
+
+	m.fields, m.err = dbFields(reflect.TypeOf((*T)(nil)).Elem())
+	uniqueKeys := map[string][]string{}
+	var uniqueNames []string
+	for _, field := range m.fields {
+		column := field.Tag.Get("db")
+		m.columns[column] = field.Index
+		if isTenantField(field) {
+			m.tenantColumn = column
+		}
+		for _, option := range strings.Fields(field.Tag.Get("orm")) {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "key":
+				if slices.Contains(strings.Split(value, ","), "primary") {
+					m.primaryKey = append(m.primaryKey, column)
+				}
+			case "unique":
+				for _, name := range strings.Split(value, ",") {
+					if _, ok := uniqueKeys[name]; !ok {
+						uniqueNames = append(uniqueNames, name)
+					}
+					uniqueKeys[name] = append(uniqueKeys[name], column)
+				}
+			}
+		}
+	}
+	if len(m.primaryKey) > 0 {
+		// Rows of tenant scoped tables are keyed per tenant, like the generated migrations.
+		if m.tenantColumn != "" && !slices.Contains(m.primaryKey, m.tenantColumn) {
+			m.primaryKey = append([]string{m.tenantColumn}, m.primaryKey...)
+		}
+		m.uniqueKeys = append(m.uniqueKeys, m.primaryKey)
+	}
+	for _, name := range uniqueNames {
+		columns := uniqueKeys[name]
+		// Unique keys of tenant scoped tables are unique per tenant.
+		if m.tenantColumn != "" {
+			columns = append([]string{m.tenantColumn}, columns...)
+		}
+		m.uniqueKeys = append(m.uniqueKeys, columns)
+	}
+	return m
+}
+
+func (m *MemoryTable[T]) Insert(ctx context.Context, row T) error {
+	v := reflect.ValueOf(&row).Elem()
+	tenantID, scoped, err := m.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	if scoped {
+		tenant := v.FieldByIndex(m.columns[m.tenantColumn])
+		if value := tenant.String(); value != "" && value != tenantID {
+			return fmt.Errorf("row of tenant '%s' inserted for tenant '%s': %w", value, tenantID, ErrTenantMismatch)
+		}
+		tenant.SetString(tenantID)
+	}
+	now := m.clock.Now().UTC()
+	for _, field := range m.fields {
+		if !hasORMOption(field, autoCreateTime) && !hasORMOption(field, autoUpdateTime) {
+			continue
+		}
+		if err := setTimestamp(v.FieldByIndex(field.Index), now); err != nil {
+			return err
+		}
+	}
+	m.normalizeRow(v)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkUnique(v, -1); err != nil {
+		return err
+	}
+	m.rows = append(m.rows, row)
+	return nil
+}
+
+func (m *MemoryTable[T]) Get(ctx context.Context, key interface{}) (T, error) {
+	var zero T
+	where, _, err := keyWhere(key)
+	if err != nil {
+		return zero, err
+	}
+	if where == "" {
+		return zero, fmt.Errorf("refusing to get a row of table '%s': %w", m.tableName, ErrEmptyKey)
+	}
+	tenantID, scoped, err := m.tenant(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for i := range m.rows {
+		row := reflect.ValueOf(&m.rows[i]).Elem()
+		ok, err := m.matchKey(row, key, tenantID, scoped)
+		if err != nil {
+			return zero, err
+		}
+		if ok {
+			return m.rows[i], nil
+		}
+	}
+	return zero, &Error{Kind: KindNotFound, Table: m.tableName, Err: sql.ErrNoRows}
+}
+
+func (m *MemoryTable[T]) Update(ctx context.Context, key interface{}, fields interface{}) error {
+	tenantID, scoped, err := m.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	versionField := reflect.Indirect(reflect.ValueOf(key)).FieldByName("Version")
+	setFields, err := m.setFields(fields)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var matched []int
+	for i := range m.rows {
+		ok, err := m.matchKey(reflect.ValueOf(&m.rows[i]).Elem(), key, tenantID, scoped)
+		if err != nil {
+			return err
+		}
+		if ok {
+			matched = append(matched, i)
+		}
+	}
+	if len(matched) == 0 {
+		kind := KindNoRowsAffected
+		if versionField.IsValid() {
+			kind = KindVersionConflict
+		}
+		return &Error{Kind: kind, Table: m.tableName, Err: errors.New("no rows affected")}
+	}
+	_, err = m.update(matched, fields, setFields, versionField.IsValid())
+	return err
+}
+
+func (m *MemoryTable[T]) Delete(ctx context.Context, key interface{}) error {
+	tenantID, scoped, err := m.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rows := m.rows[:0]
+	for i := range m.rows {
+		ok, err := m.matchKey(reflect.ValueOf(&m.rows[i]).Elem(), key, tenantID, scoped)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			rows = append(rows, m.rows[i])
+		}
+	}
+	m.rows = rows
+	return nil
+}
+
+func (m *MemoryTable[T]) List(ctx context.Context, filters interface{}) ([]T, error) {
+	tenantID, scoped, err := m.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.Indirect(reflect.ValueOf(filters))
+	order, err := listOrder(v, reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	matched, err := m.matchFilters(v, tenantID, scoped)
+	var rows []T
+	for _, i := range matched {
+		rows = append(rows, m.rows[i])
+	}
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(order) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return compareRows(reflect.ValueOf(rows[i]), reflect.ValueOf(rows[j]), order) < 0
+		})
+	}
+	if limit := v.FieldByName("Limit"); limit.IsValid() && limit.Uint() > 0 && uint64(len(rows)) > limit.Uint() {
+		rows = rows[:limit.Uint()]
+	}
+	return rows, nil
+}
+
+// UpdateWhere is Database.UpdateWhere.
+func (m *MemoryTable[T]) UpdateWhere(ctx context.Context, filters interface{}, fields interface{}, opts ...WhereOption) (int64, error) {
+	options := whereOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if _, _, err := bulkFilters(m.tableName, filters, options); err != nil {
+		return 0, err
+	}
+	setFields, err := m.setFields(fields)
+	if err != nil {
+		return 0, err
+	}
+	tenantID, scoped, err := m.tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	matched, err := m.matchFilters(reflect.Indirect(reflect.ValueOf(filters)), tenantID, scoped)
+	if err != nil {
+		return 0, err
+	}
+	return m.update(matched, fields, setFields, options.bumpVersion)
+}
+
+// DeleteWhere is Database.DeleteWhere.
+func (m *MemoryTable[T]) DeleteWhere(ctx context.Context, filters interface{}, opts ...WhereOption) (int64, error) {
+	options := whereOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if _, _, err := bulkFilters(m.tableName, filters, options); err != nil {
+		return 0, err
+	}
+	tenantID, scoped, err := m.tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	matched, err := m.matchFilters(reflect.Indirect(reflect.ValueOf(filters)), tenantID, scoped)
+	if err != nil {
+		return 0, err
+	}
+	rows := make([]T, 0, len(m.rows)-len(matched))
+	for i := range m.rows {
+		if !slices.Contains(matched, i) {
+			rows = append(rows, m.rows[i])
+		}
+	}
+	m.rows = rows
+	return int64(len(matched)), nil
+}
+
+// tenant returns the tenant of the context if the table is tenant scoped.
+// Every operation starts with it, it returns the error of the fields of T.
+func (m *MemoryTable[T]) tenant(ctx context.Context) (string, bool, error) {
+	if m.err != nil {
+		return "", false, m.err
+	}
+	if m.tenantColumn == "" {
+		return "", false, nil
+	}
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", false, fmt.Errorf("table '%s' is scoped to tenants: %w", m.tableName, ErrMissingTenant)
+	}
+	return tenantID, true, nil
+}
+
+// column returns the field of the row holding the column.
+func (m *MemoryTable[T]) column(row reflect.Value, column string) (reflect.Value, error) {
+	index, ok := m.columns[column]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("no such column: %s.%s: %w", m.tableName, column, ErrInternal)
+	}
+	return row.FieldByIndex(index), nil
+}
+
+// matchKey returns true if the row matches the key as keyWhere and tenantWhere would.
+func (m *MemoryTable[T]) matchKey(row reflect.Value, key interface{}, tenantID string, scoped bool) (bool, error) {
+	if scoped && row.FieldByIndex(m.columns[m.tenantColumn]).String() != tenantID {
+		return false, nil
+	}
+	keyValue := reflect.Indirect(reflect.ValueOf(key))
+	fields, err := dbFields(keyValue.Type())
+	if err != nil {
+		return false, err
+	}
+	for _, field := range fields {
+		if isTenantField(field) {
+			continue
+		}
+		column, err := m.column(row, field.Tag.Get("db"))
+		if err != nil {
+			return false, err
+		}
+		if match, applies := matchCondition(column, "eq", keyValue.FieldByIndex(field.Index)); applies && !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchFilters returns the indexes of the rows matching the filters as filterWhere would.
+func (m *MemoryTable[T]) matchFilters(filters reflect.Value, tenantID string, scoped bool) ([]int, error) {
+	fields, err := dbFields(filters.Type())
+	if err != nil {
+		return nil, err
+	}
+	var matched []int
+	for i := range m.rows {
+		row := reflect.ValueOf(&m.rows[i]).Elem()
+		if scoped && row.FieldByIndex(m.columns[m.tenantColumn]).String() != tenantID {
+			continue
+		}
+		ok := true
+		for _, field := range fields {
+			if isTenantField(field) {
+				continue
+			}
+			columnName, operation, _ := strings.Cut(field.Tag.Get("db"), ":")
+			value := filters.FieldByIndex(field.Index)
+			if operation == "" || (value.Kind() != reflect.Slice && isEmptyValue(value)) {
+				continue
+			}
+			column, err := m.column(row, columnName)
+			if err != nil {
+				return nil, err
+			}
+			if match, applies := matchCondition(column, operation, value); applies && !match {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, i)
+		}
+	}
+	return matched, nil
+}
+
+// matchCondition returns true if the column value matches the key or filter value with the
+// operation. applies is false if the value does not restrict the rows: nil pointers, unset
+// Nullables, empty slices and unknown operations. As in SQL, NULL columns only match IS NULL.
+func matchCondition(column reflect.Value, operation string, value reflect.Value) (match bool, applies bool) {
+	if value.Kind() == reflect.Slice && !value.Type().Implements(valuerType) {
+		if value.Len() == 0 {
+			return false, false
+		}
+		columnValue := orderValue(column)
+		if columnValue == nil {
+			return false, true
+		}
+		in := false
+		for i := 0; i < value.Len(); i++ {
+			if compareValues(columnValue, orderValue(value.Index(i))) == 0 {
+				in = true
+				break
+			}
+		}
+		if strings.Contains(operation, "not_in") {
+			return !in, true
+		}
+		return in, true
+	}
+
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return false, false
+	}
+	if n, ok := asNullable(value); ok {
+		if !n.isSet() {
+			return false, false
+		}
+		if n.isNull() {
+			return orderValue(column) == nil, true
+		}
+	}
+	columnValue := orderValue(column)
+	if columnValue == nil {
+		return false, true
+	}
+	c := compareValues(columnValue, orderValue(value))
+	switch operation {
+	case "lt":
+		return c < 0, true
+	case "gt":
+		return c > 0, true
+	case "lte":
+		return c <= 0, true
+	case "gte":
+		return c >= 0, true
+	case "eq":
+		return c == 0, true
+	}
+	return false, false
+}
+
+// update sets the set fields on the rows, incrementing their version if bumpVersion is set, and
+// returns the number of updated rows. No row is updated if a unique key would be violated.
+// Automatic update timestamps of the rows are stamped.
+// setFields returns the fields set by an update fields struct, failing with ErrInternal if none is
+// set as Database.Update does. The tenant and the stamped timestamps are never set.
+func (m *MemoryTable[T]) setFields(fields interface{}) ([]reflect.StructField, error) {
+	fieldsValue := reflect.Indirect(reflect.ValueOf(fields))
+	fieldTypes, err := dbFields(fieldsValue.Type())
+	if err != nil {
+		return nil, err
+	}
+	var set []reflect.StructField
+	for _, field := range fieldTypes {
+		if isTenantField(field) || hasORMOption(field, autoUpdateTime) {
+			continue
+		}
+		value := fieldsValue.FieldByIndex(field.Index)
+		if n, ok := asNullable(value); ok && !n.isSet() || value.Kind() == reflect.Ptr && value.IsNil() {
+			continue
+		}
+		set = append(set, field)
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("no fields to update in table '%s': %w", m.tableName, ErrInternal)
+	}
+	return set, nil
+}
+
+func (m *MemoryTable[T]) update(indexes []int, fields interface{}, setFields []reflect.StructField, bumpVersion bool) (int64, error) {
+	fieldsValue := reflect.Indirect(reflect.ValueOf(fields))
+	now := m.clock.Now().UTC()
+	updated := make([]T, len(indexes))
+	for n, i := range indexes {
+		updated[n] = m.rows[i]
+		row := reflect.ValueOf(&updated[n]).Elem()
+		if bumpVersion {
+			version, err := m.column(row, "version")
+			if err != nil {
+				return 0, err
+			}
+			version.SetUint(version.Uint() + 1)
+		}
+		for _, field := range setFields {
+			value := fieldsValue.FieldByIndex(field.Index)
+			column, err := m.column(row, field.Tag.Get("db"))
+			if err != nil {
+				return 0, err
+			}
+			if err := assignColumn(column, value); err != nil {
+				return 0, fmt.Errorf("failed to update column %s: %s: %w", field.Tag.Get("db"), err.Error(), ErrInternal)
+			}
+		}
+		// The timestamps are stamped whatever the fields set them to.
+		for _, field := range m.fields {
+			if !hasORMOption(field, autoUpdateTime) {
+				continue
+			}
+			if err := setTimestamp(row.FieldByIndex(field.Index), now); err != nil {
+				return 0, err
+			}
+		}
+		m.normalizeRow(row)
+	}
+
+	for n, i := range indexes {
+		if err := m.checkUnique(reflect.ValueOf(&updated[n]).Elem(), i); err != nil {
+			return 0, err
+		}
+	}
+	for n, i := range indexes {
+		m.rows[i] = updated[n]
+	}
+	return int64(len(indexes)), nil
+}
+
+// checkUnique returns a duplicate error if the row has the unique key of another row than the
+// row at index self.
+func (m *MemoryTable[T]) checkUnique(row reflect.Value, self int) error {
+	for _, key := range m.uniqueKeys {
+		for i := range m.rows {
+			if i == self {
+				continue
+			}
+			other := reflect.ValueOf(&m.rows[i]).Elem()
+			duplicate := true
+			for _, column := range key {
+				a := orderValue(row.FieldByIndex(m.columns[column]))
+				b := orderValue(other.FieldByIndex(m.columns[column]))
+				// NULLs are distinct in unique keys
+				if a == nil || b == nil || compareValues(a, b) != 0 {
+					duplicate = false
+					break
+				}
+			}
+			if duplicate {
+				return &Error{Kind: KindDuplicate, Table: m.tableName, Columns: key}
+			}
+		}
+	}
+	return nil
+}
+
+// assignColumn sets the column field of a row to the value of an update field: a non-nil
+// pointer, a set Nullable or a plain value.
+func assignColumn(column reflect.Value, value reflect.Value) error {
+	if n, ok := asNullable(value); ok {
+		if n.isNull() {
+			if _, isNullable := asNullable(column); isNullable {
+				column.Set(value)
+			} else {
+				column.Set(reflect.Zero(column.Type()))
+			}
+			return nil
+		}
+		value = value.FieldByName("V")
+	} else if value.Kind() == reflect.Ptr && !column.Type().AssignableTo(value.Type()) {
+		value = value.Elem()
+	}
+	if t, ok := value.Interface().(time.Time); ok {
+		value = reflect.ValueOf(t.UTC())
+	}
+
+	switch {
+	case value.Type().AssignableTo(column.Type()):
+		column.Set(value)
+	case column.Kind() == reflect.Ptr && value.Type().AssignableTo(column.Type().Elem()):
+		ptr := reflect.New(column.Type().Elem())
+		ptr.Elem().Set(value)
+		column.Set(ptr)
+	default:
+		if n, ok := asNullable(column); ok && value.Type().AssignableTo(n.elemType()) {
+			column.Set(reflect.Zero(column.Type()))
+			column.FieldByName("V").Set(value)
+			column.FieldByName("Valid").SetBool(true)
+			column.FieldByName("Set").SetBool(true)
+			return nil
+		}
+		if !value.Type().ConvertibleTo(column.Type()) {
+			return fmt.Errorf("cannot assign %s to %s", value.Type(), column.Type())
+		}
+		column.Set(value.Convert(column.Type()))
+	}
+	return nil
+}
+
+// normalizeRow makes the row look as if read back from the database: times are in UTC and unset
+// Nullables, written as NULL, are NULL.
+func (m *MemoryTable[T]) normalizeRow(row reflect.Value) {
+	normalizeTimes(row, m.fields)
+	for _, field := range m.fields {
+		value := row.FieldByIndex(field.Index)
+		if n, ok := asNullable(value); ok && !n.isSet() {
+			value.FieldByName("Set").SetBool(true)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package simplesql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+type LedgerRowKeyed struct {
+	AccountID string                     `db:"account_id" orm:"key=primary"`
+	EntryID   string                     `db:"entry_id" orm:"key=primary"`
+	Version   uint64                     `db:"version"`
+	Amount    int64                      `db:"amount"`
+	Memo      simplesql.Nullable[string] `db:"memo"`
+}
+
+type LedgerTableMemoFilters struct {
+	AmountGte *int64                     `db:"amount:gte"`
+	MemoEq    simplesql.Nullable[string] `db:"memo:eq"`
+	OrderBy   []string
+	Limit     uint32 `db:"limit"`
+}
+
+type LedgerTableMemoKey struct {
+	AccountID string                     `db:"account_id"`
+	Memo      simplesql.Nullable[string] `db:"memo"`
+}
+
+func TestMemoryTable(t *testing.T) {
+	ctx := context.Background()
+	table := simplesql.NewMemoryTable[LedgerRowKeyed]("ledger")
+	for i := 0; i < 4; i++ {
+		err := table.Insert(ctx, LedgerRowKeyed{AccountID: fmt.Sprintf("acct%d", i%2), EntryID: fmt.Sprintf("entry%d", i), Amount: int64(i)})
+		require.NoError(t, err)
+	}
+	err := table.Insert(ctx, LedgerRowKeyed{AccountID: "acct0", EntryID: "entry0"})
+	var e *simplesql.Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, simplesql.KindDuplicate, e.Kind)
+	require.Equal(t, []string{"account_id", "entry_id"}, e.Columns)
+
+	// Unset Nullables are inserted as NULL.
+	row, err := table.Get(ctx, LedgerTableKeys{AccountID: "acct1", EntryID: "entry1"})
+	require.NoError(t, err)
+	require.Equal(t, simplesql.Null[string](), row.Memo)
+
+	err = table.Update(ctx, LedgerTableKeys{AccountID: "acct1", EntryID: "entry1"}, LedgerTableUpdateFields{Memo: simplesql.NewNullable("memo")})
+	require.NoError(t, err)
+	row, err = table.Get(ctx, LedgerTableMemoKey{AccountID: "acct1", Memo: simplesql.NewNullable("memo")})
+	require.NoError(t, err)
+	require.Equal(t, "entry1", row.EntryID)
+
+	// NULL keys and filters match NULL columns only.
+	row, err = table.Get(ctx, LedgerTableMemoKey{AccountID: "acct1", Memo: simplesql.Null[string]()})
+	require.NoError(t, err)
+	require.Equal(t, "entry3", row.EntryID)
+	rows, err := table.List(ctx, LedgerTableMemoFilters{MemoEq: simplesql.NewNullable("memo")})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	rows, err = table.List(ctx, LedgerTableMemoFilters{AmountGte: Int64Ptr(1), OrderBy: []string{"account_id", "amount DESC"}, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, "entry2", rows[0].EntryID)
+	require.Equal(t, "entry3", rows[1].EntryID)
+
+	_, err = table.List(ctx, LedgerTableMemoFilters{OrderBy: []string{"unknown"}})
+	require.ErrorIs(t, err, simplesql.ErrInternal)
+}
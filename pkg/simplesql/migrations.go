@@ -1,6 +1,9 @@
 package simplesql
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"sort"
 
 	"github.com/jmoiron/sqlx"
@@ -12,33 +15,40 @@ type Migration struct {
 	Down    string
 }
 
-func getCurrentSchemaVersion(db *sqlx.DB) (int, error) {
+func getCurrentSchemaVersion(ctx context.Context, db *sqlx.DB) (int, error) {
 	var version int
-	err := db.Get(&version, "SELECT version FROM schema_version")
+	err := db.GetContext(ctx, &version, "SELECT version FROM schema_version")
 	return version, err
 }
 
-func setCurrentSchemaVersion(db *sqlx.DB, version int) error {
-	_, err := db.Exec("DELETE FROM schema_version")
+func setCurrentSchemaVersion(ctx context.Context, db *sqlx.DB, version int) error {
+	_, err := db.ExecContext(ctx, "DELETE FROM schema_version")
 	if err != nil {
 		return err
 	}
-	_, err = db.Exec("INSERT INTO schema_version (version) VALUES (?)", version)
+	_, err = db.ExecContext(ctx, "INSERT INTO schema_version (version) VALUES (?)", version)
 	return err
 }
 
+// ApplyMigrations applies the migrations newer than the schema version of the database.
 func (d *Database) ApplyMigrations(schemaMigrations []Migration) error {
-	_, err := d.DB.Exec("CREATE TABLE IF NOT EXISTS schema_version (version INTEGER PRIMARY KEY);")
+	return d.ApplyMigrationsContext(context.Background(), schemaMigrations)
+}
+
+// ApplyMigrationsContext is ApplyMigrations bounded by the context. Migrations applied before
+// the context is done are kept.
+func (d *Database) ApplyMigrationsContext(ctx context.Context, schemaMigrations []Migration) error {
+	_, err := d.DB.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_version (version INTEGER PRIMARY KEY);")
 	if err != nil {
-		return err
+		return d.handleErr("schema_version", err)
 	}
 
-	currentVersion, err := getCurrentSchemaVersion(d.DB)
+	currentVersion, err := getCurrentSchemaVersion(ctx, d.DB)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
+		if errors.Is(err, sql.ErrNoRows) {
 			currentVersion = 0
 		} else {
-			return err
+			return d.handleErr("schema_version", err)
 		}
 	}
 
@@ -49,13 +59,13 @@ func (d *Database) ApplyMigrations(schemaMigrations []Migration) error {
 
 	for _, s := range schemaMigrations {
 		if s.Version > currentVersion {
-			_, err := d.DB.Exec(s.Up)
+			_, err := d.DB.ExecContext(ctx, s.Up)
 			if err != nil {
-				return err
+				return d.handleErr("", err)
 			}
 
-			if err := setCurrentSchemaVersion(d.DB, s.Version); err != nil {
-				return err
+			if err := setCurrentSchemaVersion(ctx, d.DB, s.Version); err != nil {
+				return d.handleErr("schema_version", err)
 			}
 		}
 	}
@@ -0,0 +1,128 @@
+package simplesql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Nullable is a column value which is either unset, set to a value or set to NULL.
+//
+// An unset Nullable is ignored: it does not update the column in Update, and it does not
+// restrict the rows matched by Get, Update and Delete keys or List filters. A Nullable set to
+// NULL writes NULL and matches rows with "column IS NULL".
+type Nullable[T any] struct {
+	V     T
+	Valid bool // V is not NULL
+	Set   bool // The value is set, either to V or to NULL
+}
+
+// NewNullable returns a Nullable set to v.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{V: v, Valid: true, Set: true}
+}
+
+// Null returns a Nullable set to NULL.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Set: true}
+}
+
+// Get returns the value and whether it is set to a non-NULL value.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.V, n.Set && n.Valid
+}
+
+// Scan implements sql.Scanner so that Nullable can be used in row structs.
+// A scanned Nullable is always set.
+func (n *Nullable[T]) Scan(src interface{}) error {
+	var s sql.Null[T]
+	if err := s.Scan(src); err != nil {
+		return err
+	}
+	n.V, n.Valid, n.Set = s.V, s.Valid, true
+	return nil
+}
+
+// Value implements driver.Valuer. Unset and NULL values are both written as NULL.
+func (n Nullable[T]) Value() (driver.Value, error) {
+	if !n.Set || !n.Valid {
+		return nil, nil
+	}
+	if t, ok := any(n.V).(time.Time); ok {
+		return t.UTC(), nil
+	}
+	if valuer, ok := any(n.V).(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+// MarshalJSON encodes unset and NULL values as null.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Set || !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON decodes null as NULL. A decoded Nullable is always set.
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*n = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*n = NewNullable(v)
+	return nil
+}
+
+func (n Nullable[T]) isSet() bool {
+	return n.Set
+}
+
+func (n Nullable[T]) isNull() bool {
+	return n.Set && !n.Valid
+}
+
+func (n Nullable[T]) elemType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// nullable is implemented by every Nullable[T].
+type nullable interface {
+	isSet() bool
+	isNull() bool
+	elemType() reflect.Type
+}
+
+// asNullable returns the value as a nullable if it is a Nullable[T].
+func asNullable(v reflect.Value) (nullable, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	n, ok := v.Interface().(nullable)
+	return n, ok
+}
+
+// keyCondition returns the WHERE condition matching a key or filter field against column with
+// the given operator and placeholder. ok is false if the field does not restrict the rows:
+// nil pointers and unset Nullables. Nullables set to NULL match with IS NULL and take no parameter.
+func keyCondition(column, operator, placeholder string, value reflect.Value) (condition string, params []interface{}, ok bool) {
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return "", nil, false
+	}
+	if n, isNullable := asNullable(value); isNullable {
+		if !n.isSet() {
+			return "", nil, false
+		}
+		if n.isNull() {
+			return column + " IS NULL", nil, true
+		}
+	}
+	return column + " " + operator + " " + placeholder, []interface{}{normalizeValue(value)}, true
+}
@@ -0,0 +1,149 @@
+package simplesql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+var taskTableMigrations = []simplesql.Migration{
+	{
+		Version: 1,
+		Up: `
+			CREATE TABLE task (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				owner VARCHAR(255),
+				deadline DATETIME
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS task;
+			`,
+	},
+}
+
+type TaskRow struct {
+	ID       string                        `db:"id"`
+	Owner    simplesql.Nullable[string]    `db:"owner"`
+	Deadline simplesql.Nullable[time.Time] `db:"deadline"`
+}
+
+type TaskTableKeys struct {
+	ID    *string                    `db:"id"`
+	Owner simplesql.Nullable[string] `db:"owner"`
+}
+
+type TaskTableUpdateFields struct {
+	Owner    simplesql.Nullable[string]    `db:"owner"`
+	Deadline simplesql.Nullable[time.Time] `db:"deadline"`
+}
+
+type TaskTableSelectFilters struct {
+	OwnerEq simplesql.Nullable[string] `db:"owner:eq"`
+	Limit   uint32                     `db:"limit"`
+}
+
+func TestNullable(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+	err = simplesqlDb.ApplyMigrations(taskTableMigrations)
+	require.NoError(t, err)
+
+	deadline := time.Date(2024, 11, 5, 10, 30, 0, 0, time.UTC)
+
+	t.Run("Schema", func(t *testing.T) {
+		diff, err := simplesqlDb.VerifySchema(context.Background(), "task", TaskRow{})
+		require.NoError(t, err)
+		require.NoError(t, diff.Err())
+	})
+
+	t.Run("Insert", func(t *testing.T) {
+		err := simplesqlDb.Insert(context.Background(), db, "task", TaskRow{
+			ID:       "task0",
+			Owner:    simplesql.NewNullable("alice"),
+			Deadline: simplesql.NewNullable(deadline),
+		})
+		require.NoError(t, err)
+
+		// Unset and NULL values are both inserted as NULL.
+		err = simplesqlDb.Insert(context.Background(), db, "task", TaskRow{ID: "task1", Owner: simplesql.Null[string]()})
+		require.NoError(t, err)
+
+		var task TaskRow
+		err = simplesqlDb.Get(context.Background(), "task", TaskTableKeys{ID: StringPtr("task1")}, &task)
+		require.NoError(t, err)
+		require.Equal(t, simplesql.Null[string](), task.Owner)
+		require.Equal(t, simplesql.Null[time.Time](), task.Deadline)
+
+		err = simplesqlDb.Get(context.Background(), "task", TaskTableKeys{ID: StringPtr("task0")}, &task)
+		require.NoError(t, err)
+		owner, ok := task.Owner.Get()
+		require.True(t, ok)
+		require.Equal(t, "alice", owner)
+		require.Equal(t, simplesql.NewNullable(deadline), task.Deadline)
+	})
+
+	t.Run("Get by NULL key", func(t *testing.T) {
+		var task TaskRow
+		err := simplesqlDb.Get(context.Background(), "task", TaskTableKeys{Owner: simplesql.Null[string]()}, &task)
+		require.NoError(t, err)
+		require.Equal(t, "task1", task.ID)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		// An unset field is left untouched while a NULL field is cleared.
+		err := simplesqlDb.Update(context.Background(), db, "task", TaskTableKeys{ID: StringPtr("task0")}, TaskTableUpdateFields{
+			Owner: simplesql.Null[string](),
+		})
+		require.NoError(t, err)
+
+		var task TaskRow
+		err = simplesqlDb.Get(context.Background(), "task", TaskTableKeys{ID: StringPtr("task0")}, &task)
+		require.NoError(t, err)
+		require.Equal(t, simplesql.Null[string](), task.Owner)
+		require.Equal(t, simplesql.NewNullable(deadline), task.Deadline)
+
+		err = simplesqlDb.Update(context.Background(), db, "task", TaskTableKeys{ID: StringPtr("task1")}, TaskTableUpdateFields{
+			Owner: simplesql.NewNullable("bob"),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var tasks []TaskRow
+		err := simplesqlDb.List(context.Background(), "task", TaskTableSelectFilters{OwnerEq: simplesql.Null[string]()}, &tasks)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, "task0", tasks[0].ID)
+
+		tasks = nil
+		err = simplesqlDb.List(context.Background(), "task", TaskTableSelectFilters{OwnerEq: simplesql.NewNullable("bob")}, &tasks)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, "task1", tasks[0].ID)
+
+		tasks = nil
+		err = simplesqlDb.List(context.Background(), "task", TaskTableSelectFilters{}, &tasks)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+	})
+
+	t.Run("Delete by NULL key", func(t *testing.T) {
+		err := simplesqlDb.Delete(context.Background(), db, "task", TaskTableKeys{Owner: simplesql.Null[string]()})
+		require.NoError(t, err)
+
+		var tasks []TaskRow
+		err = simplesqlDb.List(context.Background(), "task", TaskTableSelectFilters{}, &tasks)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, "task1", tasks[0].ID)
+	})
+}
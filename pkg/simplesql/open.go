@@ -0,0 +1,264 @@
+package simplesql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultBusyTimeout    = 5 * time.Second
+	defaultConnectBackoff = 100 * time.Millisecond
+	maxConnectBackoff     = 10 * time.Second
+)
+
+// Config configures the database opened by Open.
+type Config struct {
+	Dialect Dialect
+	// DSN is used as is when set, instead of the DSN built from the fields of the dialect.
+	DSN string
+
+	// MySQL connection. The DSN always sets parseTime, which simplesql relies on.
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	// Params are extra DSN parameters, such as tls or charset.
+	Params map[string]string
+
+	// SQLite database file, or ":memory:". An in-memory database lives in a single connection,
+	// so its pool is limited to one connection.
+	Path string
+	// BusyTimeout is how long SQLite waits for a lock before failing. Defaults to 5s.
+	BusyTimeout time.Duration
+	// WAL switches the journal of a database file to write-ahead logging, letting readers
+	// run concurrently with a writer.
+	WAL bool
+	// ForeignKeys enforces foreign key constraints, which SQLite ignores by default.
+	ForeignKeys bool
+
+	// Pool settings, left to the database/sql defaults when zero.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// ConnectRetries is the number of times the first connection is retried, waiting
+	// ConnectBackoff before the first retry and doubling the wait after each one, up to 10s.
+	// ConnectBackoff defaults to 100ms.
+	ConnectRetries int
+	ConnectBackoff time.Duration
+}
+
+// ConfigFromEnv reads the config from environment variables named after the fields of Config,
+// with the prefix: <prefix>_DIALECT, <prefix>_DSN, <prefix>_HOST, <prefix>_PORT, <prefix>_USER,
+// <prefix>_PASSWORD, <prefix>_DATABASE, <prefix>_PATH, <prefix>_BUSY_TIMEOUT, <prefix>_WAL,
+// <prefix>_FOREIGN_KEYS, <prefix>_MAX_OPEN_CONNS, <prefix>_MAX_IDLE_CONNS,
+// <prefix>_CONN_MAX_LIFETIME, <prefix>_CONN_MAX_IDLE_TIME, <prefix>_CONNECT_RETRIES and
+// <prefix>_CONNECT_BACKOFF. <prefix>_PARAMS holds extra DSN parameters as a query string.
+// Durations use the syntax of time.ParseDuration. Unset variables leave fields zero.
+func ConfigFromEnv(prefix string) (Config, error) {
+	env := func(name string) (string, bool) {
+		return os.LookupEnv(prefix + "_" + name)
+	}
+
+	var errs []string
+	stringVar := func(name string, dest *string) {
+		if v, ok := env(name); ok {
+			*dest = v
+		}
+	}
+	intVar := func(name string, dest *int) {
+		if v, ok := env(name); ok {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s_%s: %s", prefix, name, err.Error()))
+			}
+			*dest = i
+		}
+	}
+	boolVar := func(name string, dest *bool) {
+		if v, ok := env(name); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s_%s: %s", prefix, name, err.Error()))
+			}
+			*dest = b
+		}
+	}
+	durationVar := func(name string, dest *time.Duration) {
+		if v, ok := env(name); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s_%s: %s", prefix, name, err.Error()))
+			}
+			*dest = d
+		}
+	}
+
+	var cfg Config
+	var dialect string
+	stringVar("DIALECT", &dialect)
+	cfg.Dialect = Dialect(dialect)
+	stringVar("DSN", &cfg.DSN)
+	stringVar("HOST", &cfg.Host)
+	intVar("PORT", &cfg.Port)
+	stringVar("USER", &cfg.User)
+	stringVar("PASSWORD", &cfg.Password)
+	stringVar("DATABASE", &cfg.Database)
+	if v, ok := env("PARAMS"); ok {
+		values, err := url.ParseQuery(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s_PARAMS: %s", prefix, err.Error()))
+		}
+		cfg.Params = map[string]string{}
+		for key := range values {
+			cfg.Params[key] = values.Get(key)
+		}
+	}
+	stringVar("PATH", &cfg.Path)
+	durationVar("BUSY_TIMEOUT", &cfg.BusyTimeout)
+	boolVar("WAL", &cfg.WAL)
+	boolVar("FOREIGN_KEYS", &cfg.ForeignKeys)
+	intVar("MAX_OPEN_CONNS", &cfg.MaxOpenConns)
+	intVar("MAX_IDLE_CONNS", &cfg.MaxIdleConns)
+	durationVar("CONN_MAX_LIFETIME", &cfg.ConnMaxLifetime)
+	durationVar("CONN_MAX_IDLE_TIME", &cfg.ConnMaxIdleTime)
+	intVar("CONNECT_RETRIES", &cfg.ConnectRetries)
+	durationVar("CONNECT_BACKOFF", &cfg.ConnectBackoff)
+
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("invalid database config: %s: %w", strings.Join(errs, ", "), ErrInternal)
+	}
+	return cfg, nil
+}
+
+// FormatDSN returns the DSN of the config for its dialect.
+func (c Config) FormatDSN() (string, error) {
+	if c.DSN != "" {
+		return c.DSN, nil
+	}
+	switch c.Dialect {
+	case DialectMySQL:
+		cfg := mysql.NewConfig()
+		cfg.User = c.User
+		cfg.Passwd = c.Password
+		cfg.Net = "tcp"
+		port := c.Port
+		if port == 0 {
+			port = 3306
+		}
+		cfg.Addr = net.JoinHostPort(c.Host, strconv.Itoa(port))
+		cfg.DBName = c.Database
+		cfg.ParseTime = true
+		if len(c.Params) > 0 {
+			cfg.Params = c.Params
+		}
+		return cfg.FormatDSN(), nil
+	case DialectSQLite:
+		if c.Path == "" {
+			return "", fmt.Errorf("sqlite database path not configured: %w", ErrInternal)
+		}
+		busyTimeout := c.BusyTimeout
+		if busyTimeout == 0 {
+			busyTimeout = defaultBusyTimeout
+		}
+		params := url.Values{}
+		params.Set("_busy_timeout", strconv.FormatInt(busyTimeout.Milliseconds(), 10))
+		if c.WAL && c.Path != ":memory:" {
+			params.Set("_journal_mode", "WAL")
+		}
+		if c.ForeignKeys {
+			params.Set("_foreign_keys", "on")
+		}
+		for key, value := range c.Params {
+			params.Set(key, value)
+		}
+		return fmt.Sprintf("file:%s?%s", c.Path, params.Encode()), nil
+	}
+	return "", fmt.Errorf("unsupported dialect '%s': %w", c.Dialect, ErrInternal)
+}
+
+// Open opens the database of the config, applies the pool settings and waits for the first
+// connection, retrying as configured. The options apply to the returned Database.
+func Open(ctx context.Context, cfg Config, opts ...Option) (Database, error) {
+	dsn, err := cfg.FormatDSN()
+	if err != nil {
+		return Database{}, err
+	}
+	db, err := sqlx.Open(string(cfg.Dialect), dsn)
+	if err != nil {
+		return Database{}, fmt.Errorf("failed to open %s database: %s: %w", cfg.Dialect, err.Error(), ErrInternal)
+	}
+
+	if cfg.Dialect == DialectSQLite && cfg.Path == ":memory:" {
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxLifetime(0)
+		db.SetConnMaxIdleTime(0)
+	} else {
+		if cfg.MaxOpenConns > 0 {
+			db.SetMaxOpenConns(cfg.MaxOpenConns)
+		}
+		if cfg.ConnMaxLifetime > 0 {
+			db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		}
+		if cfg.ConnMaxIdleTime > 0 {
+			db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+		}
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+
+	d := NewDatabase(db, opts...)
+	backoff := cfg.ConnectBackoff
+	if backoff <= 0 {
+		backoff = defaultConnectBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err = d.Health(ctx)
+		if err == nil {
+			return d, nil
+		}
+		// Attempts time out on their own while the database starts, only the parent context being
+		// done stops the retries.
+		if attempt >= cfg.ConnectRetries || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = d.handleErr("", ctx.Err())
+		case <-time.After(backoff):
+			backoff = min(2*backoff, maxConnectBackoff)
+			continue
+		}
+		break
+	}
+	db.Close()
+	return Database{}, fmt.Errorf("failed to connect to %s database: %w", cfg.Dialect, err)
+}
+
+// Health checks that the database is reachable, within the default timeout. A nil error maps to
+// the SERVING status of a gRPC health service, any other to NOT_SERVING.
+func (d *Database) Health(ctx context.Context) error {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	if err := d.DB.PingContext(ctx); err != nil {
+		return d.handleErr("", err)
+	}
+	var one int
+	if err := d.DB.GetContext(ctx, &one, "SELECT 1"); err != nil {
+		return d.handleErr("", err)
+	}
+	return nil
+}
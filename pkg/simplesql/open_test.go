@@ -0,0 +1,182 @@
+package simplesql_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SQLite file", func(t *testing.T) {
+		db, err := simplesql.Open(ctx, simplesql.Config{
+			Dialect:      simplesql.DialectSQLite,
+			Path:         filepath.Join(t.TempDir(), "test.db"),
+			BusyTimeout:  2 * time.Second,
+			WAL:          true,
+			ForeignKeys:  true,
+			MaxOpenConns: 4,
+		}, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+		require.NoError(t, err)
+		defer db.DB.Close()
+		require.NoError(t, db.Health(ctx))
+		require.Equal(t, 4, db.DB.Stats().MaxOpenConnections)
+
+		var journalMode string
+		require.NoError(t, db.DB.Get(&journalMode, "PRAGMA journal_mode"))
+		require.Equal(t, "wal", journalMode)
+		var busyTimeout int
+		require.NoError(t, db.DB.Get(&busyTimeout, "PRAGMA busy_timeout"))
+		require.Equal(t, 2000, busyTimeout)
+		var foreignKeys int
+		require.NoError(t, db.DB.Get(&foreignKeys, "PRAGMA foreign_keys"))
+		require.Equal(t, 1, foreignKeys)
+
+		require.NoError(t, db.ApplyMigrations(teamTableMigrations))
+		err = db.Insert(ctx, db.DB, "member", MemberRow{ID: "member0", TeamID: StringPtr("missing"), Version: 1})
+		require.ErrorIs(t, err, simplesql.ErrForeignKey)
+	})
+
+	t.Run("SQLite in memory", func(t *testing.T) {
+		db, err := simplesql.Open(ctx, simplesql.Config{
+			Dialect:      simplesql.DialectSQLite,
+			Path:         ":memory:",
+			MaxOpenConns: 4,
+		})
+		require.NoError(t, err)
+		defer db.DB.Close()
+		// Each connection would see its own database.
+		require.Equal(t, 1, db.DB.Stats().MaxOpenConnections)
+		require.NoError(t, db.ApplyMigrations(teamTableMigrations))
+		require.NoError(t, db.Insert(ctx, db.DB, "team", TeamRow{ID: "team0", Name: "storage"}))
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		// Nothing listens on the port, every attempt fails.
+		start := time.Now()
+		_, err := simplesql.Open(ctx, simplesql.Config{
+			Dialect:        simplesql.DialectMySQL,
+			Host:           "127.0.0.1",
+			Port:           1,
+			User:           "root",
+			ConnectRetries: 2,
+			ConnectBackoff: 10 * time.Millisecond,
+		})
+		require.Error(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = simplesql.Open(canceled, simplesql.Config{
+			Dialect:        simplesql.DialectMySQL,
+			Host:           "127.0.0.1",
+			Port:           1,
+			ConnectRetries: 100,
+			ConnectBackoff: time.Hour,
+		})
+		require.ErrorIs(t, err, simplesql.ErrTimeout)
+	})
+
+	t.Run("Retry attempt timeouts", func(t *testing.T) {
+		// The server accepts the connections and never greets, every attempt times out.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		var accepted atomic.Int32
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				accepted.Add(1)
+				// Hold the connection until the attempt gives up on it.
+				go func() {
+					defer conn.Close()
+					_, _ = io.Copy(io.Discard, conn)
+				}()
+			}
+		}()
+
+		_, err = simplesql.Open(ctx, simplesql.Config{
+			Dialect:        simplesql.DialectMySQL,
+			Host:           "127.0.0.1",
+			Port:           listener.Addr().(*net.TCPAddr).Port,
+			User:           "root",
+			ConnectRetries: 2,
+			ConnectBackoff: 10 * time.Millisecond,
+		}, simplesql.WithDefaultTimeout(50*time.Millisecond))
+		require.ErrorIs(t, err, simplesql.ErrTimeout)
+		require.EqualValues(t, 3, accepted.Load())
+	})
+
+	t.Run("Unsupported dialect", func(t *testing.T) {
+		_, err := simplesql.Open(ctx, simplesql.Config{Dialect: "postgres"})
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+	})
+}
+
+func TestConfigDSN(t *testing.T) {
+	dsn, err := simplesql.Config{
+		Dialect:  simplesql.DialectMySQL,
+		Host:     "db.local",
+		User:     "app",
+		Password: "secret",
+		Database: "ledger",
+		Params:   map[string]string{"tls": "true"},
+	}.FormatDSN()
+	require.NoError(t, err)
+	require.Equal(t, "app:secret@tcp(db.local:3306)/ledger?parseTime=true&tls=true", dsn)
+
+	dsn, err = simplesql.Config{
+		Dialect: simplesql.DialectSQLite,
+		Path:    "/var/lib/app.db",
+		WAL:     true,
+	}.FormatDSN()
+	require.NoError(t, err)
+	require.Equal(t, "file:/var/lib/app.db?_busy_timeout=5000&_journal_mode=WAL", dsn)
+
+	dsn, err = simplesql.Config{Dialect: simplesql.DialectSQLite, DSN: "file:custom.db"}.FormatDSN()
+	require.NoError(t, err)
+	require.Equal(t, "file:custom.db", dsn)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("LEDGER_DB_DIALECT", "mysql")
+	t.Setenv("LEDGER_DB_HOST", "db.local")
+	t.Setenv("LEDGER_DB_PORT", "3307")
+	t.Setenv("LEDGER_DB_USER", "app")
+	t.Setenv("LEDGER_DB_DATABASE", "ledger")
+	t.Setenv("LEDGER_DB_PARAMS", "tls=true")
+	t.Setenv("LEDGER_DB_MAX_OPEN_CONNS", "20")
+	t.Setenv("LEDGER_DB_CONN_MAX_LIFETIME", "5m")
+	t.Setenv("LEDGER_DB_CONNECT_RETRIES", "3")
+
+	cfg, err := simplesql.ConfigFromEnv("LEDGER_DB")
+	require.NoError(t, err)
+	require.Equal(t, simplesql.Config{
+		Dialect:         simplesql.DialectMySQL,
+		Host:            "db.local",
+		Port:            3307,
+		User:            "app",
+		Database:        "ledger",
+		Params:          map[string]string{"tls": "true"},
+		MaxOpenConns:    20,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnectRetries:  3,
+	}, cfg)
+
+	t.Setenv("LEDGER_DB_WAL", "sometimes")
+	_, err = simplesql.ConfigFromEnv("LEDGER_DB")
+	require.ErrorIs(t, err, simplesql.ErrInternal)
+	require.ErrorContains(t, err, "LEDGER_DB_WAL")
+}
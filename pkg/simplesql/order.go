@@ -0,0 +1,195 @@
+package simplesql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// orderTerm is a column of the ORDER BY clause of List.
+type orderTerm struct {
+	column string
+	desc   bool
+	// index is the index path of the field of the column in the rows.
+	index []int
+}
+
+func (o orderTerm) String() string {
+	if o.desc {
+		return o.column + " DESC"
+	}
+	return o.column
+}
+
+// listOrder parses the OrderBy field of List filters, a []string such as {"name", "id DESC"}.
+// The columns must be columns of the result rows, since they are interpolated in the query.
+func listOrder(filters reflect.Value, rowType reflect.Type) ([]orderTerm, error) {
+	field := filters.FieldByName("OrderBy")
+	if !field.IsValid() {
+		return nil, nil
+	}
+	orderBy, ok := field.Interface().([]string)
+	if !ok {
+		return nil, fmt.Errorf("OrderBy filter must be a []string: %w", ErrInternal)
+	}
+
+	fields, err := dbFields(rowType)
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string][]int{}
+	for _, f := range fields {
+		columns[f.Tag.Get("db")] = f.Index
+	}
+	var terms []orderTerm
+	for _, entry := range orderBy {
+		parts := strings.Fields(entry)
+		if len(parts) == 0 || len(parts) > 2 || columns[parts[0]] == nil {
+			return nil, fmt.Errorf("invalid order by '%s': %w", entry, ErrInternal)
+		}
+		term := orderTerm{column: parts[0], index: columns[parts[0]]}
+		if len(parts) == 2 {
+			switch strings.ToUpper(parts[1]) {
+			case "ASC":
+			case "DESC":
+				term.desc = true
+			default:
+				return nil, fmt.Errorf("invalid order by '%s': %w", entry, ErrInternal)
+			}
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// rowElemType returns the struct type of the rows of a result, a pointer to a slice of structs
+// or of pointers to structs.
+func rowElemType(result interface{}) reflect.Type {
+	t := reflect.TypeOf(result)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+// compareRows compares two rows, structs or pointers to structs of the row type of the order
+// terms, on the terms.
+func compareRows(a, b reflect.Value, terms []orderTerm) int {
+	a, b = reflect.Indirect(a), reflect.Indirect(b)
+	for _, term := range terms {
+		c := compareValues(orderValue(a.FieldByIndex(term.index)), orderValue(b.FieldByIndex(term.index)))
+		if term.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// orderValue returns the value of a column field as nil, int64, uint64, float64, bool, string,
+// []byte or time.Time.
+func orderValue(v reflect.Value) interface{} {
+	value := normalizeValue(v)
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		if value, err = valuer.Value(); err != nil {
+			return nil
+		}
+	}
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.String()
+	}
+	return value
+}
+
+// compareValues compares column values the way MySQL and SQLite order them: NULL first.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return compareOrdered(a, b)
+		case uint64:
+			if a < 0 {
+				return -1
+			}
+			return compareOrdered(uint64(a), b)
+		case float64:
+			return compareOrdered(float64(a), b)
+		}
+	case uint64:
+		switch b := b.(type) {
+		case uint64:
+			return compareOrdered(a, b)
+		case int64:
+			return -compareValues(b, a)
+		case float64:
+			return compareOrdered(float64(a), b)
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			return compareOrdered(a, b)
+		}
+		return -compareValues(b, a)
+	case bool:
+		if b, ok := b.(bool); ok {
+			return compareOrdered(boolInt(a), boolInt(b))
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case []byte:
+		if b, ok := b.([]byte); ok {
+			return bytes.Compare(a, b)
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,293 @@
+package simplesql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// OutboxTableName is the table the changes of outboxed tables are written to.
+	OutboxTableName = "simplesql_outbox"
+	// OutboxCheckpointTableName is the table holding the checkpoints of the change feed consumers.
+	OutboxCheckpointTableName = "simplesql_outbox_checkpoint"
+)
+
+// OutboxMigrations returns the migrations creating the outbox and checkpoint tables, starting at firstVersion.
+func OutboxMigrations(dialect Dialect, firstVersion int) []Migration {
+	switch dialect {
+	case DialectMySQL:
+		return []Migration{
+			{
+				Version: firstVersion,
+				Up: `
+					CREATE TABLE simplesql_outbox (
+						seq BIGINT NOT NULL AUTO_INCREMENT,
+						table_name VARCHAR(255) NOT NULL,
+						record_key JSON NOT NULL,
+						operation VARCHAR(16) NOT NULL,
+						actor VARCHAR(255) NOT NULL,
+						changed_at DATETIME(6) NOT NULL,
+						before_image JSON,
+						after_image JSON,
+						PRIMARY KEY (seq)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS simplesql_outbox;
+				`,
+			},
+			{
+				Version: firstVersion + 1,
+				Up: `
+					CREATE TABLE simplesql_outbox_checkpoint (
+						consumer VARCHAR(255) NOT NULL,
+						seq BIGINT NOT NULL,
+						PRIMARY KEY (consumer)
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS simplesql_outbox_checkpoint;
+				`,
+			},
+		}
+	case DialectSQLite:
+		return []Migration{
+			{
+				Version: firstVersion,
+				Up: `
+					CREATE TABLE simplesql_outbox (
+						seq INTEGER PRIMARY KEY AUTOINCREMENT,
+						table_name TEXT NOT NULL,
+						record_key TEXT NOT NULL,
+						operation TEXT NOT NULL,
+						actor TEXT NOT NULL,
+						changed_at DATETIME NOT NULL,
+						before_image TEXT,
+						after_image TEXT
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS simplesql_outbox;
+				`,
+			},
+			{
+				Version: firstVersion + 1,
+				Up: `
+					CREATE TABLE simplesql_outbox_checkpoint (
+						consumer TEXT NOT NULL PRIMARY KEY,
+						seq INTEGER NOT NULL
+					);
+				`,
+				Down: `
+					DROP TABLE IF EXISTS simplesql_outbox_checkpoint;
+				`,
+			},
+		}
+	}
+	return nil
+}
+
+// Change is a row of the outbox. Key holds the primary key columns of the changed row, and
+// Before and After its JSON images keyed by column name, see DecodeImage.
+type Change struct {
+	Seq       int64           `db:"seq"`
+	Table     string          `db:"table_name"`
+	Key       json.RawMessage `db:"record_key" orm:"json"`
+	Operation Operation       `db:"operation"`
+	Actor     string          `db:"actor"`
+	ChangedAt time.Time       `db:"changed_at"`
+	Before    json.RawMessage `db:"before_image" orm:"json"`
+	After     json.RawMessage `db:"after_image" orm:"json"`
+}
+
+// WithOutbox writes every Insert, Update and Delete of the table to the outbox, OutboxTableName,
+// within the same transaction as the mutation. The changes are read back through a ChangeFeed.
+// The outbox tables are created by OutboxMigrations.
+//
+// Mutations of outboxed tables must be run with a *sqlx.DB, in which case a transaction is
+// started for them, or with an execer which can also query, such as *sqlx.Tx.
+func WithOutbox(tableName string) Option {
+	return func(d *Database) {
+		d.trackChanges().outboxed[tableName] = true
+	}
+}
+
+// recordOutbox writes a change to the outbox for each changed row.
+func (d *Database) recordOutbox(
+	ctx context.Context, tx changeExecer, tableName string, operation Operation, primaryKey []string, changes []rowChange, changedAt time.Time,
+) error {
+	query := d.DB.Rebind(fmt.Sprintf(`
+		INSERT INTO %s
+		(table_name, record_key, operation, actor, changed_at, before_image, after_image)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, OutboxTableName))
+
+	actor := ActorFromContext(ctx)
+	for _, change := range changes {
+		keyImage := rowImage{}
+		for _, column := range primaryKey {
+			keyImage[column] = change.keyImage()[column]
+		}
+		key, err := marshalImage(keyImage)
+		if err != nil {
+			return err
+		}
+		before, err := marshalImage(change.before)
+		if err != nil {
+			return err
+		}
+		after, err := marshalImage(change.after)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, tableName, key, string(operation), actor, changedAt, before, after)
+		if err != nil {
+			return fmt.Errorf("failed to write change of table '%s' to the outbox: %w", tableName, d.handleErr(tableName, err))
+		}
+	}
+	return nil
+}
+
+// ChangeFeed reads the changes written to the outbox in sequence order.
+//
+// Sequence numbers are allocated when a change is written, not when its transaction commits, so a
+// long running transaction may commit a change with a lower sequence number than changes already
+// read. Consumers needing every change should keep transactions of outboxed tables short.
+type ChangeFeed struct {
+	db Database
+}
+
+func NewChangeFeed(db Database) *ChangeFeed {
+	return &ChangeFeed{db: db}
+}
+
+// Next returns up to limit changes with a sequence number greater than afterSeq, in order.
+func (f *ChangeFeed) Next(ctx context.Context, afterSeq int64, limit int) ([]Change, error) {
+	ctx, cancel := f.db.withTimeout(ctx)
+	defer cancel()
+
+	columnNames, _, err := getColumnNamesAndPlaceholders(Change{})
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT %s%s FROM %s WHERE seq > ? ORDER BY seq LIMIT ?`, f.db.selectHint(ctx), columnNames, OutboxTableName)
+
+	var changes []Change
+	if err := f.db.selectRows(ctx, &changes, f.db.DB.Rebind(query), afterSeq, limit); err != nil {
+		return nil, f.db.handleErr(OutboxTableName, err)
+	}
+	return changes, nil
+}
+
+// Checkpoint returns the sequence number of the last change processed by the consumer,
+// or 0 if the consumer has no checkpoint yet.
+func (f *ChangeFeed) Checkpoint(ctx context.Context, consumer string) (int64, error) {
+	ctx, cancel := f.db.withTimeout(ctx)
+	defer cancel()
+
+	var seq int64
+	query := f.db.DB.Rebind(fmt.Sprintf(`SELECT %sseq FROM %s WHERE consumer = ?`, f.db.selectHint(ctx), OutboxCheckpointTableName))
+	err := f.db.DB.GetContext(ctx, &seq, query, consumer)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, f.db.handleErr(OutboxCheckpointTableName, err)
+	}
+	return seq, nil
+}
+
+// SaveCheckpoint records seq as the sequence number of the last change processed by the consumer.
+func (f *ChangeFeed) SaveCheckpoint(ctx context.Context, consumer string, seq int64) error {
+	ctx, cancel := f.db.withTimeout(ctx)
+	defer cancel()
+
+	var query string
+	switch f.db.Dialect() {
+	case DialectMySQL:
+		query = `INSERT INTO %s (consumer, seq) VALUES (?, ?) ON DUPLICATE KEY UPDATE seq = VALUES(seq)`
+	case DialectSQLite:
+		query = `INSERT INTO %s (consumer, seq) VALUES (?, ?) ON CONFLICT (consumer) DO UPDATE SET seq = excluded.seq`
+	default:
+		return fmt.Errorf("change feed is not supported for driver '%s': %w", f.db.DB.DriverName(), ErrInternal)
+	}
+	_, err := f.db.DB.ExecContext(ctx, f.db.DB.Rebind(fmt.Sprintf(query, OutboxCheckpointTableName)), consumer, seq)
+	return f.db.handleErr(OutboxCheckpointTableName, err)
+}
+
+// Relay dispatches up to limit changes after the checkpoint of the consumer, in order, saving the
+// checkpoint after each dispatched change. It stops at the first change which fails to dispatch,
+// which is dispatched again by the next Relay: delivery is at least once. Relay returns the number
+// of changes dispatched.
+func (f *ChangeFeed) Relay(ctx context.Context, consumer string, dispatcher Dispatcher, limit int) (int, error) {
+	afterSeq, err := f.Checkpoint(ctx, consumer)
+	if err != nil {
+		return 0, err
+	}
+	changes, err := f.Next(ctx, afterSeq, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, change := range changes {
+		if err := dispatcher.Dispatch(ctx, change); err != nil {
+			return i, fmt.Errorf("failed to dispatch change %d of table '%s': %w", change.Seq, change.Table, err)
+		}
+		if err := f.SaveCheckpoint(ctx, consumer, change.Seq); err != nil {
+			return i, err
+		}
+	}
+	return len(changes), nil
+}
+
+// Dispatcher delivers changes read from the change feed, e.g. to in-process subscribers or to a message bus.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, change Change) error
+}
+
+// DispatcherFunc adapts a function to a Dispatcher.
+type DispatcherFunc func(ctx context.Context, change Change) error
+
+func (f DispatcherFunc) Dispatch(ctx context.Context, change Change) error {
+	return f(ctx, change)
+}
+
+// Subscribers is a Dispatcher fanning out changes to in-process subscribers.
+type Subscribers struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+}
+
+type subscription struct {
+	tableName  string
+	dispatcher Dispatcher
+}
+
+// Subscribe registers a dispatcher for the changes of the table, or of every table if tableName is empty.
+func (s *Subscribers) Subscribe(tableName string, dispatcher Dispatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions = append(s.subscriptions, subscription{tableName: tableName, dispatcher: dispatcher})
+}
+
+// Dispatch delivers the change to the subscribers of its table in the order they subscribed,
+// stopping at the first which fails.
+func (s *Subscribers) Dispatch(ctx context.Context, change Change) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sub := range s.subscriptions {
+		if sub.tableName != "" && sub.tableName != change.Table {
+			continue
+		}
+		if err := sub.dispatcher.Dispatch(ctx, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package simplesql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+func TestOutbox(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler), simplesql.WithOutbox("ledger"))
+	err = simplesqlDb.ApplyMigrations(append(ledgerTableMigrations, simplesql.OutboxMigrations(simplesqlDb.Dialect(), 10)...))
+	require.NoError(t, err)
+
+	ctx := simplesql.WithActor(context.Background(), "alice")
+	feed := simplesql.NewChangeFeed(simplesqlDb)
+
+	t.Run("Mutations", func(t *testing.T) {
+		for _, entryID := range []string{"entry0", "entry1", "entry2"} {
+			err := simplesqlDb.Insert(ctx, db, "ledger", LedgerRow{AccountID: "acct0", EntryID: entryID, Version: 1, Amount: 10})
+			require.NoError(t, err)
+		}
+
+		amount := int64(20)
+		err := simplesqlDb.Update(ctx, db, "ledger", LedgerTableUpdateKey{AccountID: "acct0", EntryID: "entry1", Version: 1}, LedgerTableUpdateFields{
+			Amount: &amount,
+		})
+		require.NoError(t, err)
+
+		// A rolled back mutation writes nothing to the outbox.
+		tx, err := db.Beginx()
+		require.NoError(t, err)
+		err = simplesqlDb.Insert(ctx, tx, "ledger", LedgerRow{AccountID: "acct0", EntryID: "entry3", Version: 1, Amount: 10})
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		err = simplesqlDb.Delete(ctx, db, "ledger", LedgerTableKeys{AccountID: "acct0", EntryID: "entry0"})
+		require.NoError(t, err)
+	})
+
+	t.Run("Next", func(t *testing.T) {
+		changes, err := feed.Next(context.Background(), 0, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 5)
+		for i, change := range changes {
+			require.Equal(t, int64(i+1), change.Seq)
+			require.Equal(t, "ledger", change.Table)
+			require.Equal(t, "alice", change.Actor)
+		}
+		require.Equal(t, simplesql.OperationInsert, changes[0].Operation)
+		require.JSONEq(t, `{"account_id":"acct0","entry_id":"entry0"}`, string(changes[0].Key))
+		require.Equal(t, simplesql.OperationUpdate, changes[3].Operation)
+		require.JSONEq(t, `{"account_id":"acct0","entry_id":"entry1"}`, string(changes[3].Key))
+		require.Equal(t, simplesql.OperationDelete, changes[4].Operation)
+		require.Nil(t, changes[4].After)
+
+		var row LedgerRow
+		require.NoError(t, simplesql.DecodeImage(changes[3].After, &row))
+		require.Equal(t, int64(20), row.Amount)
+		require.Equal(t, uint64(2), row.Version)
+
+		changes, err = feed.Next(context.Background(), 3, 1)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, int64(4), changes[0].Seq)
+	})
+
+	t.Run("Relay", func(t *testing.T) {
+		var ledgerSeqs, allSeqs []int64
+		bus := make(chan simplesql.Change, 10)
+		failing := true
+
+		subscribers := &simplesql.Subscribers{}
+		subscribers.Subscribe("ledger", simplesql.DispatcherFunc(func(ctx context.Context, change simplesql.Change) error {
+			ledgerSeqs = append(ledgerSeqs, change.Seq)
+			return nil
+		}))
+		subscribers.Subscribe("", simplesql.DispatcherFunc(func(ctx context.Context, change simplesql.Change) error {
+			if change.Seq == 3 && failing {
+				return errors.New("bus unavailable")
+			}
+			allSeqs = append(allSeqs, change.Seq)
+			bus <- change
+			return nil
+		}))
+		subscribers.Subscribe("account", simplesql.DispatcherFunc(func(ctx context.Context, change simplesql.Change) error {
+			t.Fatalf("unexpected change of table %s", change.Table)
+			return nil
+		}))
+
+		// The relay stops at the failed change and resumes from it.
+		n, err := feed.Relay(context.Background(), "consumer0", subscribers, 10)
+		require.Error(t, err)
+		require.Equal(t, 2, n)
+		checkpoint, err := feed.Checkpoint(context.Background(), "consumer0")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), checkpoint)
+
+		failing = false
+		n, err = feed.Relay(context.Background(), "consumer0", subscribers, 2)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		n, err = feed.Relay(context.Background(), "consumer0", subscribers, 2)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		n, err = feed.Relay(context.Background(), "consumer0", subscribers, 2)
+		require.NoError(t, err)
+		require.Equal(t, 0, n)
+
+		// Delivery is at least once: the change which failed on a subscriber is dispatched again to every subscriber.
+		require.Equal(t, []int64{1, 2, 3, 3, 4, 5}, ledgerSeqs)
+		require.Equal(t, []int64{1, 2, 3, 4, 5}, allSeqs)
+		require.Len(t, bus, 5)
+
+		// Consumers keep their own checkpoints.
+		checkpoint, err = feed.Checkpoint(context.Background(), "consumer1")
+		require.NoError(t, err)
+		require.Equal(t, int64(0), checkpoint)
+		checkpoint, err = feed.Checkpoint(context.Background(), "consumer0")
+		require.NoError(t, err)
+		require.Equal(t, int64(5), checkpoint)
+	})
+}
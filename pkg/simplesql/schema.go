@@ -0,0 +1,336 @@
+package simplesql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ColumnDiffKind describes how a column in the database differs from the row struct.
+type ColumnDiffKind string
+
+const (
+	// ColumnMissing means the row struct has a field for a column which the table does not have.
+	ColumnMissing ColumnDiffKind = "missing"
+	// ColumnUnmapped means the table has a column which no field of the row struct maps to.
+	ColumnUnmapped ColumnDiffKind = "unmapped"
+	// ColumnNullability means the field and the column disagree on whether NULL is allowed.
+	ColumnNullability ColumnDiffKind = "nullability"
+	// ColumnType means the Go type of the field cannot hold the column's SQL type.
+	ColumnType ColumnDiffKind = "type"
+)
+
+// ColumnDiff is a single difference between a table column and a row struct field.
+type ColumnDiff struct {
+	Column   string
+	Field    string
+	Kind     ColumnDiffKind
+	Expected string
+	Actual   string
+}
+
+func (c ColumnDiff) String() string {
+	switch c.Kind {
+	case ColumnMissing:
+		return fmt.Sprintf("column '%s' (field %s) does not exist in the table", c.Column, c.Field)
+	case ColumnUnmapped:
+		return fmt.Sprintf("column '%s' is not mapped to any field", c.Column)
+	default:
+		return fmt.Sprintf("column '%s' (field %s) %s mismatch: expected %s, got %s",
+			c.Column, c.Field, c.Kind, c.Expected, c.Actual)
+	}
+}
+
+// SchemaDiff is the result of comparing a table with the row struct which maps to it.
+type SchemaDiff struct {
+	Table   string
+	Columns []ColumnDiff
+}
+
+// HasDrift returns true if the table and the row struct do not match.
+func (s SchemaDiff) HasDrift() bool {
+	return len(s.Columns) > 0
+}
+
+// Err returns nil if there is no drift, otherwise an error describing every difference.
+func (s SchemaDiff) Err() error {
+	if !s.HasDrift() {
+		return nil
+	}
+	return fmt.Errorf("table '%s': %s: %w", s.Table, s.String(), ErrSchemaMismatch)
+}
+
+func (s SchemaDiff) String() string {
+	diffs := make([]string, 0, len(s.Columns))
+	for _, c := range s.Columns {
+		diffs = append(diffs, c.String())
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// tableColumn is a column as reported by the database.
+type tableColumn struct {
+	Name     string `db:"name"`
+	Type     string `db:"type"`
+	Nullable bool   `db:"nullable"`
+}
+
+// VerifySchema introspects the table and compares its columns with the db tags of the row struct.
+// row is a value of, a pointer to, or a slice of the row struct (e.g. ClusterRow{}).
+// The returned error is only set if the schema could not be read; drift is reported in the SchemaDiff.
+func VerifySchema(ctx context.Context, db *sqlx.DB, tableName string, row interface{}) (SchemaDiff, error) {
+	columns, err := getTableColumns(ctx, db, tableName)
+	if err != nil {
+		return SchemaDiff{}, err
+	}
+	if len(columns) == 0 {
+		return SchemaDiff{}, fmt.Errorf("table '%s' does not exist: %w", tableName, ErrSchemaMismatch)
+	}
+
+	columnsByName := make(map[string]tableColumn, len(columns))
+	for _, c := range columns {
+		columnsByName[strings.ToLower(c.Name)] = c
+	}
+
+	diff := SchemaDiff{Table: tableName}
+	mapped := map[string]bool{}
+
+	t := indirectType(reflect.TypeOf(row))
+	fields, err := dbFields(t)
+	if err != nil {
+		return SchemaDiff{}, err
+	}
+	for _, field := range fields {
+		dbTag := field.Tag.Get("db")
+		mapped[strings.ToLower(dbTag)] = true
+
+		column, ok := columnsByName[strings.ToLower(dbTag)]
+		if !ok {
+			diff.Columns = append(diff.Columns, ColumnDiff{Column: dbTag, Field: field.Name, Kind: ColumnMissing})
+			continue
+		}
+
+		goKind, nullable, known := fieldColumnKind(field)
+		if !known {
+			// Custom scanners can map to anything; there is nothing to compare against.
+			continue
+		}
+		// A []byte field holds NULL as a nil slice, so either nullability is acceptable.
+		if goKind != kindBlob && column.Nullable != nullable {
+			diff.Columns = append(diff.Columns, ColumnDiff{
+				Column:   dbTag,
+				Field:    field.Name,
+				Kind:     ColumnNullability,
+				Expected: nullabilityName(nullable),
+				Actual:   nullabilityName(column.Nullable),
+			})
+		}
+		if dbKind := sqlColumnKind(column.Type); !kindsCompatible(goKind, dbKind) {
+			diff.Columns = append(diff.Columns, ColumnDiff{
+				Column:   dbTag,
+				Field:    field.Name,
+				Kind:     ColumnType,
+				Expected: goKind.String(),
+				Actual:   column.Type,
+			})
+		}
+	}
+
+	for _, c := range columns {
+		if !mapped[strings.ToLower(c.Name)] {
+			diff.Columns = append(diff.Columns, ColumnDiff{Column: c.Name, Kind: ColumnUnmapped})
+		}
+	}
+
+	return diff, nil
+}
+
+// VerifySchema compares the table with the row struct. See VerifySchema.
+func (d *Database) VerifySchema(ctx context.Context, tableName string, row interface{}) (SchemaDiff, error) {
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	return VerifySchema(ctx, d.DB, tableName, row)
+}
+
+func getTableColumns(ctx context.Context, db *sqlx.DB, tableName string) ([]tableColumn, error) {
+	var query string
+	switch dialectOf(db) {
+	case DialectSQLite:
+		// Primary key columns are reported nullable unless declared NOT NULL, yet never hold NULL.
+		query = `SELECT name, type, "notnull" = 0 AND pk = 0 AS nullable FROM pragma_table_info(?) ORDER BY cid`
+	case DialectMySQL:
+		query = `
+			SELECT COLUMN_NAME AS name, DATA_TYPE AS type, IS_NULLABLE = 'YES' AS nullable
+			FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+			ORDER BY ORDINAL_POSITION
+		`
+	default:
+		return nil, fmt.Errorf("schema introspection is not supported for driver '%s': %w", db.DriverName(), ErrInternal)
+	}
+
+	var columns []tableColumn
+	if err := db.SelectContext(ctx, &columns, query, tableName); err != nil {
+		return nil, fmt.Errorf("failed to read columns of table '%s': %s: %w", tableName, err.Error(), ErrInternal)
+	}
+	return columns, nil
+}
+
+// indirectType unwraps pointers and slices to reach the row struct type.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+// columnKind is the broad category of a column type, used to compare Go and SQL types.
+type columnKind int
+
+const (
+	kindUnknown columnKind = iota
+	kindBool
+	kindInteger
+	kindReal
+	kindText
+	kindBlob
+	kindTime
+)
+
+func (k columnKind) String() string {
+	switch k {
+	case kindBool:
+		return "bool"
+	case kindInteger:
+		return "integer"
+	case kindReal:
+		return "real"
+	case kindText:
+		return "text"
+	case kindBlob:
+		return "blob"
+	case kindTime:
+		return "time"
+	}
+	return "unknown"
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+	nullTypeKinds = map[reflect.Type]columnKind{
+		reflect.TypeOf(sql.NullBool{}):    kindBool,
+		reflect.TypeOf(sql.NullByte{}):    kindInteger,
+		reflect.TypeOf(sql.NullInt16{}):   kindInteger,
+		reflect.TypeOf(sql.NullInt32{}):   kindInteger,
+		reflect.TypeOf(sql.NullInt64{}):   kindInteger,
+		reflect.TypeOf(sql.NullFloat64{}): kindReal,
+		reflect.TypeOf(sql.NullString{}):  kindText,
+		reflect.TypeOf(sql.NullTime{}):    kindTime,
+	}
+)
+
+// fieldColumnKind returns the column kind of a row struct field. See goColumnKind.
+func fieldColumnKind(field reflect.StructField) (kind columnKind, nullable bool, known bool) {
+	if isJSONField(field) {
+		return kindText, field.Type.Kind() == reflect.Ptr, true
+	}
+	return goColumnKind(field.Type)
+}
+
+// goColumnKind returns the column kind a Go type can be scanned from and whether it accepts NULL.
+// known is false for types, such as custom scanners, whose mapping cannot be deduced.
+func goColumnKind(t reflect.Type) (kind columnKind, nullable bool, known bool) {
+	if kind, ok := nullTypeKinds[t]; ok {
+		return kind, true, true
+	}
+	if t.Kind() == reflect.Ptr {
+		kind, _, known := goColumnKind(t.Elem())
+		return kind, true, known
+	}
+	if n, ok := asNullable(reflect.Zero(t)); ok {
+		kind, _, known := goColumnKind(n.elemType())
+		return kind, true, known
+	}
+	if t == timeType {
+		return kindTime, false, true
+	}
+	if reflect.PointerTo(t).Implements(scannerType) {
+		return kindUnknown, false, false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return kindBool, false, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kindInteger, false, true
+	case reflect.Float32, reflect.Float64:
+		return kindReal, false, true
+	case reflect.String:
+		return kindText, false, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return kindBlob, true, true
+		}
+	}
+	return kindUnknown, false, false
+}
+
+// sqlColumnKind classifies a declared column type. It follows SQLite's type affinity rules,
+// which also cover the MySQL data type names.
+func sqlColumnKind(sqlType string) columnKind {
+	t := strings.ToUpper(sqlType)
+	switch {
+	case strings.HasPrefix(t, "BOOL"), t == "BIT":
+		return kindBool
+	case strings.Contains(t, "DATE"), strings.Contains(t, "TIME"):
+		return kindTime
+	case strings.Contains(t, "INT"):
+		return kindInteger
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"),
+		strings.Contains(t, "JSON"), strings.Contains(t, "ENUM"):
+		return kindText
+	case t == "", strings.Contains(t, "BLOB"), strings.Contains(t, "BINARY"):
+		return kindBlob
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"),
+		strings.Contains(t, "DEC"), strings.Contains(t, "NUMERIC"):
+		return kindReal
+	}
+	return kindUnknown
+}
+
+// kindsCompatible returns true if a column of kind dbKind can be scanned into a field of kind goKind.
+func kindsCompatible(goKind, dbKind columnKind) bool {
+	if goKind == kindUnknown || dbKind == kindUnknown || goKind == dbKind {
+		return true
+	}
+	switch goKind {
+	case kindBool:
+		// Booleans are stored as TINYINT(1) in MySQL and INTEGER in SQLite.
+		return dbKind == kindInteger
+	case kindInteger:
+		return dbKind == kindBool
+	case kindReal:
+		return dbKind == kindInteger
+	case kindText:
+		return dbKind == kindBlob || dbKind == kindTime
+	case kindBlob:
+		return dbKind == kindText
+	}
+	return false
+}
+
+func nullabilityName(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
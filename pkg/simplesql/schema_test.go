@@ -0,0 +1,72 @@
+package simplesql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+func TestVerifySchema(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db)
+	err = simplesqlDb.ApplyMigrations(clusterTableMigrations)
+	require.NoError(t, err)
+
+	t.Run("Matching row", func(t *testing.T) {
+		diff, err := simplesqlDb.VerifySchema(context.Background(), clusterTableName, ClusterRow{})
+		require.NoError(t, err)
+		require.False(t, diff.HasDrift(), diff.String())
+		require.NoError(t, diff.Err())
+	})
+
+	t.Run("Drifted row", func(t *testing.T) {
+		type driftedClusterRow struct {
+			ID            string  `db:"id"`
+			Version       string  `db:"version"`
+			CreatedAt     int64   `db:"created_at"`
+			LastUpdatedAt int64   `db:"last_updated_at"`
+			DeletedAt     *int64  `db:"deleted_at"`
+			Name          string  `db:"name"`
+			State         string  `db:"state"`
+			Message       string  `db:"message"`
+			Region        string  `db:"region"`
+			Ignored       float64 `db:"-"`
+		}
+
+		diff, err := simplesql.VerifySchema(context.Background(), db, clusterTableName, &driftedClusterRow{})
+		require.NoError(t, err)
+		require.True(t, diff.HasDrift())
+		require.ErrorIs(t, diff.Err(), simplesql.ErrSchemaMismatch)
+		require.ElementsMatch(t, []simplesql.ColumnDiff{
+			{Column: "version", Field: "Version", Kind: simplesql.ColumnType, Expected: "text", Actual: "BIGINT"},
+			{Column: "deleted_at", Field: "DeletedAt", Kind: simplesql.ColumnNullability, Expected: "NULL", Actual: "NOT NULL"},
+			{Column: "region", Field: "Region", Kind: simplesql.ColumnMissing},
+			{Column: "cluster_manager_id", Kind: simplesql.ColumnUnmapped},
+		}, diff.Columns)
+	})
+
+	t.Run("Primary key without NOT NULL", func(t *testing.T) {
+		_, err := db.Exec(`CREATE TABLE label (id TEXT PRIMARY KEY, name TEXT NOT NULL)`)
+		require.NoError(t, err)
+		type labelRow struct {
+			ID   string `db:"id"`
+			Name string `db:"name"`
+		}
+
+		diff, err := simplesql.VerifySchema(context.Background(), db, "label", labelRow{})
+		require.NoError(t, err)
+		require.False(t, diff.HasDrift(), diff.String())
+	})
+
+	t.Run("Missing table", func(t *testing.T) {
+		_, err := simplesqlDb.VerifySchema(context.Background(), "does_not_exist", ClusterRow{})
+		require.ErrorIs(t, err, simplesql.ErrSchemaMismatch)
+	})
+}
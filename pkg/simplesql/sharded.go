@@ -0,0 +1,251 @@
+package simplesql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrMissingShardKey = errors.New("shard key missing")
+	ErrCrossShard      = errors.New("cross-shard transaction")
+)
+
+// ShardFunc returns the shard, in [0, shards), holding the rows of the shard key value.
+type ShardFunc func(shardKey interface{}, shards int) int
+
+// HashShard is the default ShardFunc, an FNV-1a hash of the shard key value.
+func HashShard(shardKey interface{}, shards int) int {
+	h := fnv.New32a()
+	fmt.Fprint(h, shardKey)
+	return int(h.Sum32() % uint32(shards))
+}
+
+// ShardedDatabase spreads tables over several databases. Each row lives in the shard of the
+// value of its shard key field, tagged `orm:"shard_key"`. Keys of Get, Update and Delete must
+// set the shard key. List filters setting it query a single shard, others query every shard
+// and merge the rows, see List.
+type ShardedDatabase struct {
+	shards    []Database
+	shardFunc ShardFunc
+}
+
+type ShardOption func(*ShardedDatabase)
+
+// WithShardFunc replaces HashShard to route shard keys. Changing the routing of existing rows
+// requires moving them.
+func WithShardFunc(shardFunc ShardFunc) ShardOption {
+	return func(s *ShardedDatabase) {
+		s.shardFunc = shardFunc
+	}
+}
+
+func NewShardedDatabase(shards []Database, opts ...ShardOption) *ShardedDatabase {
+	s := &ShardedDatabase{
+		shards:    shards,
+		shardFunc: HashShard,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Shards returns the databases of the shards.
+func (s *ShardedDatabase) Shards() []Database {
+	return s.shards
+}
+
+// ShardTx is a transaction of a single shard. Operations given a ShardTx fail with ErrCrossShard
+// when their shard key routes to another shard.
+type ShardTx struct {
+	*sqlx.Tx
+	shard int
+}
+
+// Shard returns the shard of the transaction.
+func (t *ShardTx) Shard() int {
+	return t.shard
+}
+
+// BeginTx starts a transaction on the shard of the shard key value.
+func (s *ShardedDatabase) BeginTx(ctx context.Context, shardKey interface{}, opts *sql.TxOptions) (*ShardTx, error) {
+	shard := s.shardFunc(shardKey, len(s.shards))
+	tx, err := s.shards[shard].DB.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, s.shards[shard].handleErr("", err)
+	}
+	return &ShardTx{Tx: tx, shard: shard}, nil
+}
+
+// ApplyMigrations applies the migrations to every shard.
+func (s *ShardedDatabase) ApplyMigrations(ctx context.Context, schemaMigrations []Migration) error {
+	for i := range s.shards {
+		if err := s.shards[i].ApplyMigrationsContext(ctx, schemaMigrations); err != nil {
+			return fmt.Errorf("failed to migrate shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Health checks every shard.
+func (s *ShardedDatabase) Health(ctx context.Context) error {
+	for i := range s.shards {
+		if err := s.shards[i].Health(ctx); err != nil {
+			return fmt.Errorf("shard %d is unhealthy: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// shardKey returns the value of the shard key field of the struct, if set.
+func shardKey(v interface{}) (interface{}, bool, error) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	fields, err := dbFields(value.Type())
+	if err != nil {
+		return nil, false, err
+	}
+	for _, field := range fields {
+		if !hasORMOption(field, "shard_key") {
+			continue
+		}
+		// Only equality filters route to a shard
+		if _, operation, ok := strings.Cut(field.Tag.Get("db"), ":"); ok && operation != "eq" {
+			return nil, false, nil
+		}
+		fieldValue := value.FieldByIndex(field.Index)
+		if fieldValue.Kind() == reflect.Slice && !fieldValue.Type().Implements(valuerType) {
+			return nil, false, nil
+		}
+		key := orderValue(fieldValue)
+		if key == nil || isEmptyValue(fieldValue) {
+			return nil, false, nil
+		}
+		return key, true, nil
+	}
+	return nil, false, fmt.Errorf("%s has no field tagged orm:\"shard_key\": %w", value.Type().Name(), ErrMissingShardKey)
+}
+
+// route returns the shard of the struct and the execer to run its statement with: the shard
+// database, or the transaction if it belongs to the shard.
+func (s *ShardedDatabase) route(tableName string, v interface{}, execer sqlx.ExecerContext) (int, sqlx.ExecerContext, error) {
+	key, ok, err := shardKey(v)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok {
+		return 0, nil, fmt.Errorf("shard key of table '%s' not set: %w", tableName, ErrMissingShardKey)
+	}
+	shard := s.shardFunc(key, len(s.shards))
+
+	switch e := execer.(type) {
+	case nil, *sqlx.DB:
+		return shard, s.shards[shard].DB, nil
+	case *ShardTx:
+		if e.shard != shard {
+			return 0, nil, fmt.Errorf("table '%s' row of shard %d in transaction of shard %d: %w", tableName, shard, e.shard, ErrCrossShard)
+		}
+		return shard, e.Tx, nil
+	}
+	return 0, nil, fmt.Errorf("sharded tables are written with a *sqlx.DB or a *ShardTx: %w", ErrCrossShard)
+}
+
+// Insert inserts the row into its shard. The execer is nil or a *sqlx.DB to insert outside of
+// a transaction, or a *ShardTx.
+func (s *ShardedDatabase) Insert(ctx context.Context, execer sqlx.ExecerContext, tableName string, row interface{}) error {
+	shard, execer, err := s.route(tableName, row, execer)
+	if err != nil {
+		return err
+	}
+	return s.shards[shard].Insert(ctx, execer, tableName, row)
+}
+
+// Get reads the row of the key from its shard.
+func (s *ShardedDatabase) Get(ctx context.Context, tableName string, key interface{}, row interface{}) error {
+	shard, _, err := s.route(tableName, key, nil)
+	if err != nil {
+		return err
+	}
+	return s.shards[shard].Get(ctx, tableName, key, row)
+}
+
+// Update updates the row of the key in its shard. The execer is as for Insert.
+func (s *ShardedDatabase) Update(
+	ctx context.Context, execer sqlx.ExecerContext, tableName string, key interface{}, fields interface{},
+) error {
+	shard, execer, err := s.route(tableName, key, execer)
+	if err != nil {
+		return err
+	}
+	return s.shards[shard].Update(ctx, execer, tableName, key, fields)
+}
+
+// Delete deletes the rows of the key from its shard. The execer is nil or a *sqlx.DB to delete
+// outside of a transaction, or a *ShardTx.
+func (s *ShardedDatabase) Delete(ctx context.Context, execer sqlx.ExecerContext, tableName string, key interface{}) error {
+	shard, execer, err := s.route(tableName, key, execer)
+	if err != nil {
+		return err
+	}
+	return s.shards[shard].Delete(ctx, execer, tableName, key)
+}
+
+// List lists the rows matching the filters. Filters setting the shard key query its shard only.
+// Other filters query every shard concurrently: the rows of the shards are merged in the order
+// of the OrderBy filter, and cut to the Limit filter. Without OrderBy, rows are merged in shard
+// order.
+func (s *ShardedDatabase) List(ctx context.Context, tableName string, filters interface{}, result interface{}) error {
+	key, ok, err := shardKey(filters)
+	if err != nil && !errors.Is(err, ErrMissingShardKey) {
+		return err
+	}
+	if ok {
+		return s.shards[s.shardFunc(key, len(s.shards))].List(ctx, tableName, filters, result)
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(filters))
+	order, err := listOrder(v, rowElemType(result))
+	if err != nil {
+		return err
+	}
+
+	slice := reflect.ValueOf(result).Elem()
+	shardResults := make([]reflect.Value, len(s.shards))
+	errs := make([]error, len(s.shards))
+	var wg sync.WaitGroup
+	for i := range s.shards {
+		shardResults[i] = reflect.New(slice.Type())
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.shards[i].List(ctx, tableName, filters, shardResults[i].Interface())
+		}(i)
+	}
+	wg.Wait()
+
+	merged := reflect.MakeSlice(slice.Type(), 0, 0)
+	for i, shardResult := range shardResults {
+		if errs[i] != nil {
+			return fmt.Errorf("failed to list shard %d: %w", i, errs[i])
+		}
+		merged = reflect.AppendSlice(merged, shardResult.Elem())
+	}
+	if len(order) > 0 {
+		sort.SliceStable(merged.Interface(), func(i, j int) bool {
+			return compareRows(merged.Index(i), merged.Index(j), order) < 0
+		})
+	}
+	if limit := v.FieldByName("Limit"); limit.IsValid() && limit.Uint() > 0 && uint64(merged.Len()) > limit.Uint() {
+		merged = merged.Slice(0, int(limit.Uint()))
+	}
+	slice.Set(reflect.AppendSlice(slice, merged))
+	return nil
+}
@@ -0,0 +1,186 @@
+package simplesql_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+)
+
+var eventTableMigrations = []simplesql.Migration{
+	{
+		Version: 1,
+		Up: `
+			CREATE TABLE event (
+				account_id VARCHAR(255) NOT NULL,
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				version BIGINT NOT NULL,
+				seq INTEGER NOT NULL
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS event;
+			`,
+	},
+}
+
+type EventRow struct {
+	AccountID string `db:"account_id" orm:"shard_key"`
+	ID        string `db:"id"`
+	Version   uint64 `db:"version"`
+	Seq       int64  `db:"seq"`
+}
+
+type EventTableKeys struct {
+	AccountID *string `db:"account_id" orm:"shard_key"`
+	ID        *string `db:"id"`
+}
+
+type EventTableUpdateKey struct {
+	AccountID string `db:"account_id" orm:"shard_key"`
+	ID        string `db:"id"`
+	Version   uint64 `db:"version"`
+}
+
+type EventTableUpdateFields struct {
+	Seq *int64 `db:"seq"`
+}
+
+type EventTableSelectFilters struct {
+	AccountIDEq *string `db:"account_id:eq" orm:"shard_key"`
+	SeqGte      *int64  `db:"seq:gte"`
+	OrderBy     []string
+	Limit       uint32 `db:"limit"`
+}
+
+func TestShardedDatabase(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var shards []simplesql.Database
+	for i := 0; i < 3; i++ {
+		db, err := simplesql.Open(ctx, simplesql.Config{
+			Dialect: simplesql.DialectSQLite,
+			Path:    filepath.Join(dir, fmt.Sprintf("shard%d.db", i)),
+			WAL:     true,
+		}, simplesql.WithErrHandler(simplesql.SQLiteErrHandler))
+		require.NoError(t, err)
+		defer db.DB.Close()
+		shards = append(shards, db)
+	}
+	sharded := simplesql.NewShardedDatabase(shards)
+	require.NoError(t, sharded.ApplyMigrations(ctx, eventTableMigrations))
+	require.NoError(t, sharded.Health(ctx))
+
+	accounts := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"}
+	for i, account := range accounts {
+		for j := 0; j < 2; j++ {
+			err := sharded.Insert(ctx, nil, "event", EventRow{
+				AccountID: account,
+				ID:        fmt.Sprintf("%s-%d", account, j),
+				Version:   1,
+				Seq:       int64(10*j + i),
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("Routing", func(t *testing.T) {
+		// Each row is stored in the shard of its account only.
+		total, used := 0, 0
+		for i, shard := range sharded.Shards() {
+			var rows []EventRow
+			require.NoError(t, shard.List(ctx, "event", EventTableSelectFilters{}, &rows))
+			for _, row := range rows {
+				require.Equal(t, i, simplesql.HashShard(row.AccountID, 3))
+			}
+			total += len(rows)
+			if len(rows) > 0 {
+				used++
+			}
+		}
+		require.Equal(t, 12, total)
+		require.Greater(t, used, 1)
+
+		var row EventRow
+		err := sharded.Get(ctx, "event", EventTableKeys{AccountID: StringPtr("delta"), ID: StringPtr("delta-1")}, &row)
+		require.NoError(t, err)
+		require.Equal(t, int64(13), row.Seq)
+
+		err = sharded.Get(ctx, "event", EventTableKeys{ID: StringPtr("delta-1")}, &row)
+		require.ErrorIs(t, err, simplesql.ErrMissingShardKey)
+	})
+
+	t.Run("Scatter gather", func(t *testing.T) {
+		var rows []EventRow
+		err := sharded.List(ctx, "event", EventTableSelectFilters{
+			SeqGte:  Int64Ptr(2),
+			OrderBy: []string{"seq DESC"},
+			Limit:   5,
+		}, &rows)
+		require.NoError(t, err)
+		var seqs []int64
+		for _, row := range rows {
+			seqs = append(seqs, row.Seq)
+		}
+		require.Equal(t, []int64{15, 14, 13, 12, 11}, seqs)
+
+		rows = nil
+		err = sharded.List(ctx, "event", EventTableSelectFilters{AccountIDEq: StringPtr("bravo"), OrderBy: []string{"id"}}, &rows)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "bravo-0", rows[0].ID)
+
+		err = sharded.List(ctx, "event", EventTableSelectFilters{OrderBy: []string{"seq; DROP TABLE event"}}, &rows)
+		require.ErrorIs(t, err, simplesql.ErrInternal)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		err := sharded.Update(ctx, nil, "event", EventTableUpdateKey{AccountID: "echo", ID: "echo-0", Version: 1}, EventTableUpdateFields{
+			Seq: Int64Ptr(100),
+		})
+		require.NoError(t, err)
+
+		var row EventRow
+		err = sharded.Get(ctx, "event", EventTableKeys{AccountID: StringPtr("echo"), ID: StringPtr("echo-0")}, &row)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), row.Seq)
+		require.Equal(t, uint64(2), row.Version)
+
+		err = sharded.Delete(ctx, nil, "event", EventTableKeys{AccountID: StringPtr("echo"), ID: StringPtr("echo-0")})
+		require.NoError(t, err)
+		err = sharded.Get(ctx, "event", EventTableKeys{AccountID: StringPtr("echo"), ID: StringPtr("echo-0")}, &row)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	})
+
+	t.Run("Transactions", func(t *testing.T) {
+		tx, err := sharded.BeginTx(ctx, "alpha", nil)
+		require.NoError(t, err)
+		err = sharded.Insert(ctx, tx, "event", EventRow{AccountID: "alpha", ID: "alpha-2", Version: 1, Seq: 20})
+		require.NoError(t, err)
+
+		// Find an account of another shard.
+		other := ""
+		for _, account := range accounts {
+			if simplesql.HashShard(account, 3) != tx.Shard() {
+				other = account
+				break
+			}
+		}
+		require.NotEmpty(t, other)
+		err = sharded.Insert(ctx, tx, "event", EventRow{AccountID: other, ID: other + "-2", Version: 1, Seq: 21})
+		require.ErrorIs(t, err, simplesql.ErrCrossShard)
+		err = sharded.Update(ctx, tx, "event", EventTableUpdateKey{AccountID: other, ID: other + "-0", Version: 1}, EventTableUpdateFields{
+			Seq: Int64Ptr(0),
+		})
+		require.ErrorIs(t, err, simplesql.ErrCrossShard)
+		require.NoError(t, tx.Commit())
+
+		var row EventRow
+		err = sharded.Get(ctx, "event", EventTableKeys{AccountID: StringPtr("alpha"), ID: StringPtr("alpha-2")}, &row)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,93 @@
+package simplesql
+
+import (
+	"reflect"
+	"sync"
+)
+
+// tableRegistry holds the row structs of the tables registered with RegisterTable, from which
+// simplesql derives the columns it maintains, such as the automatic update timestamps, and the
+// tenant column of the tenant scoped tables.
+type tableRegistry struct {
+	mu      sync.RWMutex
+	rows    map[string]reflect.Type
+	tenants map[string]string
+}
+
+func newTableRegistry() *tableRegistry {
+	return &tableRegistry{rows: map[string]reflect.Type{}, tenants: map[string]string{}}
+}
+
+// WithTable registers the row struct of the table, see RegisterTable.
+func WithTable(tableName string, row interface{}) Option {
+	return func(d *Database) {
+		d.RegisterTable(tableName, row)
+	}
+}
+
+// WithTenantTable scopes every query of the table to the tenant of the context, on the column.
+func WithTenantTable(tableName, column string) Option {
+	return func(d *Database) {
+		d.registry().setTenant(tableName, column)
+	}
+}
+
+// RegisterTable registers the row struct of the table. Update and UpdateWhere stamp the
+// auto=update_time columns of the row, whether or not the fields struct declares them, and a row
+// with an `orm:"tenant"` field scopes every query of the table to the tenant of the context,
+// whatever the structs of the query declare. The generated tables register their row struct when
+// created.
+//
+// The registrations are shared with the copies of the Database, which NewDatabase prepares for.
+func (d *Database) RegisterTable(tableName string, row interface{}) {
+	t := indirectType(reflect.TypeOf(row))
+	registry := d.registry()
+	registry.mu.Lock()
+	registry.rows[tableName] = t
+	registry.mu.Unlock()
+
+	// The fields error is returned by the operations on the table.
+	fields, _ := dbFields(t)
+	for _, field := range fields {
+		if isTenantField(field) {
+			registry.setTenant(tableName, field.Tag.Get("db"))
+		}
+	}
+}
+
+// registry returns the registry of the tables, allocated on first use for the Databases which
+// were not created by NewDatabase.
+func (d *Database) registry() *tableRegistry {
+	if d.tables == nil {
+		d.tables = newTableRegistry()
+	}
+	return d.tables
+}
+
+func (r *tableRegistry) setTenant(tableName, column string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tenants[tableName] = column
+}
+
+// rowType returns the row struct registered for the table.
+func (d *Database) rowType(tableName string) (reflect.Type, bool) {
+	if d.tables == nil {
+		return nil, false
+	}
+	d.tables.mu.RLock()
+	defer d.tables.mu.RUnlock()
+	t, ok := d.tables.rows[tableName]
+	return t, ok
+}
+
+// registeredTenant returns the tenant column registered for the table.
+func (d *Database) registeredTenant(tableName string) (string, bool) {
+	if d.tables == nil {
+		return "", false
+	}
+	d.tables.mu.RLock()
+	defer d.tables.mu.RUnlock()
+	column, ok := d.tables.tenants[tableName]
+	return column, ok
+}
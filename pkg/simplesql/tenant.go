@@ -0,0 +1,75 @@
+package simplesql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the tenant the queries of tenant scoped tables are restricted to.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant carried by the context.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// isTenantField returns true if the field declares the tenant column with `orm:"tenant"`.
+// Key and filter structs may declare it with a blank field: `_ struct{} db:"tenant_id" orm:"tenant"`.
+func isTenantField(field reflect.StructField) bool {
+	return hasORMOption(field, "tenant")
+}
+
+// tenantColumn returns the tenant column of the table, registered with the table or declared by
+// a field of one of the structs. Registered tables are scoped whatever the structs declare.
+func (d *Database) tenantColumn(tableName string, structs ...interface{}) (string, bool, error) {
+	if column, ok := d.registeredTenant(tableName); ok {
+		return column, true, nil
+	}
+	for _, s := range structs {
+		t := indirectType(reflect.TypeOf(s))
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+		fields, err := dbFields(t)
+		if err != nil {
+			return "", false, err
+		}
+		for _, field := range fields {
+			if isTenantField(field) {
+				return field.Tag.Get("db"), true, nil
+			}
+		}
+	}
+	return "", false, nil
+}
+
+// tenantScope returns the tenant column of the table and the tenant of the context. scoped is false
+// if the table is not tenant scoped. An error is returned if the table is scoped and the context
+// lacks a tenant.
+func (d *Database) tenantScope(ctx context.Context, tableName string, structs ...interface{}) (column, tenantID string, scoped bool, err error) {
+	column, scoped, err = d.tenantColumn(tableName, structs...)
+	if err != nil || !scoped {
+		return "", "", false, err
+	}
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", "", false, fmt.Errorf("table '%s' is scoped to tenants: %w", tableName, ErrMissingTenant)
+	}
+	return column, tenantID, true, nil
+}
+
+// tenantWhere returns the condition restricting the rows of the table to the tenant of the context,
+// prefixed with AND, along with its positional parameter.
+func (d *Database) tenantWhere(ctx context.Context, tableName string, structs ...interface{}) (string, []interface{}, error) {
+	column, tenantID, scoped, err := d.tenantScope(ctx, tableName, structs...)
+	if err != nil || !scoped {
+		return "", nil, err
+	}
+	return fmt.Sprintf(" AND %s = ?", column), []interface{}{tenantID}, nil
+}
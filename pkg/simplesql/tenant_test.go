@@ -0,0 +1,241 @@
+package simplesql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+var documentTableMigrations = []simplesql.Migration{
+	{
+		Version: 1,
+		Up: `
+			CREATE TABLE document (
+				tenant_id VARCHAR(255) NOT NULL,
+				id VARCHAR(255) NOT NULL,
+				title VARCHAR(255) NOT NULL,
+				PRIMARY KEY (tenant_id, id),
+				UNIQUE (tenant_id, title)
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS document;
+			`,
+	},
+	{
+		Version: 2,
+		Up: `
+			CREATE TABLE document_history (
+				seq INTEGER PRIMARY KEY AUTOINCREMENT,
+				tenant_id VARCHAR(255) NOT NULL,
+				id VARCHAR(255) NOT NULL,
+				operation VARCHAR(16) NOT NULL,
+				actor VARCHAR(255) NOT NULL,
+				changed_at DATETIME NOT NULL,
+				before_image TEXT,
+				after_image TEXT
+			);
+		`,
+		Down: `
+				DROP TABLE IF EXISTS document_history;
+			`,
+	},
+}
+
+type DocumentRow struct {
+	TenantID string `db:"tenant_id" orm:"tenant"`
+	ID       string `db:"id"`
+	Title    string `db:"title"`
+}
+
+type DocumentTableKeys struct {
+	_  struct{} `db:"tenant_id" orm:"tenant"`
+	ID *string  `db:"id"`
+}
+
+type DocumentTableUpdateFields struct {
+	Title *string `db:"title"`
+}
+
+type DocumentTableSelectFilters struct {
+	_       struct{} `db:"tenant_id" orm:"tenant"`
+	TitleEq *string  `db:"title:eq"`
+	Limit   uint32   `db:"limit"`
+}
+
+// UnscopedDocumentKeys does not declare the tenant column.
+type UnscopedDocumentKeys struct {
+	ID *string `db:"id"`
+}
+
+func TestTenantScope(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(
+		db, simplesql.WithErrHandler(simplesql.SQLiteErrHandler), simplesql.WithTable("document", DocumentRow{}),
+		simplesql.WithAudit("document"),
+	)
+	err = simplesqlDb.ApplyMigrations(documentTableMigrations)
+	require.NoError(t, err)
+
+	tenantA := simplesql.WithTenant(context.Background(), "tenantA")
+	tenantB := simplesql.WithTenant(context.Background(), "tenantB")
+
+	t.Run("Insert", func(t *testing.T) {
+		err := simplesqlDb.Insert(tenantA, db, "document", DocumentRow{ID: "doc0", Title: "plan"})
+		require.NoError(t, err)
+		err = simplesqlDb.Insert(tenantB, db, "document", DocumentRow{ID: "doc1", Title: "plan"})
+		require.NoError(t, err)
+		err = simplesqlDb.Insert(tenantA, db, "document", DocumentRow{TenantID: "tenantA", ID: "doc2", Title: "notes"})
+		require.NoError(t, err)
+
+		// The unique key is per tenant.
+		err = simplesqlDb.Insert(tenantA, db, "document", DocumentRow{ID: "doc3", Title: "plan"})
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+		err = simplesqlDb.Insert(tenantA, db, "document", DocumentRow{TenantID: "tenantB", ID: "doc3", Title: "draft"})
+		require.ErrorIs(t, err, simplesql.ErrTenantMismatch)
+
+		err = simplesqlDb.Insert(context.Background(), db, "document", DocumentRow{ID: "doc3", Title: "draft"})
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		var doc DocumentRow
+		err := simplesqlDb.Get(tenantA, "document", DocumentTableKeys{ID: StringPtr("doc0")}, &doc)
+		require.NoError(t, err)
+		require.Equal(t, DocumentRow{TenantID: "tenantA", ID: "doc0", Title: "plan"}, doc)
+
+		err = simplesqlDb.Get(tenantB, "document", DocumentTableKeys{ID: StringPtr("doc0")}, &doc)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+		// The registered table is scoped when queried with structs which do not declare the tenant column.
+		var unscoped struct {
+			ID string `db:"id"`
+		}
+		err = simplesqlDb.Get(tenantB, "document", UnscopedDocumentKeys{ID: StringPtr("doc0")}, &unscoped)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+
+		err = simplesqlDb.Get(context.Background(), "document", UnscopedDocumentKeys{ID: StringPtr("doc0")}, &unscoped)
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var docs []DocumentRow
+		err := simplesqlDb.List(tenantA, "document", DocumentTableSelectFilters{}, &docs)
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		for _, doc := range docs {
+			require.Equal(t, "tenantA", doc.TenantID)
+		}
+
+		docs = nil
+		err = simplesqlDb.List(tenantB, "document", DocumentTableSelectFilters{TitleEq: StringPtr("plan")}, &docs)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		require.Equal(t, "doc1", docs[0].ID)
+
+		err = simplesqlDb.List(context.Background(), "document", DocumentTableSelectFilters{}, &docs)
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		// A row of another tenant is not updated.
+		err := simplesqlDb.Update(tenantB, db, "document", DocumentTableKeys{ID: StringPtr("doc0")}, DocumentTableUpdateFields{
+			Title: StringPtr("stolen"),
+		})
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+
+		err = simplesqlDb.Update(tenantA, db, "document", DocumentTableKeys{ID: StringPtr("doc0")}, DocumentTableUpdateFields{
+			Title: StringPtr("roadmap"),
+		})
+		require.NoError(t, err)
+
+		err = simplesqlDb.Update(context.Background(), db, "document", UnscopedDocumentKeys{ID: StringPtr("doc0")}, DocumentTableUpdateFields{
+			Title: StringPtr("roadmap"),
+		})
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+
+		var doc DocumentRow
+		err = simplesqlDb.Get(tenantA, "document", DocumentTableKeys{ID: StringPtr("doc0")}, &doc)
+		require.NoError(t, err)
+		require.Equal(t, "roadmap", doc.Title)
+	})
+
+	t.Run("History", func(t *testing.T) {
+		// The id is taken by a document of each tenant.
+		err := simplesqlDb.Insert(tenantB, db, "document", DocumentRow{ID: "doc0", Title: "secret"})
+		require.NoError(t, err)
+
+		entries, err := simplesqlDb.History(tenantA, "document", DocumentTableKeys{ID: StringPtr("doc0")})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		for _, entry := range entries {
+			require.NotContains(t, string(entry.After), "secret")
+		}
+
+		entries, err = simplesqlDb.History(tenantB, "document", UnscopedDocumentKeys{ID: StringPtr("doc0")})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Contains(t, string(entries[0].After), "secret")
+
+		_, err = simplesqlDb.History(context.Background(), "document", DocumentTableKeys{ID: StringPtr("doc0")})
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+
+		err = simplesqlDb.Delete(tenantB, db, "document", DocumentTableKeys{ID: StringPtr("doc0")})
+		require.NoError(t, err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := simplesqlDb.Delete(context.Background(), db, "document", UnscopedDocumentKeys{ID: StringPtr("doc1")})
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+
+		// Deleting a row of another tenant is a no-op.
+		err = simplesqlDb.Delete(tenantA, db, "document", UnscopedDocumentKeys{ID: StringPtr("doc1")})
+		require.NoError(t, err)
+
+		var doc DocumentRow
+		err = simplesqlDb.Get(tenantB, "document", DocumentTableKeys{ID: StringPtr("doc1")}, &doc)
+		require.NoError(t, err)
+
+		err = simplesqlDb.Delete(tenantB, db, "document", DocumentTableKeys{ID: StringPtr("doc1")})
+		require.NoError(t, err)
+		err = simplesqlDb.Get(tenantB, "document", DocumentTableKeys{ID: StringPtr("doc1")}, &doc)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+	})
+
+	t.Run("Registered tables", func(t *testing.T) {
+		// A Database which never saw the tenant column scopes the tables registered as tenant scoped.
+		fresh := simplesql.NewDatabase(db, simplesql.WithTenantTable("document", "tenant_id"))
+		var unscoped struct {
+			ID string `db:"id"`
+		}
+		err := fresh.Get(context.Background(), "document", UnscopedDocumentKeys{ID: StringPtr("doc2")}, &unscoped)
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+		err = fresh.Get(tenantB, "document", UnscopedDocumentKeys{ID: StringPtr("doc2")}, &unscoped)
+		require.ErrorIs(t, err, simplesql.ErrRecordNotFound)
+		err = fresh.Update(tenantB, db, "document", UnscopedDocumentKeys{ID: StringPtr("doc2")}, DocumentTableUpdateFields{
+			Title: StringPtr("stolen"),
+		})
+		require.ErrorIs(t, err, simplesql.ErrInsertConflict)
+		err = fresh.Delete(tenantB, db, "document", UnscopedDocumentKeys{ID: StringPtr("doc2")})
+		require.NoError(t, err)
+
+		// So does a Database which was not created by NewDatabase.
+		literal := simplesql.Database{DB: db}
+		literal.RegisterTable("document", DocumentRow{})
+		err = literal.Delete(context.Background(), db, "document", UnscopedDocumentKeys{ID: StringPtr("doc2")})
+		require.ErrorIs(t, err, simplesql.ErrMissingTenant)
+
+		var doc DocumentRow
+		err = simplesqlDb.Get(tenantA, "document", DocumentTableKeys{ID: StringPtr("doc2")}, &doc)
+		require.NoError(t, err)
+		require.Equal(t, "notes", doc.Title)
+	})
+}
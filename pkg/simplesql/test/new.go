@@ -54,7 +54,7 @@ func NewTestMySQLDB() (*sqlx.DB, error) {
 
 	// Step 3: Now connect to the newly created database
 	// This is important: You must connect to the database in which you want to create tables.
-	dsnWithDB := fmt.Sprintf("root:@tcp(127.0.0.1:3306)/%s", dbName)
+	dsnWithDB := fmt.Sprintf("root:@tcp(127.0.0.1:3306)/%s?parseTime=true", dbName)
 	dbWithDB, err := sqlx.Connect("mysql", dsnWithDB)
 	if err != nil {
 		db.Close()
@@ -0,0 +1,43 @@
+package simplesql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithDefaultTimeout bounds each operation by the timeout. A context whose deadline is earlier
+// keeps its deadline. Migrations are not bounded, since they may run for long.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(d *Database) {
+		d.defaultTimeout = timeout
+	}
+}
+
+// withTimeout returns the context of an operation, bounded by the default timeout.
+func (d *Database) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.defaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d.defaultTimeout)
+}
+
+// selectHint returns the optimizer hint which makes MySQL abort a select past the deadline of
+// the context, so the server stops working on it too. Other dialects get no hint.
+func (d *Database) selectHint(ctx context.Context) string {
+	deadline, ok := ctx.Deadline()
+	if !ok || d.Dialect() != DialectMySQL {
+		return ""
+	}
+	ms := time.Until(deadline).Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	return fmt.Sprintf("/*+ MAX_EXECUTION_TIME(%d) */ ", ms)
+}
+
+// isContextErr reports whether err is caused by the deadline or the cancellation of a context.
+func isContextErr(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
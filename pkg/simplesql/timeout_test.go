@@ -0,0 +1,84 @@
+package simplesql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/msanath/gondolf/pkg/simplesql"
+	"github.com/msanath/gondolf/pkg/simplesql/test"
+)
+
+func TestTimeout(t *testing.T) {
+	db, err := test.NewTestSQLiteDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	simplesqlDb := simplesql.NewDatabase(db, simplesql.WithDefaultTimeout(time.Minute))
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("Migrations", func(t *testing.T) {
+		err := simplesqlDb.ApplyMigrationsContext(canceled, teamTableMigrations)
+		require.ErrorIs(t, err, simplesql.ErrTimeout)
+		require.ErrorIs(t, err, context.Canceled)
+
+		err = simplesqlDb.ApplyMigrationsContext(context.Background(), teamTableMigrations)
+		require.NoError(t, err)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		err := simplesqlDb.Insert(canceled, db, "team", TeamRow{ID: "team0", Name: "storage"})
+		var e *simplesql.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, simplesql.KindTimeout, e.Kind)
+		require.Equal(t, "team", e.Table)
+		require.ErrorIs(t, err, simplesql.ErrTimeout)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotErrorIs(t, err, simplesql.ErrInternal)
+
+		var teams []TeamRow
+		err = simplesqlDb.List(canceled, "team", struct{}{}, &teams)
+		require.ErrorIs(t, err, simplesql.ErrTimeout)
+	})
+
+	t.Run("Default timeout", func(t *testing.T) {
+		ctx := context.Background()
+		err := simplesqlDb.Insert(ctx, db, "team", TeamRow{ID: "team0", Name: "storage"})
+		require.NoError(t, err)
+
+		// The default timeout applies to operations of contexts without a deadline.
+		expiring := simplesql.NewDatabase(db, simplesql.WithDefaultTimeout(time.Nanosecond))
+		var team TeamRow
+		err = expiring.Get(ctx, "team", struct {
+			ID string `db:"id"`
+		}{ID: "team0"}, &team)
+		require.ErrorIs(t, err, simplesql.ErrTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		err = simplesqlDb.Get(ctx, "team", struct {
+			ID string `db:"id"`
+		}{ID: "team0"}, &team)
+		require.NoError(t, err)
+		require.Equal(t, "storage", team.Name)
+	})
+
+	t.Run("Select hint", func(t *testing.T) {
+		// The MAX_EXECUTION_TIME hint of MySQL is a comment to SQLite, which lets the
+		// hinted queries run here.
+		hinted := simplesql.NewDatabase(sqlx.NewDb(db.DB, "mysql"), simplesql.WithDefaultTimeout(time.Minute))
+		var teams []TeamRow
+		err := hinted.List(context.Background(), "team", struct{}{}, &teams)
+		require.NoError(t, err)
+		require.Equal(t, []TeamRow{{ID: "team0", Name: "storage"}}, teams)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		require.Regexp(t, `^/\*\+ MAX_EXECUTION_TIME\((59\d{3}|60000)\) \*/ $`, hinted.SelectHint(ctx))
+		require.Empty(t, hinted.SelectHint(context.Background()))
+		require.Empty(t, simplesqlDb.SelectHint(ctx))
+	})
+}
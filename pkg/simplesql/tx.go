@@ -0,0 +1,56 @@
+package simplesql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Tx is a transaction which runs the functions registered with OnCommit once committed, such as
+// the invalidations of the cached tables written in the transaction.
+type Tx struct {
+	*sqlx.Tx
+
+	mu       sync.Mutex
+	onCommit []func()
+}
+
+// CommitNotifier is implemented by the transactions which run functions once committed, like Tx.
+type CommitNotifier interface {
+	OnCommit(fn func())
+}
+
+var _ CommitNotifier = (*Tx)(nil)
+
+// BeginTx starts a Tx.
+func (d *Database) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := d.DB.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, d.handleErr("", err)
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// OnCommit registers fn to run once the transaction is committed. fn is dropped on Rollback.
+func (t *Tx) OnCommit(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onCommit = append(t.onCommit, fn)
+}
+
+// Commit commits the transaction and runs the functions registered with OnCommit, in order.
+func (t *Tx) Commit() error {
+	if err := t.Tx.Commit(); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	onCommit := t.onCommit
+	t.onCommit = nil
+	t.mu.Unlock()
+	for _, fn := range onCommit {
+		fn()
+	}
+	return nil
+}